@@ -0,0 +1,436 @@
+// Package asm is a two pass 6502 assembler. It encodes instructions with
+// the opcode tables of go6502, so every cpu variant gets its own opcodes.
+//
+// A line holds an optional label, then an instruction or a directive, then
+// an optional ; comment:
+//
+//	        .org $0600
+//	count = 10
+//	start:  ldx #count
+//	@loop:  dex
+//	        bne @loop
+//	        .word start, >start, <start
+//
+// Labels starting with @ are local to the last global label. The supported
+// directives are .org, .byte, .word and .include. Operands whose value is
+// known in the first pass and fits in a byte use the zero page modes.
+package asm
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/zehlt/go6502"
+)
+
+// Error reports the file and the line of a faulty statement.
+type Error struct {
+	File string
+	Line int
+	Msg  string
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("%s:%d: %s", e.File, e.Line, e.Msg)
+}
+
+// Segment is a run of bytes starting at Addr.
+type Segment struct {
+	Addr uint16
+	Data []uint8
+}
+
+// Program is the output of the assembler, local labels are listed in
+// Symbols as global@local.
+type Program struct {
+	Segments []Segment
+	Symbols  map[string]uint16
+}
+
+// Load writes the segments of the program to the bus.
+func (p *Program) Load(bus go6502.Bus) {
+	for _, segment := range p.Segments {
+		for i, data := range segment.Data {
+			bus.Write(segment.Addr+uint16(i), data)
+		}
+	}
+}
+
+// Assembler encodes with Opcodes, go6502.Opcodes when nil. ReadFile loads
+// the .include files, os.ReadFile when nil.
+type Assembler struct {
+	Opcodes  map[uint8]go6502.Opcode
+	ReadFile func(name string) ([]byte, error)
+}
+
+// New returns an assembler for the opcodes of the variant.
+func New(variant go6502.Variant) *Assembler {
+	return &Assembler{Opcodes: variant.Opcodes()}
+}
+
+// Assemble assembles source with the NMOS opcodes.
+func Assemble(source string) (*Program, error) {
+	var a Assembler
+	return a.Assemble("source", source)
+}
+
+const maxIncludeDepth = 16
+
+type statement struct {
+	file  string
+	line  int
+	scope string
+
+	label    string
+	constant string // the expression of a name = expr line
+
+	mnemonic string
+	operand  string
+
+	// decided by the first pass
+	opcode go6502.Opcode
+}
+
+type assembly struct {
+	*Assembler
+	index   map[string]map[int]go6502.Opcode
+	symbols map[string]int
+	unknown map[string]bool
+	pc      uint16
+	final   bool
+
+	program *Program
+}
+
+// Assemble assembles source, name is the file name used in errors.
+func (a *Assembler) Assemble(name, source string) (*Program, error) {
+	as := assembly{
+		Assembler: a,
+		index:     indexOpcodes(a.opcodes()),
+		symbols:   map[string]int{},
+		unknown:   map[string]bool{},
+		program:   &Program{Symbols: map[string]uint16{}},
+	}
+
+	statements, err := as.parse(name, source, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, pass := range []bool{false, true} {
+		as.final = pass
+		as.pc = 0
+		for i := range statements {
+			if err := as.assemble(&statements[i]); err != nil {
+				return nil, &Error{File: statements[i].file, Line: statements[i].line, Msg: err.Error()}
+			}
+		}
+	}
+
+	for name, value := range as.symbols {
+		as.program.Symbols[name] = uint16(value)
+	}
+	return as.program, nil
+}
+
+func (a *Assembler) opcodes() map[uint8]go6502.Opcode {
+	if a.Opcodes == nil {
+		return go6502.Opcodes
+	}
+	return a.Opcodes
+}
+
+// indexOpcodes maps mnemonics and modes to opcodes. When several opcodes
+// share both, the lowest one is kept, except for the documented NOP.
+func indexOpcodes(opcodes map[uint8]go6502.Opcode) map[string]map[int]go6502.Opcode {
+	index := map[string]map[int]go6502.Opcode{}
+	for code := 0xFF; code >= 0; code-- {
+		opc, ok := opcodes[uint8(code)]
+		if !ok {
+			continue
+		}
+		if index[opc.Mnemonic] == nil {
+			index[opc.Mnemonic] = map[int]go6502.Opcode{}
+		}
+		index[opc.Mnemonic][baseMode(opc.Mode)] = opc
+	}
+	if nop, ok := opcodes[go6502.NOP_IMP]; ok && nop.Mnemonic == "NOP" {
+		index["NOP"][go6502.Implied] = nop
+	}
+	return index
+}
+
+// the page crossing penalty does not change the encoding
+func baseMode(mode int) int {
+	switch mode {
+	case go6502.AbsoluteX1:
+		return go6502.AbsoluteX
+	case go6502.AbsoluteY1:
+		return go6502.AbsoluteY
+	case go6502.IndirectY1:
+		return go6502.IndirectY
+	}
+	return mode
+}
+
+func (a *assembly) readFile(name string) ([]byte, error) {
+	if a.ReadFile == nil {
+		return os.ReadFile(name)
+	}
+	return a.ReadFile(name)
+}
+
+// parse splits the source into statements, the included files inlined
+func (a *assembly) parse(file, source string, depth int) ([]statement, error) {
+	var statements []statement
+	scope := ""
+	for i, text := range strings.Split(source, "\n") {
+		st := statement{file: file, line: i + 1}
+		fail := func(format string, args ...interface{}) error {
+			return &Error{File: file, Line: i + 1, Msg: fmt.Sprintf(format, args...)}
+		}
+
+		text = strings.TrimSpace(stripComment(text))
+		if name, rest, ok := cutLabel(text); ok {
+			if !strings.HasPrefix(name, "@") {
+				scope = name
+			}
+			st.label = name
+			text = rest
+		} else if name, expr, ok := cutConstant(text); ok {
+			st.label = name
+			st.constant = expr
+			text = ""
+		}
+		st.scope = scope
+
+		if text != "" {
+			end := strings.IndexAny(text, " \t")
+			if end < 0 {
+				end = len(text)
+			}
+			st.mnemonic = strings.ToUpper(text[:end])
+			st.operand = strings.TrimSpace(text[end:])
+		}
+
+		if st.mnemonic == ".INCLUDE" {
+			if depth == maxIncludeDepth {
+				return nil, fail("includes nested too deep")
+			}
+			name := strings.Trim(st.operand, `"`)
+			data, err := a.readFile(name)
+			if err != nil {
+				return nil, fail("%v", err)
+			}
+			included, err := a.parse(name, string(data), depth+1)
+			if err != nil {
+				return nil, err
+			}
+			if st.label != "" {
+				st.mnemonic, st.operand = "", ""
+				statements = append(statements, st)
+			}
+			statements = append(statements, included...)
+			continue
+		}
+		statements = append(statements, st)
+	}
+	return statements, nil
+}
+
+func stripComment(text string) string {
+	quoted := byte(0)
+	for i := 0; i < len(text); i++ {
+		switch c := text[i]; {
+		case quoted != 0:
+			if c == quoted {
+				quoted = 0
+			}
+		case c == '"' || c == '\'':
+			quoted = c
+		case c == ';':
+			return text[:i]
+		}
+	}
+	return text
+}
+
+func cutLabel(text string) (string, string, bool) {
+	end := 0
+	for end < len(text) && isSymbolPart(text[end]) {
+		end++
+	}
+	if end == 0 || !isSymbolStart(text[0]) || end == len(text) || text[end] != ':' {
+		return "", "", false
+	}
+	return text[:end], strings.TrimSpace(text[end+1:]), true
+}
+
+func cutConstant(text string) (string, string, bool) {
+	eq := strings.Index(text, "=")
+	if eq <= 0 {
+		return "", "", false
+	}
+	name := strings.TrimSpace(text[:eq])
+	for i := 0; i < len(name); i++ {
+		if !isSymbolPart(name[i]) {
+			return "", "", false
+		}
+	}
+	if !isSymbolStart(name[0]) {
+		return "", "", false
+	}
+	return name, strings.TrimSpace(text[eq+1:]), true
+}
+
+func (a *assembly) lookup(name string) (int, bool) {
+	value, ok := a.symbols[name]
+	return value, ok
+}
+
+// define sets the symbol of st, a value not known in the first pass leaves
+// it undefined until the second one so that the operands using it stay
+// absolute
+func (a *assembly) define(st *statement, value int, known bool) error {
+	name := qualify(st.label, st.scope)
+	if _, ok := a.symbols[name]; (ok || a.unknown[name]) && !a.final {
+		return fmt.Errorf("%s defined twice", name)
+	}
+	if !known {
+		a.unknown[name] = true
+		return nil
+	}
+	a.symbols[name] = value
+	return nil
+}
+
+// assemble runs one statement of a pass, the first one only computes the
+// addresses, the second one emits the bytes
+func (a *assembly) assemble(st *statement) error {
+	if st.constant != "" {
+		value, known, err := a.eval(st.constant, st)
+		if err != nil {
+			return err
+		}
+		if !known && a.final {
+			return fmt.Errorf("undefined symbol in %q", st.constant)
+		}
+		return a.define(st, value, known)
+	}
+
+	if st.label != "" {
+		if err := a.define(st, int(a.pc), true); err != nil {
+			return err
+		}
+	}
+
+	switch st.mnemonic {
+	case "":
+		return nil
+	case ".ORG":
+		value, known, err := a.eval(st.operand, st)
+		if err != nil {
+			return err
+		}
+		if !known {
+			return fmt.Errorf(".org needs a value known in the first pass")
+		}
+		a.pc = uint16(value)
+		return nil
+	case ".BYTE":
+		return a.data(st, 1)
+	case ".WORD":
+		return a.data(st, 2)
+	}
+	if strings.HasPrefix(st.mnemonic, ".") {
+		return fmt.Errorf("unknown directive %s", st.mnemonic)
+	}
+	return a.instruction(st)
+}
+
+func (a *assembly) emit(data ...uint8) {
+	if a.final {
+		segments := a.program.Segments
+		last := len(segments) - 1
+		if last < 0 || segments[last].Addr+uint16(len(segments[last].Data)) != a.pc {
+			a.program.Segments = append(segments, Segment{Addr: a.pc})
+			last++
+		}
+		a.program.Segments[last].Data = append(a.program.Segments[last].Data, data...)
+	}
+	a.pc += uint16(len(data))
+}
+
+func (a *assembly) data(st *statement, size int) error {
+	for _, arg := range splitOperands(st.operand) {
+		if size == 1 && len(arg) >= 2 && arg[0] == '"' && arg[len(arg)-1] == '"' {
+			a.emit([]uint8(arg[1 : len(arg)-1])...)
+			continue
+		}
+
+		value, err := a.value(arg, st)
+		if err != nil {
+			return err
+		}
+		if size == 1 {
+			if err := checkByte(value); err != nil {
+				return err
+			}
+			a.emit(uint8(value))
+		} else {
+			if value < -0x8000 || value > 0xFFFF {
+				return fmt.Errorf("value %d does not fit in a word", value)
+			}
+			a.emit(uint8(value), uint8(value>>8))
+		}
+	}
+	return nil
+}
+
+// value evaluates an expression that must be defined in the second pass
+func (a *assembly) value(text string, st *statement) (int, error) {
+	value, known, err := a.eval(text, st)
+	if err != nil {
+		return 0, err
+	}
+	if !known && a.final {
+		return 0, fmt.Errorf("undefined symbol in %q", text)
+	}
+	return value, nil
+}
+
+func checkByte(value int) error {
+	if value < -0x80 || value > 0xFF {
+		return fmt.Errorf("value %d does not fit in a byte", value)
+	}
+	return nil
+}
+
+// splitOperands splits on the commas outside of parentheses and quotes
+func splitOperands(text string) []string {
+	var parts []string
+	depth, start := 0, 0
+	quoted := byte(0)
+	for i := 0; i < len(text); i++ {
+		switch c := text[i]; {
+		case quoted != 0:
+			if c == quoted {
+				quoted = 0
+			}
+		case c == '"' || c == '\'':
+			quoted = c
+		case c == '(':
+			depth++
+		case c == ')':
+			depth--
+		case c == ',' && depth == 0:
+			parts = append(parts, strings.TrimSpace(text[start:i]))
+			start = i + 1
+		}
+	}
+	if strings.TrimSpace(text) != "" {
+		parts = append(parts, strings.TrimSpace(text[start:]))
+	}
+	return parts
+}
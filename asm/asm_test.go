@@ -0,0 +1,270 @@
+package asm
+
+import (
+	"errors"
+	"os"
+	"testing"
+
+	"github.com/zehlt/go6502"
+	"github.com/zehlt/go6502/asrt"
+)
+
+func assemble(t *testing.T, source string) *Program {
+	t.Helper()
+	program, err := Assemble(source)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return program
+}
+
+func assertBytes(t *testing.T, got []uint8, expected ...uint8) {
+	t.Helper()
+	asrt.Equal(t, len(got), len(expected))
+	for i := range expected {
+		if i < len(got) && got[i] != expected[i] {
+			t.Errorf("byte %d: got $%02X, expected $%02X", i, got[i], expected[i])
+		}
+	}
+}
+
+func TestAddressingModes(t *testing.T) {
+	program := assemble(t, `
+		lda #$23
+		sta $10
+		ldx $10,y
+		lda $2000,x
+		jmp ($fffc)
+		lda ($40,x)
+		sta ($40),y
+		asl a
+		lsr
+		brk
+	`)
+
+	asrt.Equal(t, len(program.Segments), 1)
+	asrt.Equal(t, program.Segments[0].Addr, uint16(0x0000))
+	assertBytes(t, program.Segments[0].Data,
+		go6502.LDA_IMM, 0x23,
+		go6502.STA_ZER, 0x10,
+		go6502.LDX_ZRY, 0x10,
+		go6502.LDA_ABX, 0x00, 0x20,
+		go6502.JMP_IND, 0xFC, 0xFF,
+		go6502.LDA_IDX, 0x40,
+		go6502.STA_IDY, 0x40,
+		go6502.ASL_ACC,
+		go6502.LSR_ACC,
+		go6502.BRK_IMP,
+	)
+}
+
+func TestLabelsAndBranches(t *testing.T) {
+	program := assemble(t, `
+		.org $0600
+	start:	ldx #3
+	@loop:	dex
+		bne @loop
+		beq done
+		jmp start
+	done:	rts
+	other:
+	@loop:	jmp @loop
+	`)
+
+	assertBytes(t, program.Segments[0].Data,
+		go6502.LDX_IMM, 0x03,
+		go6502.DEX_IMP,
+		go6502.BNE_REL, 0xFD,
+		go6502.BEQ_REL, 0x03,
+		go6502.JMP_ABS, 0x00, 0x06,
+		go6502.RTS_IMP,
+		go6502.JMP_ABS, 0x0B, 0x06,
+	)
+	asrt.Equal(t, program.Symbols["start"], uint16(0x0600))
+	asrt.Equal(t, program.Symbols["start@loop"], uint16(0x0602))
+	asrt.Equal(t, program.Symbols["done"], uint16(0x060A))
+	asrt.Equal(t, program.Symbols["other@loop"], uint16(0x060B))
+}
+
+func TestExpressions(t *testing.T) {
+	program := assemble(t, `
+	base = $1234
+	size = 2 * (3 + 4) - 1
+		.org $0300
+		lda #<base
+		ldx #>base
+		ldy #size
+		lda #%1010 | $01
+		lda #'A' + 1
+		lda #~0 & $0F
+		lda #1 << 4
+		.word *, base >> 4
+	`)
+
+	assertBytes(t, program.Segments[0].Data,
+		go6502.LDA_IMM, 0x34,
+		go6502.LDX_IMM, 0x12,
+		go6502.LDY_IMM, 13,
+		go6502.LDA_IMM, 0x0B,
+		go6502.LDA_IMM, 0x42,
+		go6502.LDA_IMM, 0x0F,
+		go6502.LDA_IMM, 0x10,
+		0x0E, 0x03, 0x23, 0x01,
+	)
+}
+
+func TestZeroPageSelection(t *testing.T) {
+	program := assemble(t, `
+	zp = $80
+		lda zp
+		lda zp+$100
+		lda forward
+		lda zp,y
+		stx zp,y
+	forward = $20
+	`)
+
+	// forward references are not known in the first pass, they stay
+	// absolute, and zero page,Y only exists for LDX and STX
+	assertBytes(t, program.Segments[0].Data,
+		go6502.LDA_ZER, 0x80,
+		go6502.LDA_ABS, 0x80, 0x01,
+		go6502.LDA_ABS, 0x20, 0x00,
+		go6502.LDA_ABY, 0x80, 0x00,
+		go6502.STX_ZRY, 0x80,
+	)
+
+	// a constant defined from a forward label is not known either
+	program = assemble(t, `
+		.org $0600
+	ptr = target
+		lda ptr
+		.org $0700
+	target: .byte 1
+	`)
+	assertBytes(t, program.Segments[0].Data, go6502.LDA_ABS, 0x00, 0x07)
+}
+
+func TestDataDirectives(t *testing.T) {
+	program := assemble(t, `
+		.org $1000
+		.byte 1, $FF, "Hi; there", ','
+		.word $1234, table
+		.org $2000
+	table:	.byte -1
+	`)
+
+	asrt.Equal(t, len(program.Segments), 2)
+	assertBytes(t, program.Segments[0].Data,
+		0x01, 0xFF, 'H', 'i', ';', ' ', 't', 'h', 'e', 'r', 'e', ',',
+		0x34, 0x12, 0x00, 0x20,
+	)
+	asrt.Equal(t, program.Segments[1].Addr, uint16(0x2000))
+	assertBytes(t, program.Segments[1].Data, 0xFF)
+}
+
+func TestInclude(t *testing.T) {
+	files := map[string]string{
+		"vectors.s": "reset = $0400\n\t.include \"nmi.s\"",
+		"nmi.s":     "nmi = $0500",
+	}
+	a := Assembler{ReadFile: func(name string) ([]byte, error) {
+		source, ok := files[name]
+		if !ok {
+			return nil, os.ErrNotExist
+		}
+		return []byte(source), nil
+	}}
+
+	program, err := a.Assemble("main.s", "\t.include \"vectors.s\"\n\t.word reset, nmi")
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertBytes(t, program.Segments[0].Data, 0x00, 0x04, 0x00, 0x05)
+
+	_, err = a.Assemble("main.s", "\tnop\n\t.include \"missing.s\"")
+	var asmErr *Error
+	asrt.True(t, errors.As(err, &asmErr))
+	asrt.Equal(t, asmErr.File, "main.s")
+	asrt.Equal(t, asmErr.Line, 2)
+}
+
+func TestErrors(t *testing.T) {
+	sources := []struct {
+		source string
+		line   int
+	}{
+		{"\tnop\n\tfoo", 2},
+		{"\tlda undefined", 1},
+		{"\n\n\tlda #$100", 3},
+		{"\tlda ($20),x", 1},
+		{"\tbne far\n\t.org $1000\nfar:", 1},
+		{"a:\na:", 2},
+		{"c = later\nc = 1\nlater:", 2},
+		{"\t.org later\nlater:", 1},
+		{"\tlda #(1", 1},
+	}
+
+	for _, s := range sources {
+		_, err := Assemble(s.source)
+		var asmErr *Error
+		if !errors.As(err, &asmErr) {
+			t.Errorf("%q: got %v, expected an assembly error", s.source, err)
+			continue
+		}
+		asrt.Equal(t, asmErr.Line, s.line)
+	}
+}
+
+func TestCmosVariant(t *testing.T) {
+	program, err := New(go6502.WDC65C02).Assemble("cmos.s", `
+		.org $0200
+	loop:	lda ($20)
+		bbr3 $10, loop
+		stz $1234,x
+		inc
+		jmp ($1000,x)
+		stp
+	`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assertBytes(t, program.Segments[0].Data,
+		go6502.LDA_IZP, 0x20,
+		go6502.BBR3_ZRL, 0x10, 0xFB,
+		go6502.STZ_ABX, 0x34, 0x12,
+		go6502.INC_ACC,
+		go6502.JMP_IAX, 0x00, 0x10,
+		go6502.STP_IMP,
+	)
+}
+
+func TestLoadAndRun(t *testing.T) {
+	program, err := New(go6502.WDC65C02).Assemble("hello.s", `
+		.org $0200
+		ldx #0
+	@loop:	lda message,x
+		beq @done
+		sta $0300,x
+		inx
+		bne @loop
+	@done:	stp
+	message: .byte "HELLO", 0
+	`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	memory := go6502.Mem{}
+	program.Load(&memory)
+
+	cpu := go6502.Cpu{Variant: go6502.WDC65C02}
+	cpu.ProgramCounter = 0x0200
+	for !cpu.Halted() {
+		if err := cpu.Step(&memory); err != nil {
+			t.Fatal(err)
+		}
+	}
+	asrt.Equal(t, string(memory[0x0300:0x0305]), "HELLO")
+}
@@ -0,0 +1,223 @@
+package asm
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// Expressions are made of numbers ($hex, %binary, decimal, 'c'), symbols,
+// * for the current address, the unary - ~ < > operators and the binary
+// | ^ & << >> + - * / operators, from the lowest to the highest priority.
+
+type exprParser struct {
+	text  string
+	pos   int
+	scope string
+	addr  uint16
+	// lookup returns false for the symbols not defined yet
+	lookup func(name string) (int, bool)
+	// unknown is set when a symbol is not defined yet
+	unknown bool
+}
+
+func (a *assembly) eval(text string, st *statement) (int, bool, error) {
+	p := exprParser{text: text, scope: st.scope, addr: a.pc, lookup: a.lookup}
+	value, err := p.parse()
+	if err != nil {
+		return 0, false, err
+	}
+	return value, !p.unknown, nil
+}
+
+func (p *exprParser) parse() (int, error) {
+	value, err := p.binary(0)
+	if err != nil {
+		return 0, err
+	}
+	p.skipSpaces()
+	if p.pos < len(p.text) {
+		return 0, fmt.Errorf("unexpected %q in expression", p.text[p.pos:])
+	}
+	return value, nil
+}
+
+var binaryOperators = [][]string{
+	{"|"},
+	{"^"},
+	{"&"},
+	{"<<", ">>"},
+	{"+", "-"},
+	{"*", "/"},
+}
+
+func (p *exprParser) binary(level int) (int, error) {
+	if level == len(binaryOperators) {
+		return p.unary()
+	}
+
+	left, err := p.binary(level + 1)
+	if err != nil {
+		return 0, err
+	}
+	for {
+		p.skipSpaces()
+		op := ""
+		for _, candidate := range binaryOperators[level] {
+			if strings.HasPrefix(p.text[p.pos:], candidate) {
+				op = candidate
+				break
+			}
+		}
+		if op == "" {
+			return left, nil
+		}
+		p.pos += len(op)
+
+		right, err := p.binary(level + 1)
+		if err != nil {
+			return 0, err
+		}
+		switch op {
+		case "|":
+			left |= right
+		case "^":
+			left ^= right
+		case "&":
+			left &= right
+		case "<<":
+			left <<= uint(right)
+		case ">>":
+			left >>= uint(right)
+		case "+":
+			left += right
+		case "-":
+			left -= right
+		case "*":
+			left *= right
+		case "/":
+			if right == 0 {
+				if p.unknown {
+					return 0, nil
+				}
+				return 0, fmt.Errorf("division by zero")
+			}
+			left /= right
+		}
+	}
+}
+
+func (p *exprParser) unary() (int, error) {
+	p.skipSpaces()
+	if p.pos >= len(p.text) {
+		return 0, fmt.Errorf("missing operand in expression")
+	}
+
+	op := p.text[p.pos]
+	switch op {
+	case '-', '~', '<', '>':
+		p.pos++
+		value, err := p.unary()
+		if err != nil {
+			return 0, err
+		}
+		switch op {
+		case '-':
+			return -value, nil
+		case '~':
+			return ^value, nil
+		case '<':
+			return value & 0xFF, nil
+		default:
+			return (value >> 8) & 0xFF, nil
+		}
+	}
+	return p.primary()
+}
+
+func (p *exprParser) primary() (int, error) {
+	c := p.text[p.pos]
+	switch {
+	case c == '(':
+		p.pos++
+		value, err := p.binary(0)
+		if err != nil {
+			return 0, err
+		}
+		p.skipSpaces()
+		if p.pos >= len(p.text) || p.text[p.pos] != ')' {
+			return 0, fmt.Errorf("missing ) in expression")
+		}
+		p.pos++
+		return value, nil
+	case c == '*':
+		p.pos++
+		return int(p.addr), nil
+	case c == '$':
+		p.pos++
+		return p.number(16, isHexDigit)
+	case c == '%':
+		p.pos++
+		return p.number(2, func(r byte) bool { return r == '0' || r == '1' })
+	case c >= '0' && c <= '9':
+		return p.number(10, func(r byte) bool { return r >= '0' && r <= '9' })
+	case c == '\'':
+		if p.pos+2 >= len(p.text) || p.text[p.pos+2] != '\'' {
+			return 0, fmt.Errorf("bad character literal")
+		}
+		value := int(p.text[p.pos+1])
+		p.pos += 3
+		return value, nil
+	case isSymbolStart(c):
+		start := p.pos
+		for p.pos < len(p.text) && isSymbolPart(p.text[p.pos]) {
+			p.pos++
+		}
+		name := qualify(p.text[start:p.pos], p.scope)
+		value, ok := p.lookup(name)
+		if !ok {
+			p.unknown = true
+		}
+		return value, nil
+	}
+	return 0, fmt.Errorf("unexpected %q in expression", p.text[p.pos:])
+}
+
+func (p *exprParser) number(base int, digit func(byte) bool) (int, error) {
+	start := p.pos
+	for p.pos < len(p.text) && digit(p.text[p.pos]) {
+		p.pos++
+	}
+	value, err := strconv.ParseInt(p.text[start:p.pos], base, 32)
+	if err != nil {
+		return 0, fmt.Errorf("bad number %q", p.text[start:p.pos])
+	}
+	return int(value), nil
+}
+
+func (p *exprParser) skipSpaces() {
+	for p.pos < len(p.text) && unicode.IsSpace(rune(p.text[p.pos])) {
+		p.pos++
+	}
+}
+
+func isHexDigit(c byte) bool {
+	return c >= '0' && c <= '9' || c >= 'a' && c <= 'f' || c >= 'A' && c <= 'F'
+}
+
+// local labels start with @ and belong to the last global label
+func isSymbolStart(c byte) bool {
+	return c == '_' || c == '@' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z'
+}
+
+func isSymbolPart(c byte) bool {
+	return isSymbolStart(c) || c >= '0' && c <= '9'
+}
+
+func qualify(name, scope string) string {
+	if strings.HasPrefix(name, "@") {
+		return scope + name
+	}
+	return name
+}
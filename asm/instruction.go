@@ -0,0 +1,237 @@
+package asm
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/zehlt/go6502"
+)
+
+// operand syntaxes, each one lists its zero page mode then its absolute
+// mode, noMode when the syntax has no such form
+const noMode = -1
+
+type syntax struct {
+	zeroPage, absolute int
+}
+
+var (
+	direct    = syntax{go6502.ZeroPage, go6502.Absolute}
+	indexX    = syntax{go6502.ZeroPageX, go6502.AbsoluteX}
+	indexY    = syntax{go6502.ZeroPageY, go6502.AbsoluteY}
+	indirect  = syntax{go6502.IndirectZeroPage, go6502.Indirect}
+	indirectX = syntax{go6502.IndirectX, go6502.AbsoluteIndirectX}
+	indirectY = syntax{go6502.IndirectY, noMode}
+	invalid   = syntax{noMode, noMode}
+)
+
+// instruction selects the opcode in the first pass and encodes it in the
+// second one, so that both passes agree on the size
+func (a *assembly) instruction(st *statement) error {
+	modes, ok := a.index[st.mnemonic]
+	if !ok {
+		return fmt.Errorf("unknown instruction %s", st.mnemonic)
+	}
+
+	if !a.final {
+		opc, err := a.selectOpcode(st, modes)
+		if err != nil {
+			return err
+		}
+		st.opcode = opc
+		a.pc += uint16(opc.ByteSize)
+		return nil
+	}
+	return a.encode(st)
+}
+
+func (a *assembly) selectOpcode(st *statement, modes map[int]go6502.Opcode) (go6502.Opcode, error) {
+	unsupported := func() (go6502.Opcode, error) {
+		return go6502.Opcode{}, fmt.Errorf("%s does not support the operand %q", st.mnemonic, st.operand)
+	}
+	pick := func(mode int) (go6502.Opcode, error) {
+		if opc, ok := modes[mode]; ok {
+			return opc, nil
+		}
+		return unsupported()
+	}
+
+	operand := st.operand
+	switch {
+	case operand == "":
+		if opc, ok := modes[go6502.Implied]; ok {
+			return opc, nil
+		}
+		return pick(go6502.Accumulator)
+	case strings.EqualFold(operand, "A"):
+		if opc, ok := modes[go6502.Accumulator]; ok {
+			return opc, nil
+		}
+	case operand[0] == '#':
+		return pick(go6502.Immediate)
+	}
+
+	if opc, ok := modes[go6502.Relative]; ok {
+		return opc, nil
+	}
+	if opc, ok := modes[go6502.ZeroPageRelative]; ok {
+		return opc, nil
+	}
+
+	expr, syn := splitSyntax(operand)
+	zeroPage, hasZeroPage := modes[syn.zeroPage]
+	absolute, hasAbsolute := modes[syn.absolute]
+	switch {
+	case hasZeroPage && hasAbsolute:
+		value, known, err := a.eval(expr, st)
+		if err != nil {
+			return go6502.Opcode{}, err
+		}
+		if known && value >= 0 && value <= 0xFF {
+			return zeroPage, nil
+		}
+		return absolute, nil
+	case hasZeroPage:
+		return zeroPage, nil
+	case hasAbsolute:
+		return absolute, nil
+	}
+	return unsupported()
+}
+
+// splitSyntax returns the address expression of the operand and its syntax
+func splitSyntax(operand string) (string, syntax) {
+	upper := strings.ToUpper(strings.ReplaceAll(operand, " ", ""))
+	parts := splitOperands(operand)
+
+	if strings.HasPrefix(upper, "(") {
+		switch {
+		case strings.HasSuffix(upper, ",X)"):
+			inner := strings.TrimSpace(operand[1:strings.LastIndex(operand, ")")])
+			return strings.TrimSpace(inner[:strings.LastIndex(inner, ",")]), indirectX
+		case len(parts) == 2 && strings.EqualFold(parts[1], "Y") && enclosed(parts[0]):
+			return strings.TrimSpace(parts[0][1 : len(parts[0])-1]), indirectY
+		case len(parts) == 1 && enclosed(operand):
+			return strings.TrimSpace(operand[1 : len(operand)-1]), indirect
+		}
+	}
+
+	if len(parts) == 2 {
+		switch strings.ToUpper(parts[1]) {
+		case "X":
+			if enclosed(parts[0]) {
+				// there is no (zp),X mode
+				return parts[0], invalid
+			}
+			return parts[0], indexX
+		case "Y":
+			return parts[0], indexY
+		}
+	}
+	return operand, direct
+}
+
+// enclosed tells whether the parenthesis opening text closes at its end,
+// "(a+1)*2" is an expression, not an indirection
+func enclosed(text string) bool {
+	if !strings.HasPrefix(text, "(") || !strings.HasSuffix(text, ")") {
+		return false
+	}
+	depth := 0
+	for i := 0; i < len(text); i++ {
+		switch text[i] {
+		case '(':
+			depth++
+		case ')':
+			depth--
+			if depth == 0 {
+				return i == len(text)-1
+			}
+		}
+	}
+	return false
+}
+
+func (a *assembly) encode(st *statement) error {
+	opc := st.opcode
+	next := int(a.pc) + int(opc.ByteSize)
+	operand := st.operand
+	if operand != "" && operand[0] == '#' {
+		operand = operand[1:]
+	}
+
+	switch {
+	case opc.Mode == go6502.Implied || opc.Mode == go6502.Accumulator:
+		a.emit(opc.Code)
+
+	case opc.Mode == go6502.Relative:
+		offset, err := a.branchOffset(operand, st, next)
+		if err != nil {
+			return err
+		}
+		a.emit(opc.Code, uint8(offset))
+
+	case opc.Mode == go6502.ZeroPageRelative:
+		parts := splitOperands(operand)
+		if len(parts) != 2 {
+			return fmt.Errorf("%s needs a zero page address and a target", st.mnemonic)
+		}
+		addr, err := a.value(parts[0], st)
+		if err != nil {
+			return err
+		}
+		if addr < 0 || addr > 0xFF {
+			return fmt.Errorf("address $%X is not in the zero page", addr)
+		}
+		offset, err := a.branchOffset(parts[1], st, next)
+		if err != nil {
+			return err
+		}
+		a.emit(opc.Code, uint8(addr), uint8(offset))
+
+	case opc.Mode == go6502.Immediate:
+		value, err := a.value(operand, st)
+		if err != nil {
+			return err
+		}
+		if err := checkByte(value); err != nil {
+			return err
+		}
+		a.emit(opc.Code, uint8(value))
+
+	case opc.ByteSize == 2:
+		expr, _ := splitSyntax(operand)
+		value, err := a.value(expr, st)
+		if err != nil {
+			return err
+		}
+		if value < 0 || value > 0xFF {
+			return fmt.Errorf("address $%X is not in the zero page", value)
+		}
+		a.emit(opc.Code, uint8(value))
+
+	default:
+		expr, _ := splitSyntax(operand)
+		value, err := a.value(expr, st)
+		if err != nil {
+			return err
+		}
+		if value < 0 || value > 0xFFFF {
+			return fmt.Errorf("address $%X is out of range", value)
+		}
+		a.emit(opc.Code, uint8(value), uint8(value>>8))
+	}
+	return nil
+}
+
+func (a *assembly) branchOffset(text string, st *statement, next int) (int, error) {
+	target, err := a.value(text, st)
+	if err != nil {
+		return 0, err
+	}
+	offset := target - next
+	if offset < -128 || offset > 127 {
+		return 0, fmt.Errorf("branch to $%04X out of range", target)
+	}
+	return offset, nil
+}
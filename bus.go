@@ -1,5 +1,7 @@
 package go6502
 
+// Bus is what the cpu reads and writes. Mem and BusEx are flat memories,
+// MappedBus is made of chips mapped to address ranges.
 type Bus interface {
 	Write(addr uint16, data uint8)
 	Read(addr uint16) uint8
@@ -7,6 +9,34 @@ type Bus interface {
 	ReadWord(addr uint16) uint16
 }
 
+// Inspector is implemented by the buses and the chips that tools can look
+// into without the side effects of Read and Write, like a status register
+// acknowledged by its read. Poke changes the byte Peek returns, a ROM
+// included.
+type Inspector interface {
+	Peek(addr uint16) uint8
+	Poke(addr uint16, data uint8)
+}
+
+// Peek reads addr without side effects when bus is an Inspector, it falls
+// back to Read otherwise.
+func Peek(bus Bus, addr uint16) uint8 {
+	if i, ok := bus.(Inspector); ok {
+		return i.Peek(addr)
+	}
+	return bus.Read(addr)
+}
+
+// Poke writes addr without side effects when bus is an Inspector, it falls
+// back to Write otherwise.
+func Poke(bus Bus, addr uint16, data uint8) {
+	if i, ok := bus.(Inspector); ok {
+		i.Poke(addr, data)
+		return
+	}
+	bus.Write(addr, data)
+}
+
 type BusEx struct {
 	m *Mem
 }
@@ -27,6 +57,21 @@ func (b BusEx) WriteWord(addr uint16, data uint16) {
 	b.m.WriteWord(addr, data)
 }
 
+func (b BusEx) Peek(addr uint16) uint8 {
+	return b.m.Peek(addr)
+}
+
+func (b BusEx) Poke(addr uint16, data uint8) {
+	b.m.Poke(addr, data)
+}
+
 func (b BusEx) WriteBytes(addr uint16, data []uint8) {
 	b.m.WriteBytes(addr, data)
 }
+
+// ReadZeroPageWord reads the pointer at addr in the zero page of bus, the
+// high byte of $FF is read from $00 like the cpu does for (zp,X) and
+// (zp),Y.
+func ReadZeroPageWord(bus Bus, addr uint8) uint16 {
+	return uint16(bus.Read(zeroPageNext(uint16(addr))))<<8 | uint16(bus.Read(uint16(addr)))
+}
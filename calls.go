@@ -0,0 +1,173 @@
+package go6502
+
+import "fmt"
+
+// FrameKind tells what entered a frame of the call stack.
+type FrameKind int
+
+const (
+	CallFrame FrameKind = iota
+	BreakFrame
+	IrqFrame
+	NmiFrame
+)
+
+func (k FrameKind) String() string {
+	switch k {
+	case CallFrame:
+		return "JSR"
+	case BreakFrame:
+		return "BRK"
+	case IrqFrame:
+		return "IRQ"
+	case NmiFrame:
+		return "NMI"
+	default:
+		return "unknown"
+	}
+}
+
+// Frame is a subroutine or an interrupt handler in progress. Caller is the
+// address of the JSR or the BRK, or of the instruction an interrupt came
+// before, Target the address of the subroutine or the handler.
+// StackPointer is the stack pointer once the return address is pushed.
+type Frame struct {
+	Kind         FrameKind
+	Caller       uint16
+	Target       uint16
+	StackPointer Register8
+	Cycle        int
+}
+
+func (f Frame) String() string {
+	if f.Kind == IrqFrame || f.Kind == NmiFrame {
+		return fmt.Sprintf("%v $%04X before $%04X", f.Kind, f.Target, f.Caller)
+	}
+	return fmt.Sprintf("%v $%04X from $%04X", f.Kind, f.Target, f.Caller)
+}
+
+// Diagnostic reports a use of the stack the call stack cannot follow, PC
+// is the address of the instruction.
+type Diagnostic struct {
+	Cycle   int
+	PC      uint16
+	Message string
+}
+
+func (d Diagnostic) String() string {
+	return fmt.Sprintf("$%04X (cycle %d): %s", d.PC, d.Cycle, d.Message)
+}
+
+// maxDiagnostics bounds the diagnostics kept, the oldest are dropped
+const maxDiagnostics = 256
+
+// Backtrace returns the call stack tracked with TrackCalls, the innermost
+// frame first.
+func (c *Cpu) Backtrace() []Frame {
+	frames := make([]Frame, len(c.calls))
+	for i, f := range c.calls {
+		frames[len(frames)-1-i] = f
+	}
+	return frames
+}
+
+// SetBacktrace replaces the call stack with frames, innermost first as
+// Backtrace returns them. Tools that restore a state with UnmarshalBinary,
+// which empties the call stack, put the frames of the state back with it.
+func (c *Cpu) SetBacktrace(frames []Frame) {
+	c.calls = c.calls[:0]
+	for i := len(frames) - 1; i >= 0; i-- {
+		c.calls = append(c.calls, frames[i])
+	}
+}
+
+// Diagnostics returns the mismatches found while tracking the calls, the
+// oldest first.
+func (c *Cpu) Diagnostics() []Diagnostic {
+	return append([]Diagnostic(nil), c.diagnostics...)
+}
+
+// diagnose reports the instruction or the interrupt sequence that just
+// completed, the message follows its mnemonic
+func (c *Cpu) diagnose(format string, args ...interface{}) {
+	mnemonic := "interrupt"
+	if !sameProgram(c.program, interruptProgram) {
+		mnemonic = c.Variant.Opcodes()[c.opcode].Mnemonic
+	}
+	if len(c.diagnostics) == maxDiagnostics {
+		c.diagnostics = append(c.diagnostics[:0], c.diagnostics[1:]...)
+	}
+	c.diagnostics = append(c.diagnostics, Diagnostic{Cycle: c.Cycle, PC: c.opcodePC, Message: mnemonic + " " + fmt.Sprintf(format, args...)})
+}
+
+func (c *Cpu) enter(kind FrameKind) {
+	c.calls = append(c.calls, Frame{
+		Kind:         kind,
+		Caller:       c.opcodePC,
+		Target:       uint16(c.ProgramCounter),
+		StackPointer: c.StackPointer,
+		Cycle:        c.Cycle,
+	})
+}
+
+// trackCalls follows the instruction or the interrupt sequence that just
+// completed on the call stack
+func (c *Cpu) trackCalls() {
+	before, after := c.spBefore, c.StackPointer
+	interrupt := sameProgram(c.program, interruptProgram)
+
+	// but for TXS, the stack pointer moves by 3 at most
+	if interrupt || c.opcode != TXS_IMP {
+		switch delta := int(after) - int(before); {
+		case delta > 3:
+			c.diagnose("wraps the stack past $0100")
+			c.calls = c.calls[:0]
+		case delta < -3:
+			c.diagnose("wraps the stack past $01FF")
+			c.calls = c.calls[:0]
+		}
+	}
+
+	switch {
+	case interrupt && c.vector == NmiVector:
+		c.enter(NmiFrame)
+		return
+	case interrupt:
+		c.enter(IrqFrame)
+		return
+	case c.opcode == JSR_ABS:
+		c.enter(CallFrame)
+		return
+	case c.opcode == BRK_IMP:
+		c.enter(BreakFrame)
+		return
+	}
+
+	returns := c.opcode == RTS_IMP || c.opcode == RTI_IMP
+	if returns {
+		c.checkReturn(before)
+	}
+
+	// the frames whose return address was pulled are left
+	for n := len(c.calls); n > 0 && c.calls[n-1].StackPointer < after; n-- {
+		if !returns {
+			c.diagnose("discards the frame of %v", c.calls[n-1])
+		}
+		c.calls = c.calls[:n-1]
+	}
+}
+
+func (c *Cpu) checkReturn(sp Register8) {
+	if len(c.calls) == 0 {
+		c.diagnose("without a frame to return from")
+		return
+	}
+	top := c.calls[len(c.calls)-1]
+	if sp != top.StackPointer {
+		c.diagnose("with the stack pointer at $%02X, %v left it at $%02X", uint8(sp), top, uint8(top.StackPointer))
+		return
+	}
+	if (c.opcode == RTS_IMP) != (top.Kind == CallFrame) {
+		c.diagnose("returns from %v", top)
+	}
+}
@@ -0,0 +1,184 @@
+package go6502
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/zehlt/go6502/asrt"
+)
+
+func trackedCpu(code map[uint16][]uint8) (*Cpu, *Mem) {
+	memory := &Mem{}
+	for addr, bytes := range code {
+		memory.WriteBytes(addr, bytes)
+	}
+	memory.WriteWord(IrqVector, 0x8000)
+	memory.WriteWord(NmiVector, 0x9000)
+	cpu := &Cpu{TrackCalls: true}
+	cpu.ProgramCounter = 0x0600
+	cpu.StackPointer = 0xFF
+	return cpu, memory
+}
+
+func stepN(t *testing.T, cpu *Cpu, bus Bus, n int) {
+	for i := 0; i < n; i++ {
+		asrt.Equal(t, cpu.Step(bus), nil)
+	}
+}
+
+func TestBacktraceOfNestedCalls(t *testing.T) {
+	cpu, memory := trackedCpu(map[uint16][]uint8{
+		0x0600: {JSR_ABS, 0x10, 0x06, NOP_IMP},
+		0x0610: {JSR_ABS, 0x20, 0x06, RTS_IMP},
+		0x0620: {NOP_IMP, RTS_IMP},
+	})
+
+	stepN(t, cpu, memory, 3)
+	frames := cpu.Backtrace()
+	asrt.Equal(t, len(frames), 2)
+	asrt.Equal(t, frames[0], Frame{Kind: CallFrame, Caller: 0x0610, Target: 0x0620, StackPointer: 0xFB, Cycle: 12})
+	asrt.Equal(t, frames[1], Frame{Kind: CallFrame, Caller: 0x0600, Target: 0x0610, StackPointer: 0xFD, Cycle: 6})
+	asrt.Equal(t, frames[0].String(), "JSR $0620 from $0610")
+
+	stepN(t, cpu, memory, 1)
+	asrt.Equal(t, len(cpu.Backtrace()), 1)
+	stepN(t, cpu, memory, 2)
+	asrt.Equal(t, len(cpu.Backtrace()), 0)
+	asrt.Equal(t, cpu.ProgramCounter, Register16(0x0604))
+	asrt.Equal(t, len(cpu.Diagnostics()), 0)
+}
+
+func TestBacktraceOfInterrupts(t *testing.T) {
+	cpu, memory := trackedCpu(map[uint16][]uint8{
+		0x0600: {JSR_ABS, 0x10, 0x06},
+		0x0610: {NOP_IMP, NOP_IMP},
+		0x8000: {RTI_IMP},
+		0x9000: {BRK_IMP, 0x00},
+	})
+
+	stepN(t, cpu, memory, 1)
+	cpu.SetNMI(true)
+	stepN(t, cpu, memory, 2)
+	frames := cpu.Backtrace()
+	asrt.Equal(t, len(frames), 3)
+	asrt.Equal(t, frames[0].Kind, BreakFrame)
+	asrt.Equal(t, frames[0].Caller, uint16(0x9000))
+	asrt.Equal(t, frames[1].Kind, NmiFrame)
+	asrt.Equal(t, frames[1].String(), "NMI $9000 before $0610")
+	asrt.Equal(t, frames[2].Kind, CallFrame)
+
+	// the RTI of the IRQ handler returns from the BRK
+	stepN(t, cpu, memory, 1)
+	asrt.Equal(t, len(cpu.Backtrace()), 2)
+	asrt.Equal(t, len(cpu.Diagnostics()), 0)
+}
+
+func TestReturnWithoutCall(t *testing.T) {
+	cpu, memory := trackedCpu(map[uint16][]uint8{
+		0x0600: {RTS_IMP},
+	})
+	cpu.StackPointer = 0xFD
+	stepN(t, cpu, memory, 1)
+
+	diagnostics := cpu.Diagnostics()
+	asrt.Equal(t, len(diagnostics), 1)
+	asrt.Equal(t, diagnostics[0].PC, uint16(0x0600))
+	asrt.Equal(t, diagnostics[0].String(), "$0600 (cycle 6): RTS without a frame to return from")
+}
+
+func TestResetForgetsTheCalls(t *testing.T) {
+	cpu, memory := trackedCpu(map[uint16][]uint8{
+		0x0600: {RTS_IMP},
+	})
+	cpu.StackPointer = 0xFD
+	stepN(t, cpu, memory, 1)
+	asrt.Equal(t, len(cpu.Diagnostics()), 1)
+	cpu.SetBacktrace([]Frame{{Kind: CallFrame, Caller: 0x0600}})
+	asrt.Equal(t, len(cpu.Backtrace()), 1)
+
+	cpu.Reset(memory)
+	asrt.Equal(t, len(cpu.Backtrace()), 0)
+	asrt.Equal(t, len(cpu.Diagnostics()), 0)
+}
+
+func TestReturnOfTheWrongKind(t *testing.T) {
+	cpu, memory := trackedCpu(map[uint16][]uint8{
+		0x0600: {JSR_ABS, 0x10, 0x06},
+		0x0610: {RTI_IMP},
+	})
+	cpu.StackPointer = 0xF0
+	stepN(t, cpu, memory, 2)
+
+	diagnostics := cpu.Diagnostics()
+	asrt.Equal(t, len(diagnostics), 1)
+	asrt.Equal(t, diagnostics[0].Message, "RTI returns from JSR $0610 from $0600")
+	asrt.Equal(t, len(cpu.Backtrace()), 0)
+}
+
+func TestReturnWithValuesLeftOnTheStack(t *testing.T) {
+	cpu, memory := trackedCpu(map[uint16][]uint8{
+		0x0600: {JSR_ABS, 0x10, 0x06},
+		0x0610: {PHA_IMP, RTS_IMP},
+	})
+	stepN(t, cpu, memory, 3)
+
+	diagnostics := cpu.Diagnostics()
+	asrt.Equal(t, len(diagnostics), 1)
+	asrt.True(t, strings.HasPrefix(diagnostics[0].Message, "RTS with the stack pointer at $FC"))
+	asrt.Equal(t, len(cpu.Backtrace()), 0)
+}
+
+func TestPullingTheReturnAddress(t *testing.T) {
+	cpu, memory := trackedCpu(map[uint16][]uint8{
+		0x0600: {JSR_ABS, 0x10, 0x06},
+		0x0610: {PLA_IMP, PLA_IMP, LDX_IMM, 0xFF, TXS_IMP},
+	})
+	stepN(t, cpu, memory, 2)
+	asrt.Equal(t, len(cpu.Backtrace()), 0)
+	diagnostics := cpu.Diagnostics()
+	asrt.Equal(t, len(diagnostics), 1)
+	asrt.Equal(t, diagnostics[0].Message, "PLA discards the frame of JSR $0610 from $0600")
+
+	// TXS moves the stack pointer freely
+	cpu, memory = trackedCpu(map[uint16][]uint8{
+		0x0600: {JSR_ABS, 0x10, 0x06},
+		0x0610: {LDX_IMM, 0xFF, TXS_IMP},
+	})
+	stepN(t, cpu, memory, 3)
+	asrt.Equal(t, len(cpu.Backtrace()), 0)
+	diagnostics = cpu.Diagnostics()
+	asrt.Equal(t, len(diagnostics), 1)
+	asrt.Equal(t, diagnostics[0].Message, "TXS discards the frame of JSR $0610 from $0600")
+}
+
+func TestStackWraparound(t *testing.T) {
+	cpu, memory := trackedCpu(map[uint16][]uint8{
+		0x0600: {JSR_ABS, 0x10, 0x06},
+		0x0610: {PLA_IMP},
+	})
+	cpu.StackPointer = 0x00
+	stepN(t, cpu, memory, 1)
+	asrt.Equal(t, cpu.StackPointer, Register8(0xFE))
+	diagnostics := cpu.Diagnostics()
+	asrt.Equal(t, len(diagnostics), 1)
+	asrt.Equal(t, diagnostics[0].Message, "JSR wraps the stack past $0100")
+	asrt.Equal(t, len(cpu.Backtrace()), 1)
+
+	cpu.StackPointer = 0xFF
+	stepN(t, cpu, memory, 1)
+	diagnostics = cpu.Diagnostics()
+	asrt.Equal(t, len(diagnostics), 2)
+	asrt.Equal(t, diagnostics[1].Message, "PLA wraps the stack past $01FF")
+	asrt.Equal(t, len(cpu.Backtrace()), 0)
+}
+
+func TestCallsAreNotTrackedByDefault(t *testing.T) {
+	cpu, memory := trackedCpu(map[uint16][]uint8{
+		0x0600: {JSR_ABS, 0x10, 0x06},
+		0x0610: {RTS_IMP, RTS_IMP},
+	})
+	cpu.TrackCalls = false
+	stepN(t, cpu, memory, 3)
+	asrt.Equal(t, len(cpu.Backtrace()), 0)
+	asrt.Equal(t, len(cpu.Diagnostics()), 0)
+}
@@ -0,0 +1,98 @@
+// Command go6502mon is a machine language monitor in the style of the
+// Apple II and VICE monitors. It loads binaries, dumps, edits, assembles
+// and disassembles memory, and runs the cpu under breakpoints and
+// watchpoints. The execution is recorded so that it can step backwards,
+// and the calls are tracked to show a backtrace.
+//
+//	go6502mon [-variant 65c02] [-load file@addr] [-script file] [-gdb addr]
+//
+// With -script the commands are read from the file, echoed with their
+// output, and the first failing command exits with status 1, which makes
+// scripts usable as regression tests. Otherwise the commands are read
+// from the standard input, help lists them. Interrupt pauses a running
+// program. With -gdb the monitor serves the cpu to GDB remote protocol
+// clients on the TCP address instead, once the script if any has run.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"strings"
+
+	"github.com/zehlt/go6502"
+	"github.com/zehlt/go6502/gdbstub"
+)
+
+var variants = map[string]go6502.Variant{
+	"6502":   go6502.NMOS6502,
+	"2a03":   go6502.Ricoh2A03,
+	"65c02":  go6502.CMOS65C02,
+	"r65c02": go6502.Rockwell65C02,
+	"w65c02": go6502.WDC65C02,
+}
+
+func main() {
+	variantName := flag.String("variant", "6502", "cpu variant: 6502, 2a03, 65c02, r65c02 or w65c02")
+	load := flag.String("load", "", "binary to load, as file@addr with addr in hexadecimal")
+	script := flag.String("script", "", "file of commands to run instead of the standard input")
+	gdb := flag.String("gdb", "", "TCP address to serve GDB remote protocol clients on, like localhost:1234")
+	flag.Parse()
+
+	variant, ok := variants[strings.ToLower(*variantName)]
+	if !ok {
+		fmt.Fprintf(os.Stderr, "unknown variant %q\n", *variantName)
+		os.Exit(2)
+	}
+	m := newMonitor(variant, os.Stdout)
+
+	if *load != "" {
+		at := strings.LastIndex(*load, "@")
+		if at < 0 {
+			fmt.Fprintln(os.Stderr, "-load expects file@addr")
+			os.Exit(2)
+		}
+		if err := m.load([]string{(*load)[:at], (*load)[at+1:]}); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(2)
+		}
+	}
+
+	interrupts := make(chan os.Signal, 1)
+	signal.Notify(interrupts, os.Interrupt)
+	go func() {
+		for range interrupts {
+			m.debugger.Pause()
+		}
+	}()
+
+	// the script runs before gdb clients are served, the standard input
+	// is only read without them
+	switch {
+	case *script != "":
+		file, err := os.Open(*script)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(2)
+		}
+		defer file.Close()
+		if err := m.run(file, false); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+	case *gdb == "":
+		if err := m.run(os.Stdin, true); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+	}
+
+	if *gdb != "" {
+		fmt.Fprintf(os.Stderr, "serving gdb on %s\n", *gdb)
+		if err := gdbstub.NewServer(m.debugger).ListenAndServe(*gdb); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+	}
+}
@@ -0,0 +1,592 @@
+package main
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/zehlt/go6502"
+	"github.com/zehlt/go6502/asm"
+	"github.com/zehlt/go6502/debug"
+	"github.com/zehlt/go6502/rewind"
+	"github.com/zehlt/go6502/trace"
+)
+
+var errQuit = errors.New("quit")
+
+// monitor runs the commands of a session, the addresses are hexadecimal
+// with an optional $ prefix
+type monitor struct {
+	out      io.Writer
+	cpu      *go6502.Cpu
+	mem      *go6502.Mem
+	debugger *debug.Debugger
+	readFile func(name string) ([]byte, error)
+
+	// where m and d continue without an address
+	dumpAddr, disasmAddr uint16
+	// the address of the next line in assemble mode, -1 outside of it
+	asmAddr int
+}
+
+func newMonitor(variant go6502.Variant, out io.Writer) *monitor {
+	m := &monitor{
+		out:      out,
+		cpu:      &go6502.Cpu{Variant: variant},
+		mem:      &go6502.Mem{},
+		readFile: os.ReadFile,
+		asmAddr:  -1,
+	}
+	m.cpu.StackPointer = 0xFF
+	m.cpu.TrackCalls = true
+	m.debugger = debug.New(m.cpu, m.mem)
+	m.debugger.History = rewind.New(m.cpu, m.mem)
+	return m
+}
+
+type command struct {
+	names []string
+	usage string
+	help  string
+	run   func(m *monitor, args []string) error
+}
+
+var commands []command
+
+func init() {
+	commands = []command{
+		{[]string{"l", "load"}, "l file addr", "load a binary file at addr", (*monitor).load},
+		{[]string{"m", "mem"}, "m [start [end]]", "dump memory", (*monitor).dump},
+		{[]string{">"}, "> addr byte...", "write bytes to memory", (*monitor).edit},
+		{[]string{"r", "reg"}, "r [reg=value...]", "show or set A X Y SP PC P", (*monitor).registers},
+		{[]string{"d", "disass"}, "d [start [end]]", "disassemble", (*monitor).disassemble},
+		{[]string{"a", "asm"}, "a addr [instruction]", "assemble one instruction, or every line up to an empty one", (*monitor).assemble},
+		{[]string{"break", "bk"}, "break [addr [if condition]]", "set a breakpoint, list them without addr", (*monitor).breakpoint},
+		{[]string{"watch", "w"}, "watch r|w|rw start [end]", "stop on reads or writes of an address range", (*monitor).watch},
+		{[]string{"del", "delete"}, "del id", "delete a breakpoint or a watchpoint", (*monitor).delete},
+		{[]string{"z", "step"}, "z [count]", "execute instructions", (*monitor).step},
+		{[]string{"n", "next"}, "n", "step over a JSR", (*monitor).next},
+		{[]string{"ret", "return"}, "ret", "run until the current subroutine returns", (*monitor).stepOut},
+		{[]string{"g", "go"}, "g [addr]", "run until a breakpoint, a watchpoint or a halt", (*monitor).goRun},
+		{[]string{"until"}, "until cycle", "run until the cycle count, decimal, is reached", (*monitor).until},
+		{[]string{"bz", "back"}, "bz [count]", "step back instructions", (*monitor).stepBack},
+		{[]string{"rewind"}, "rewind cycle", "go back to the instruction running at the cycle, decimal", (*monitor).rewind},
+		{[]string{"lastw"}, "lastw addr", "go back to the instruction that last wrote to addr", (*monitor).lastWrite},
+		{[]string{"bt", "backtrace"}, "bt", "show the subroutines and interrupts in progress, and the misuses of the stack", (*monitor).backtrace},
+		{[]string{"tr", "trace"}, "tr on|off", "log every executed instruction", (*monitor).trace},
+		{[]string{"reset"}, "reset", "reset the cpu through the reset vector", (*monitor).reset},
+		{[]string{"?", "help"}, "help", "list the commands", (*monitor).help},
+		{[]string{"x", "q", "quit"}, "q", "leave the monitor", func(*monitor, []string) error { return errQuit }},
+	}
+}
+
+func (m *monitor) printf(format string, args ...interface{}) {
+	fmt.Fprintf(m.out, format, args...)
+}
+
+// prompt is the prompt of the next line
+func (m *monitor) prompt() string {
+	if m.asmAddr >= 0 {
+		return fmt.Sprintf("a %04X> ", m.asmAddr)
+	}
+	return "(mon) "
+}
+
+// execute runs one line, errQuit ends the session
+func (m *monitor) execute(line string) error {
+	line = strings.TrimSpace(line)
+	if m.asmAddr >= 0 {
+		if line == "" {
+			m.asmAddr = -1
+			return nil
+		}
+		return m.assembleLine(uint16(m.asmAddr), line)
+	}
+	if line == "" || strings.HasPrefix(line, ";") {
+		return nil
+	}
+
+	// > takes its address right after it, like >0600 a9 40
+	if strings.HasPrefix(line, ">") {
+		line = "> " + line[1:]
+	}
+	fields := strings.Fields(line)
+	name := strings.ToLower(fields[0])
+	// an interrupt while waiting for the command does not stop it
+	m.debugger.ClearPause()
+	for _, cmd := range commands {
+		for _, n := range cmd.names {
+			if n == name {
+				return cmd.run(m, fields[1:])
+			}
+		}
+	}
+	return fmt.Errorf("unknown command %q, try help", fields[0])
+}
+
+// run executes the lines of in. A script stops at its first error, an
+// interactive session reports it and goes on.
+func (m *monitor) run(in io.Reader, interactive bool) error {
+	scanner := bufio.NewScanner(in)
+	for line := 1; ; line++ {
+		prompt := m.prompt()
+		if interactive {
+			m.printf("%s", prompt)
+		}
+		if !scanner.Scan() {
+			return scanner.Err()
+		}
+		if !interactive {
+			m.printf("%s%s\n", prompt, scanner.Text())
+		}
+
+		err := m.execute(scanner.Text())
+		switch {
+		case err == errQuit:
+			return nil
+		case err != nil && interactive:
+			m.printf("error: %v\n", err)
+		case err != nil:
+			return fmt.Errorf("line %d: %v", line, err)
+		}
+	}
+}
+
+func parseAddr(text string) (uint16, error) {
+	value, err := strconv.ParseUint(strings.TrimPrefix(text, "$"), 16, 16)
+	if err != nil {
+		return 0, fmt.Errorf("bad address %q", text)
+	}
+	return uint16(value), nil
+}
+
+func parseByte(text string) (uint8, error) {
+	value, err := strconv.ParseUint(strings.TrimPrefix(text, "$"), 16, 8)
+	if err != nil {
+		return 0, fmt.Errorf("bad byte %q", text)
+	}
+	return uint8(value), nil
+}
+
+// parseRange reads the optional start and end of m and d, end included
+func parseRange(args []string, start uint16, length int) (uint16, int, error) {
+	if len(args) > 0 {
+		addr, err := parseAddr(args[0])
+		if err != nil {
+			return 0, 0, err
+		}
+		start = addr
+	}
+	if len(args) > 1 {
+		end, err := parseAddr(args[1])
+		if err != nil {
+			return 0, 0, err
+		}
+		if end < start {
+			return 0, 0, fmt.Errorf("end $%04X before start $%04X", end, start)
+		}
+		length = int(end-start) + 1
+	}
+	return start, length, nil
+}
+
+func (m *monitor) load(args []string) error {
+	if len(args) != 2 {
+		return fmt.Errorf("usage: l file addr")
+	}
+	addr, err := parseAddr(args[1])
+	if err != nil {
+		return err
+	}
+	data, err := m.readFile(args[0])
+	if err != nil {
+		return err
+	}
+	if int(addr)+len(data) > len(m.mem) {
+		return fmt.Errorf("%s does not fit at $%04X", args[0], addr)
+	}
+	m.mem.WriteBytes(addr, data)
+	m.debugger.Modified()
+	m.printf("loaded %d bytes at $%04X-$%04X\n", len(data), addr, int(addr)+len(data)-1)
+	return nil
+}
+
+func (m *monitor) dump(args []string) error {
+	start, length, err := parseRange(args, m.dumpAddr, 0x80)
+	if err != nil {
+		return err
+	}
+	data := m.debugger.Memory(start, length)
+	for row := 0; row < len(data); row += 16 {
+		end := row + 16
+		if end > len(data) {
+			end = len(data)
+		}
+		hex := make([]string, 0, 16)
+		text := make([]byte, 0, 16)
+		for _, b := range data[row:end] {
+			hex = append(hex, fmt.Sprintf("%02X", b))
+			if b >= 0x20 && b < 0x7F {
+				text = append(text, b)
+			} else {
+				text = append(text, '.')
+			}
+		}
+		m.printf("%04X  %-47s  %s\n", start+uint16(row), strings.Join(hex, " "), text)
+	}
+	m.dumpAddr = start + uint16(length)
+	return nil
+}
+
+func (m *monitor) edit(args []string) error {
+	if len(args) < 2 {
+		return fmt.Errorf("usage: > addr byte...")
+	}
+	addr, err := parseAddr(args[0])
+	if err != nil {
+		return err
+	}
+	for i, arg := range args[1:] {
+		b, err := parseByte(arg)
+		if err != nil {
+			return err
+		}
+		m.debugger.Poke(addr+uint16(i), b)
+	}
+	return nil
+}
+
+func (m *monitor) registers(args []string) error {
+	if len(args) > 0 {
+		m.debugger.Modified()
+	}
+	for _, arg := range args {
+		parts := strings.SplitN(arg, "=", 2)
+		if len(parts) != 2 {
+			return fmt.Errorf("usage: r [reg=value...]")
+		}
+		name := strings.ToUpper(parts[0])
+		if name == "PC" {
+			value, err := parseAddr(parts[1])
+			if err != nil {
+				return err
+			}
+			m.cpu.ProgramCounter = go6502.Register16(value)
+			continue
+		}
+
+		value, err := parseByte(parts[1])
+		if err != nil {
+			return err
+		}
+		switch name {
+		case "A":
+			m.cpu.Accumulator = go6502.Register8(value)
+		case "X":
+			m.cpu.XIndex = go6502.Register8(value)
+		case "Y":
+			m.cpu.YIndex = go6502.Register8(value)
+		case "SP", "S":
+			m.cpu.StackPointer = go6502.Register8(value)
+		case "P":
+			m.cpu.Status = go6502.Register8(value)
+		default:
+			return fmt.Errorf("unknown register %q", parts[0])
+		}
+	}
+
+	r := m.debugger.Registers()
+	m.printf("PC:%04X A:%02X X:%02X Y:%02X SP:%02X P:%02X NV-BDIZC:%08b CYC:%d\n",
+		uint16(r.ProgramCounter), uint8(r.Accumulator), uint8(r.XIndex), uint8(r.YIndex),
+		uint8(r.StackPointer), uint8(r.Status), uint8(r.Status), m.cpu.Cycle)
+	return nil
+}
+
+func (m *monitor) disassemble(args []string) error {
+	start, length, err := parseRange(args, m.disasmAddr, 0)
+	if err != nil {
+		return err
+	}
+	// without an end, 16 instructions
+	end := int(start) + length
+	addr := int(start)
+	for i := 0; length == 0 && i < 16 || length > 0 && addr < end; i++ {
+		line := m.debugger.Disassemble(uint16(addr), 1)[0]
+		m.printf("%s\n", line)
+		addr += len(line.Bytes)
+	}
+	m.disasmAddr = uint16(addr)
+	return nil
+}
+
+func (m *monitor) assemble(args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("usage: a addr [instruction]")
+	}
+	addr, err := parseAddr(args[0])
+	if err != nil {
+		return err
+	}
+	if len(args) == 1 {
+		m.asmAddr = int(addr)
+		return nil
+	}
+	return m.assembleLine(addr, strings.Join(args[1:], " "))
+}
+
+func (m *monitor) assembleLine(addr uint16, line string) error {
+	a := asm.New(m.cpu.Variant)
+	program, err := a.Assemble("inline", fmt.Sprintf("\t.org $%04X\n\t%s", addr, line))
+	if err != nil {
+		var asmErr *asm.Error
+		if errors.As(err, &asmErr) {
+			return errors.New(asmErr.Msg)
+		}
+		return err
+	}
+	program.Load(m.mem)
+	m.debugger.Modified()
+
+	next := addr
+	for _, segment := range program.Segments {
+		next = segment.Addr + uint16(len(segment.Data))
+	}
+	m.printf("%s\n", m.debugger.Disassemble(addr, 1)[0])
+	if m.asmAddr >= 0 {
+		m.asmAddr = int(next)
+	}
+	m.disasmAddr = next
+	return nil
+}
+
+func (m *monitor) breakpoint(args []string) error {
+	if len(args) == 0 {
+		m.listBreakpoints()
+		return nil
+	}
+	addr, err := parseAddr(args[0])
+	if err != nil {
+		return err
+	}
+	condition := ""
+	if len(args) > 1 {
+		if strings.ToLower(args[1]) != "if" || len(args) == 2 {
+			return fmt.Errorf("usage: break [addr [if condition]]")
+		}
+		condition = strings.Join(args[2:], " ")
+	}
+	b, err := m.debugger.AddBreakpoint(addr, condition)
+	if err != nil {
+		return err
+	}
+	m.printf("breakpoint %d at $%04X\n", b.ID, b.Addr)
+	return nil
+}
+
+func (m *monitor) listBreakpoints() {
+	type entry struct {
+		id   int
+		text string
+	}
+	var entries []entry
+	for _, b := range m.debugger.Breakpoints() {
+		text := fmt.Sprintf("break $%04X", b.Addr)
+		if b.Text != "" {
+			text += " if " + b.Text
+		}
+		entries = append(entries, entry{b.ID, text})
+	}
+	for _, w := range m.debugger.Watchpoints() {
+		kind := map[debug.Access]string{debug.Read: "r", debug.Write: "w", debug.ReadWrite: "rw"}[w.Access]
+		entries = append(entries, entry{w.ID, fmt.Sprintf("watch %s $%04X-$%04X", kind, w.Start, w.End)})
+	}
+	sort.Slice(entries, func(i, j int) bool { return entries[i].id < entries[j].id })
+	for _, e := range entries {
+		m.printf("%3d  %s\n", e.id, e.text)
+	}
+}
+
+func (m *monitor) watch(args []string) error {
+	if len(args) < 2 || len(args) > 3 {
+		return fmt.Errorf("usage: watch r|w|rw start [end]")
+	}
+	access, ok := map[string]debug.Access{"r": debug.Read, "w": debug.Write, "rw": debug.ReadWrite}[strings.ToLower(args[0])]
+	if !ok {
+		return fmt.Errorf("usage: watch r|w|rw start [end]")
+	}
+	start, length, err := parseRange(args[1:], 0, 1)
+	if err != nil {
+		return err
+	}
+	w := m.debugger.AddWatchpoint(start, start+uint16(length-1), access)
+	m.printf("watchpoint %d at $%04X-$%04X\n", w.ID, w.Start, w.End)
+	return nil
+}
+
+func (m *monitor) delete(args []string) error {
+	if len(args) != 1 {
+		return fmt.Errorf("usage: del id")
+	}
+	id, err := strconv.Atoi(args[0])
+	if err != nil || !m.debugger.Remove(id) {
+		return fmt.Errorf("no breakpoint or watchpoint %s", args[0])
+	}
+	return nil
+}
+
+// report prints why the execution stopped and the next instruction
+func (m *monitor) report(stop debug.Stop) error {
+	if stop.Reason != debug.Done {
+		m.printf("%s\n", stop)
+	}
+	m.printf("%s\n", trace.Line(m.cpu, m.mem))
+	m.disasmAddr = uint16(m.cpu.ProgramCounter)
+	if stop.Reason == debug.Faulted {
+		return stop.Err
+	}
+	return nil
+}
+
+func (m *monitor) step(args []string) error {
+	count := 1
+	if len(args) > 0 {
+		n, err := strconv.Atoi(args[0])
+		if err != nil || n < 1 {
+			return fmt.Errorf("bad count %q", args[0])
+		}
+		count = n
+	}
+	var stop debug.Stop
+	for i := 0; i < count; i++ {
+		stop = m.debugger.Step()
+		if stop.Reason != debug.Done {
+			break
+		}
+	}
+	return m.report(stop)
+}
+
+func (m *monitor) next(args []string) error {
+	return m.report(m.debugger.StepOver())
+}
+
+func (m *monitor) stepOut(args []string) error {
+	return m.report(m.debugger.StepOut())
+}
+
+func (m *monitor) goRun(args []string) error {
+	if len(args) > 0 {
+		addr, err := parseAddr(args[0])
+		if err != nil {
+			return err
+		}
+		m.cpu.ProgramCounter = go6502.Register16(addr)
+		m.debugger.Modified()
+	}
+	return m.report(m.debugger.Continue())
+}
+
+func (m *monitor) until(args []string) error {
+	if len(args) != 1 {
+		return fmt.Errorf("usage: until cycle")
+	}
+	cycle, err := strconv.Atoi(args[0])
+	if err != nil {
+		return fmt.Errorf("bad cycle %q", args[0])
+	}
+	return m.report(m.debugger.RunToCycle(cycle))
+}
+
+func (m *monitor) stepBack(args []string) error {
+	count := 1
+	if len(args) > 0 {
+		n, err := strconv.Atoi(args[0])
+		if err != nil || n < 1 {
+			return fmt.Errorf("bad count %q", args[0])
+		}
+		count = n
+	}
+	for i := 0; i < count; i++ {
+		if !m.debugger.History.StepBack() {
+			m.printf("start of the history\n")
+			break
+		}
+	}
+	return m.report(debug.Stop{Reason: debug.Done})
+}
+
+func (m *monitor) rewind(args []string) error {
+	if len(args) != 1 {
+		return fmt.Errorf("usage: rewind cycle")
+	}
+	cycle, err := strconv.Atoi(args[0])
+	if err != nil {
+		return fmt.Errorf("bad cycle %q", args[0])
+	}
+	if err := m.debugger.History.RunBackTo(cycle); err != nil {
+		return fmt.Errorf("%v, the oldest is %d", err, m.debugger.History.Oldest())
+	}
+	return m.report(debug.Stop{Reason: debug.Done})
+}
+
+func (m *monitor) lastWrite(args []string) error {
+	if len(args) != 1 {
+		return fmt.Errorf("usage: lastw addr")
+	}
+	addr, err := parseAddr(args[0])
+	if err != nil {
+		return err
+	}
+	cycle, ok := m.debugger.History.LastWrite(addr)
+	if !ok {
+		return fmt.Errorf("no write to $%04X in the history", addr)
+	}
+	if err := m.debugger.History.RunBackTo(cycle); err != nil {
+		return err
+	}
+	m.printf("last write to $%04X at cycle %d\n", addr, cycle)
+	return m.report(debug.Stop{Reason: debug.Done})
+}
+
+func (m *monitor) backtrace(args []string) error {
+	for i, frame := range m.cpu.Backtrace() {
+		m.printf("#%d %v, SP:%02X\n", i, frame, uint8(frame.StackPointer))
+	}
+	for _, d := range m.cpu.Diagnostics() {
+		m.printf("warning: %v\n", d)
+	}
+	return nil
+}
+
+func (m *monitor) trace(args []string) error {
+	if len(args) != 1 {
+		return fmt.Errorf("usage: tr on|off")
+	}
+	switch strings.ToLower(args[0]) {
+	case "on":
+		m.cpu.Trace = trace.NewWriter(m.out).Trace
+	case "off":
+		m.cpu.Trace = nil
+	default:
+		return fmt.Errorf("usage: tr on|off")
+	}
+	return nil
+}
+
+func (m *monitor) reset(args []string) error {
+	m.cpu.Reset(m.mem)
+	m.debugger.Modified()
+	return m.report(debug.Stop{Reason: debug.Done})
+}
+
+func (m *monitor) help(args []string) error {
+	for _, cmd := range commands {
+		m.printf("%-30s %s\n", cmd.usage, cmd.help)
+	}
+	return nil
+}
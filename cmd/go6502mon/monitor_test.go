@@ -0,0 +1,171 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/zehlt/go6502"
+	"github.com/zehlt/go6502/asrt"
+)
+
+func runScript(t *testing.T, m *monitor, script string) string {
+	t.Helper()
+	var out bytes.Buffer
+	m.out = &out
+	if err := m.run(strings.NewReader(script), false); err != nil {
+		t.Fatalf("%v\n%s", err, out.String())
+	}
+	return out.String()
+}
+
+func assertContains(t *testing.T, out string, expected ...string) {
+	t.Helper()
+	for _, e := range expected {
+		if !strings.Contains(out, e) {
+			t.Errorf("missing %q in\n%s", e, out)
+		}
+	}
+}
+
+func TestSessionScript(t *testing.T) {
+	script, err := os.ReadFile("testdata/session.mon")
+	if err != nil {
+		t.Fatal(err)
+	}
+	m := newMonitor(go6502.WDC65C02, nil)
+	out := runScript(t, m, string(script))
+
+	assertContains(t, out,
+		"0600  A2 00     LDX #$00",
+		"0607  D0 FB     BNE $0604",
+		"breakpoint 1 at $0604",
+		"PC:0604 A:40 X:03",
+		"watchpoint 2, write $40 at $0200, stopped at $060C",
+		"halted at $060D",
+		"0200  12 34",
+	)
+	asrt.Equal(t, m.mem[0x0201], uint8(0x34))
+}
+
+func TestStepAndTrace(t *testing.T) {
+	m := newMonitor(go6502.NMOS6502, nil)
+	out := runScript(t, m, strings.Join([]string{
+		"a 0600 jsr $0610",
+		"a 0603 nop",
+		"a 0610 lda #$01",
+		"a 0612 rts",
+		"r pc=0600",
+		"n",
+		"r pc=0600",
+		"z",
+		"ret",
+		"tr on",
+		"z 1",
+		"tr off",
+	}, "\n"))
+
+	assertContains(t, out,
+		"0603  EA        NOP",
+		"0610  A9 01     LDA #$01",
+		"0603  EA        NOP                             A:01",
+	)
+	asrt.Equal(t, strings.Count(out, "0603  EA        NOP  "), 3)
+}
+
+func TestLoadAndErrors(t *testing.T) {
+	m := newMonitor(go6502.NMOS6502, nil)
+	m.readFile = func(name string) ([]byte, error) {
+		if name != "prog.bin" {
+			return nil, os.ErrNotExist
+		}
+		return []byte{0xA9, 0x07, 0x00}, nil
+	}
+
+	out := runScript(t, m, "l prog.bin C000\nd C000 C001")
+	assertContains(t, out, "loaded 3 bytes at $C000-$C002", "C000  A9 07     LDA #$07")
+
+	var buf bytes.Buffer
+	m.out = &buf
+	err := m.run(strings.NewReader("r\nfoo\nr"), false)
+	asrt.Equal(t, err.Error(), `line 2: unknown command "foo", try help`)
+
+	err = m.run(strings.NewReader("break 0600 if Q\nr"), true)
+	asrt.Equal(t, err, nil)
+	assertContains(t, buf.String(), "error: unexpected \"Q\" in condition")
+}
+
+func TestStepBack(t *testing.T) {
+	m := newMonitor(go6502.NMOS6502, nil)
+	out := runScript(t, m, strings.Join([]string{
+		"a 0600 lda #$01",
+		"a 0602 sta $10",
+		"a 0604 lda #$02",
+		"a 0606 sta $10",
+		"a 0608 nop",
+		"r pc=0600",
+		"z 5",
+		"lastw 10",
+		"m 10 10",
+		"bz 2",
+		"bz 9",
+		"z 2",
+		"rewind 4",
+	}, "\n"))
+
+	assertContains(t, out,
+		"last write to $0010 at cycle 7",
+		"0606  85 10     STA $10",
+		"0010  01",
+		"0602  85 10     STA $10",
+		"start of the history",
+		"0600  A9 01     LDA #$01",
+	)
+	asrt.Equal(t, uint16(m.cpu.ProgramCounter), uint16(0x0602))
+	asrt.Equal(t, m.mem[0x10], uint8(0x00))
+}
+
+func TestBacktrace(t *testing.T) {
+	m := newMonitor(go6502.NMOS6502, nil)
+	out := runScript(t, m, strings.Join([]string{
+		"a 0600 jsr $0610",
+		"a 0610 jsr $0620",
+		"a 0620 pla",
+		"a 0621 nop",
+		"r pc=0600",
+		"z 2",
+		"bt",
+		"z",
+		"bt",
+	}, "\n"))
+
+	assertContains(t, out,
+		"#0 JSR $0620 from $0610, SP:FB",
+		"#1 JSR $0610 from $0600, SP:FD",
+		"warning: $0620 (cycle 16): PLA discards the frame of JSR $0620 from $0610",
+	)
+}
+
+func TestBacktraceAfterStepBack(t *testing.T) {
+	m := newMonitor(go6502.NMOS6502, nil)
+	runScript(t, m, strings.Join([]string{
+		"a 0600 jsr $0610",
+		"a 0610 jsr $0620",
+		"a 0613 rts",
+		"a 0620 nop",
+		"a 0621 rts",
+		"r pc=0600",
+		"z 3",
+		"bz",
+	}, "\n"))
+
+	out := runScript(t, m, "bt")
+	assertContains(t, out,
+		"#0 JSR $0620 from $0610, SP:FB",
+		"#1 JSR $0610 from $0600, SP:FD",
+	)
+	out = runScript(t, m, "z 2\nbt")
+	assertContains(t, out, "#0 JSR $0610 from $0600, SP:FD")
+	asrt.False(t, strings.Contains(out, "warning:"))
+}
@@ -0,0 +1,46 @@
+// Command tracecmp compares an execution log with a golden log in the
+// nestest.log format and reports the first line that differs.
+//
+//	tracecmp run.log nestest.log
+//
+// It exits with status 1 when the logs differ and 2 on errors.
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/zehlt/go6502/trace"
+)
+
+func main() {
+	if len(os.Args) != 3 {
+		fmt.Fprintln(os.Stderr, "usage: tracecmp got.log golden.log")
+		os.Exit(2)
+	}
+
+	got, err := os.Open(os.Args[1])
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+	defer got.Close()
+
+	golden, err := os.Open(os.Args[2])
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+	defer golden.Close()
+
+	divergence, err := trace.Compare(got, golden)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+	if divergence != nil {
+		fmt.Println(divergence)
+		os.Exit(1)
+	}
+	fmt.Println("logs match")
+}
@@ -0,0 +1,167 @@
+package go6502
+
+// Operations and opcode tables of the 65C02 family.
+
+var cmosOpcodes = map[uint8]Opcode{
+	// Fixed page wrapping bug costs one more cycle
+	JMP_IND: {Code: JMP_IND, Mnemonic: "JMP", ByteSize: 3, Cycles: 6, Mode: Indirect, program: jmpIndCmosProgram},
+	JMP_IAX: {Code: JMP_IAX, Mnemonic: "JMP", ByteSize: 3, Cycles: 6, Mode: AbsoluteIndirectX, program: jmpIaxProgram},
+
+	// Shifts only pay the indexing cycle when a page is crossed
+	ASL_ABX: {Code: ASL_ABX, Mnemonic: "ASL", Operation: asl, ByteSize: 3, Cycles: 6, Mode: AbsoluteX1, Access: ModifyAccess},
+	LSR_ABX: {Code: LSR_ABX, Mnemonic: "LSR", Operation: lsr, ByteSize: 3, Cycles: 6, Mode: AbsoluteX1, Access: ModifyAccess},
+	ROL_ABX: {Code: ROL_ABX, Mnemonic: "ROL", Operation: rol, ByteSize: 3, Cycles: 6, Mode: AbsoluteX1, Access: ModifyAccess},
+	ROR_ABX: {Code: ROR_ABX, Mnemonic: "ROR", Operation: ror, ByteSize: 3, Cycles: 6, Mode: AbsoluteX1, Access: ModifyAccess},
+
+	// Always taken, one more cycle when crossing a page
+	BRA_REL: {Code: BRA_REL, Mnemonic: "BRA", Operation: bra, ByteSize: 2, Cycles: 3, Mode: Relative, Access: BranchAccess},
+
+	PHX_IMP: {Code: PHX_IMP, Mnemonic: "PHX", Operation: phx, ByteSize: 1, Cycles: 3, Mode: Implied, Access: PushAccess},
+	PHY_IMP: {Code: PHY_IMP, Mnemonic: "PHY", Operation: phy, ByteSize: 1, Cycles: 3, Mode: Implied, Access: PushAccess},
+	PLX_IMP: {Code: PLX_IMP, Mnemonic: "PLX", Operation: plx, ByteSize: 1, Cycles: 4, Mode: Implied, Access: PullAccess},
+	PLY_IMP: {Code: PLY_IMP, Mnemonic: "PLY", Operation: ply, ByteSize: 1, Cycles: 4, Mode: Implied, Access: PullAccess},
+
+	STZ_ZER: {Code: STZ_ZER, Mnemonic: "STZ", Operation: stz, ByteSize: 2, Cycles: 3, Mode: ZeroPage, Access: WriteAccess},
+	STZ_ZRX: {Code: STZ_ZRX, Mnemonic: "STZ", Operation: stz, ByteSize: 2, Cycles: 4, Mode: ZeroPageX, Access: WriteAccess},
+	STZ_ABS: {Code: STZ_ABS, Mnemonic: "STZ", Operation: stz, ByteSize: 3, Cycles: 4, Mode: Absolute, Access: WriteAccess},
+	STZ_ABX: {Code: STZ_ABX, Mnemonic: "STZ", Operation: stz, ByteSize: 3, Cycles: 5, Mode: AbsoluteX, Access: WriteAccess},
+
+	TRB_ZER: {Code: TRB_ZER, Mnemonic: "TRB", Operation: trb, ByteSize: 2, Cycles: 5, Mode: ZeroPage, Access: ModifyAccess},
+	TRB_ABS: {Code: TRB_ABS, Mnemonic: "TRB", Operation: trb, ByteSize: 3, Cycles: 6, Mode: Absolute, Access: ModifyAccess},
+	TSB_ZER: {Code: TSB_ZER, Mnemonic: "TSB", Operation: tsb, ByteSize: 2, Cycles: 5, Mode: ZeroPage, Access: ModifyAccess},
+	TSB_ABS: {Code: TSB_ABS, Mnemonic: "TSB", Operation: tsb, ByteSize: 3, Cycles: 6, Mode: Absolute, Access: ModifyAccess},
+
+	ORA_IZP: {Code: ORA_IZP, Mnemonic: "ORA", Operation: aor, ByteSize: 2, Cycles: 5, Mode: IndirectZeroPage, Access: ReadAccess},
+	AND_IZP: {Code: AND_IZP, Mnemonic: "AND", Operation: and, ByteSize: 2, Cycles: 5, Mode: IndirectZeroPage, Access: ReadAccess},
+	EOR_IZP: {Code: EOR_IZP, Mnemonic: "EOR", Operation: eor, ByteSize: 2, Cycles: 5, Mode: IndirectZeroPage, Access: ReadAccess},
+	ADC_IZP: {Code: ADC_IZP, Mnemonic: "ADC", Operation: adc, ByteSize: 2, Cycles: 5, Mode: IndirectZeroPage, Access: ReadAccess},
+	STA_IZP: {Code: STA_IZP, Mnemonic: "STA", Operation: sta, ByteSize: 2, Cycles: 5, Mode: IndirectZeroPage, Access: WriteAccess},
+	LDA_IZP: {Code: LDA_IZP, Mnemonic: "LDA", Operation: lda, ByteSize: 2, Cycles: 5, Mode: IndirectZeroPage, Access: ReadAccess},
+	CMP_IZP: {Code: CMP_IZP, Mnemonic: "CMP", Operation: cmp, ByteSize: 2, Cycles: 5, Mode: IndirectZeroPage, Access: ReadAccess},
+	SBC_IZP: {Code: SBC_IZP, Mnemonic: "SBC", Operation: sbc, ByteSize: 2, Cycles: 5, Mode: IndirectZeroPage, Access: ReadAccess},
+
+	INC_ACC: {Code: INC_ACC, Mnemonic: "INC", Operation: inc, ByteSize: 1, Cycles: 2, Mode: Accumulator, Access: ModifyAccess},
+	DEC_ACC: {Code: DEC_ACC, Mnemonic: "DEC", Operation: dec, ByteSize: 1, Cycles: 2, Mode: Accumulator, Access: ModifyAccess},
+
+	BIT_IMM: {Code: BIT_IMM, Mnemonic: "BIT", Operation: bitImmediate, ByteSize: 2, Cycles: 2, Mode: Immediate, Access: ReadAccess},
+	BIT_ZRX: {Code: BIT_ZRX, Mnemonic: "BIT", Operation: bit, ByteSize: 2, Cycles: 4, Mode: ZeroPageX, Access: ReadAccess},
+	BIT_ABX: {Code: BIT_ABX, Mnemonic: "BIT", Operation: bit, ByteSize: 3, Cycles: 4, Mode: AbsoluteX1, Access: ReadAccess},
+}
+
+var cmosNops = cmosNopTable()
+
+// every opcode left undefined on the 65C02 is a NOP, the size and timing
+// depend on the column of the opcode matrix
+func cmosNopTable() map[uint8]Opcode {
+	table := map[uint8]Opcode{}
+	for hi := 0x00; hi < 0x100; hi += 0x10 {
+		for _, lo := range []int{0x03, 0x07, 0x0B, 0x0F} {
+			code := uint8(hi | lo)
+			table[code] = Opcode{Code: code, Mnemonic: "NOP", ByteSize: 1, Cycles: 1, Mode: Implied, program: []microStep{}}
+		}
+	}
+
+	for _, code := range []uint8{0x02, 0x22, 0x42, 0x62, 0x82, 0xC2, 0xE2} {
+		table[code] = Opcode{Code: code, Mnemonic: "NOP", Operation: skb, ByteSize: 2, Cycles: 2, Mode: Immediate, Access: ReadAccess}
+	}
+	table[0x44] = Opcode{Code: 0x44, Mnemonic: "NOP", Operation: skb, ByteSize: 2, Cycles: 3, Mode: ZeroPage, Access: ReadAccess}
+	for _, code := range []uint8{0x54, 0xD4, 0xF4} {
+		table[code] = Opcode{Code: code, Mnemonic: "NOP", Operation: skb, ByteSize: 2, Cycles: 4, Mode: ZeroPageX, Access: ReadAccess}
+	}
+	table[0x5C] = Opcode{Code: 0x5C, Mnemonic: "NOP", ByteSize: 3, Cycles: 8, Mode: Absolute, program: nop5CProgram}
+	for _, code := range []uint8{0xDC, 0xFC} {
+		table[code] = Opcode{Code: code, Mnemonic: "NOP", Operation: skb, ByteSize: 3, Cycles: 4, Mode: Absolute, Access: ReadAccess}
+	}
+	return table
+}
+
+// $5C reads its absolute operand then keeps the bus busy for four cycles
+var nop5CProgram = []microStep{fetchAddrLo, fetchAddrHi, dummyReadAddr, dummyReadAddr, dummyReadAddr, dummyReadAddr, dummyReadAddr}
+
+var rockwellOpcodes = rockwellTable()
+
+func rockwellTable() map[uint8]Opcode {
+	table := map[uint8]Opcode{}
+	for bit := uint8(0); bit < 8; bit++ {
+		rmbCode := RMB0_ZER + bit<<4
+		smbCode := SMB0_ZER + bit<<4
+		bbrCode := BBR0_ZRL + bit<<4
+		bbsCode := BBS0_ZRL + bit<<4
+		digit := string(rune('0' + bit))
+
+		table[rmbCode] = Opcode{Code: rmbCode, Mnemonic: "RMB" + digit, Operation: rmb(bit), ByteSize: 2, Cycles: 5, Mode: ZeroPage, Access: ModifyAccess}
+		table[smbCode] = Opcode{Code: smbCode, Mnemonic: "SMB" + digit, Operation: smb(bit), ByteSize: 2, Cycles: 5, Mode: ZeroPage, Access: ModifyAccess}
+		table[bbrCode] = Opcode{Code: bbrCode, Mnemonic: "BBR" + digit, Operation: bbr(bit), ByteSize: 3, Cycles: 5, Mode: ZeroPageRelative, Access: BranchAccess}
+		table[bbsCode] = Opcode{Code: bbsCode, Mnemonic: "BBS" + digit, Operation: bbs(bit), ByteSize: 3, Cycles: 5, Mode: ZeroPageRelative, Access: BranchAccess}
+	}
+	return table
+}
+
+var wdcOpcodes = map[uint8]Opcode{
+	WAI_IMP: {Code: WAI_IMP, Mnemonic: "WAI", ByteSize: 1, Cycles: 3, Mode: Implied, program: waiProgram},
+	STP_IMP: {Code: STP_IMP, Mnemonic: "STP", ByteSize: 1, Cycles: 3, Mode: Implied, program: stpProgram},
+}
+
+func bra(c *Cpu, value uint8) uint8 {
+	return taken(true)
+}
+
+func phx(c *Cpu, value uint8) uint8 {
+	return uint8(c.XIndex)
+}
+
+func phy(c *Cpu, value uint8) uint8 {
+	return uint8(c.YIndex)
+}
+
+func plx(c *Cpu, value uint8) uint8 {
+	c.XIndex = Register8(value)
+	c.updateZeroAndNegativeFlags(c.XIndex)
+	return 0
+}
+
+func ply(c *Cpu, value uint8) uint8 {
+	c.YIndex = Register8(value)
+	c.updateZeroAndNegativeFlags(c.YIndex)
+	return 0
+}
+
+func stz(c *Cpu, value uint8) uint8 {
+	return 0
+}
+
+func trb(c *Cpu, value uint8) uint8 {
+	c.Status.Set(1, value&uint8(c.Accumulator) == 0)
+	return value &^ uint8(c.Accumulator)
+}
+
+func tsb(c *Cpu, value uint8) uint8 {
+	c.Status.Set(1, value&uint8(c.Accumulator) == 0)
+	return value | uint8(c.Accumulator)
+}
+
+func rmb(bit uint8) func(c *Cpu, value uint8) uint8 {
+	return func(c *Cpu, value uint8) uint8 {
+		return value &^ (1 << bit)
+	}
+}
+
+func smb(bit uint8) func(c *Cpu, value uint8) uint8 {
+	return func(c *Cpu, value uint8) uint8 {
+		return value | (1 << bit)
+	}
+}
+
+// the branch offset follows the zero page operand, the microcode hands
+// over the zero page value
+func bbr(bit uint8) func(c *Cpu, value uint8) uint8 {
+	return func(c *Cpu, value uint8) uint8 {
+		return taken(value&(1<<bit) == 0)
+	}
+}
+
+func bbs(bit uint8) func(c *Cpu, value uint8) uint8 {
+	return func(c *Cpu, value uint8) uint8 {
+		return taken(value&(1<<bit) != 0)
+	}
+}
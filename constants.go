@@ -17,6 +17,9 @@ const (
 	IndirectY1
 	Accumulator
 	Relative
+	IndirectZeroPage
+	AbsoluteIndirectX
+	ZeroPageRelative
 )
 
 const (
@@ -203,209 +206,559 @@ const (
 	OTHER = 0xFF
 )
 
+// Undocumented NMOS opcodes. Mnemonics with several encodings for the same
+// addressing mode carry the opcode as a suffix.
+const (
+	NOP_IMP_1A = 0x1A
+	NOP_IMP_3A = 0x3A
+	NOP_IMP_5A = 0x5A
+	NOP_IMP_7A = 0x7A
+	NOP_IMP_DA = 0xDA
+	NOP_IMP_FA = 0xFA
+	NOP_IMM_80 = 0x80
+	NOP_IMM_82 = 0x82
+	NOP_IMM_89 = 0x89
+	NOP_IMM_C2 = 0xC2
+	NOP_IMM_E2 = 0xE2
+	NOP_ZER_04 = 0x04
+	NOP_ZER_44 = 0x44
+	NOP_ZER_64 = 0x64
+	NOP_ZRX_14 = 0x14
+	NOP_ZRX_34 = 0x34
+	NOP_ZRX_54 = 0x54
+	NOP_ZRX_74 = 0x74
+	NOP_ZRX_D4 = 0xD4
+	NOP_ZRX_F4 = 0xF4
+	NOP_ABS_0C = 0x0C
+	NOP_ABX_1C = 0x1C
+	NOP_ABX_3C = 0x3C
+	NOP_ABX_5C = 0x5C
+	NOP_ABX_7C = 0x7C
+	NOP_ABX_DC = 0xDC
+	NOP_ABX_FC = 0xFC
+
+	JAM_IMP_02 = 0x02
+	JAM_IMP_12 = 0x12
+	JAM_IMP_22 = 0x22
+	JAM_IMP_32 = 0x32
+	JAM_IMP_42 = 0x42
+	JAM_IMP_52 = 0x52
+	JAM_IMP_62 = 0x62
+	JAM_IMP_72 = 0x72
+	JAM_IMP_92 = 0x92
+	JAM_IMP_B2 = 0xB2
+	JAM_IMP_D2 = 0xD2
+	JAM_IMP_F2 = 0xF2
+
+	SLO_ZER = 0x07
+	SLO_ZRX = 0x17
+	SLO_ABS = 0x0F
+	SLO_ABX = 0x1F
+	SLO_ABY = 0x1B
+	SLO_IDX = 0x03
+	SLO_IDY = 0x13
+
+	RLA_ZER = 0x27
+	RLA_ZRX = 0x37
+	RLA_ABS = 0x2F
+	RLA_ABX = 0x3F
+	RLA_ABY = 0x3B
+	RLA_IDX = 0x23
+	RLA_IDY = 0x33
+
+	SRE_ZER = 0x47
+	SRE_ZRX = 0x57
+	SRE_ABS = 0x4F
+	SRE_ABX = 0x5F
+	SRE_ABY = 0x5B
+	SRE_IDX = 0x43
+	SRE_IDY = 0x53
+
+	RRA_ZER = 0x67
+	RRA_ZRX = 0x77
+	RRA_ABS = 0x6F
+	RRA_ABX = 0x7F
+	RRA_ABY = 0x7B
+	RRA_IDX = 0x63
+	RRA_IDY = 0x73
+
+	DCP_ZER = 0xC7
+	DCP_ZRX = 0xD7
+	DCP_ABS = 0xCF
+	DCP_ABX = 0xDF
+	DCP_ABY = 0xDB
+	DCP_IDX = 0xC3
+	DCP_IDY = 0xD3
+
+	ISC_ZER = 0xE7
+	ISC_ZRX = 0xF7
+	ISC_ABS = 0xEF
+	ISC_ABX = 0xFF
+	ISC_ABY = 0xFB
+	ISC_IDX = 0xE3
+	ISC_IDY = 0xF3
+
+	SAX_ZER = 0x87
+	SAX_ZRY = 0x97
+	SAX_ABS = 0x8F
+	SAX_IDX = 0x83
+
+	LAX_ZER = 0xA7
+	LAX_ZRY = 0xB7
+	LAX_ABS = 0xAF
+	LAX_ABY = 0xBF
+	LAX_IDX = 0xA3
+	LAX_IDY = 0xB3
+
+	ANC_IMM_0B = 0x0B
+	ANC_IMM_2B = 0x2B
+	ALR_IMM    = 0x4B
+	ARR_IMM    = 0x6B
+	SBX_IMM    = 0xCB
+	SBC_IMM_EB = 0xEB
+
+	// unstable, see the comments on their operations
+	XAA_IMM = 0x8B
+	LXA_IMM = 0xAB
+	AHX_ABY = 0x9F
+	AHX_IDY = 0x93
+	TAS_ABY = 0x9B
+	SHY_ABX = 0x9C
+	SHX_ABY = 0x9E
+	LAS_ABY = 0xBB
+)
+
+// 65C02 opcodes, they reuse encodings that are undocumented on the NMOS
+const (
+	BRA_REL = 0x80
+
+	PHX_IMP = 0xDA
+	PHY_IMP = 0x5A
+	PLX_IMP = 0xFA
+	PLY_IMP = 0x7A
+
+	STZ_ZER = 0x64
+	STZ_ZRX = 0x74
+	STZ_ABS = 0x9C
+	STZ_ABX = 0x9E
+
+	TRB_ZER = 0x14
+	TRB_ABS = 0x1C
+	TSB_ZER = 0x04
+	TSB_ABS = 0x0C
+
+	ORA_IZP = 0x12
+	AND_IZP = 0x32
+	EOR_IZP = 0x52
+	ADC_IZP = 0x72
+	STA_IZP = 0x92
+	LDA_IZP = 0xB2
+	CMP_IZP = 0xD2
+	SBC_IZP = 0xF2
+
+	INC_ACC = 0x1A
+	DEC_ACC = 0x3A
+
+	BIT_IMM = 0x89
+	BIT_ZRX = 0x34
+	BIT_ABX = 0x3C
+
+	JMP_IAX = 0x7C
+)
+
+// Rockwell and WDC 65C02 opcodes, the bit number is part of the opcode
+const (
+	RMB0_ZER = 0x07
+	RMB1_ZER = 0x17
+	RMB2_ZER = 0x27
+	RMB3_ZER = 0x37
+	RMB4_ZER = 0x47
+	RMB5_ZER = 0x57
+	RMB6_ZER = 0x67
+	RMB7_ZER = 0x77
+
+	SMB0_ZER = 0x87
+	SMB1_ZER = 0x97
+	SMB2_ZER = 0xA7
+	SMB3_ZER = 0xB7
+	SMB4_ZER = 0xC7
+	SMB5_ZER = 0xD7
+	SMB6_ZER = 0xE7
+	SMB7_ZER = 0xF7
+
+	BBR0_ZRL = 0x0F
+	BBR1_ZRL = 0x1F
+	BBR2_ZRL = 0x2F
+	BBR3_ZRL = 0x3F
+	BBR4_ZRL = 0x4F
+	BBR5_ZRL = 0x5F
+	BBR6_ZRL = 0x6F
+	BBR7_ZRL = 0x7F
+
+	BBS0_ZRL = 0x8F
+	BBS1_ZRL = 0x9F
+	BBS2_ZRL = 0xAF
+	BBS3_ZRL = 0xBF
+	BBS4_ZRL = 0xCF
+	BBS5_ZRL = 0xDF
+	BBS6_ZRL = 0xEF
+	BBS7_ZRL = 0xFF
+
+	WAI_IMP = 0xCB
+	STP_IMP = 0xDB
+)
+
+// Access tells how an instruction uses its operand, together with the
+// addressing mode it selects the microcode executing it.
+const (
+	NoAccess = iota
+	ReadAccess
+	WriteAccess
+	ModifyAccess
+	BranchAccess
+	PushAccess
+	PullAccess
+	UnstableAccess
+)
+
+// Opcode describes one instruction. Cycles is the base cycle count, the
+// microcode adds the page crossing and branching penalties.
+//
+// Operation receives the operand and returns the value to write back. Reads
+// ignore the result, writes and pushes ignore the operand, branches return
+// non zero when taken. Instructions with a program of their own, like JSR
+// or BRK, have no Operation.
 type Opcode struct {
 	Code      uint8
+	Mnemonic  string
 	ByteSize  int
 	Cycles    int
 	Mode      int
-	Operation func(cpu *Cpu, bus Bus, mode int)
+	Access    int
+	Operation func(cpu *Cpu, value uint8) uint8
+
+	program []microStep
 }
 
-var Opcodes = map[uint8]Opcode{
+var officialOpcodes = map[uint8]Opcode{
 
 	// Load Operations
-	LDA_IMM: {Code: LDA_IMM, Operation: lda, ByteSize: 2, Cycles: 2, Mode: Immediate},
-	LDA_ZER: {Code: LDA_ZER, Operation: lda, ByteSize: 2, Cycles: 3, Mode: ZeroPage},
-	LDA_ZRX: {Code: LDA_ZRX, Operation: lda, ByteSize: 2, Cycles: 4, Mode: ZeroPageX},
-	LDA_ABS: {Code: LDA_ABS, Operation: lda, ByteSize: 3, Cycles: 4, Mode: Absolute},
-	LDA_ABX: {Code: LDA_ABX, Operation: lda, ByteSize: 3, Cycles: 4, Mode: AbsoluteX1},
-	LDA_ABY: {Code: LDA_ABY, Operation: lda, ByteSize: 3, Cycles: 4, Mode: AbsoluteY1},
-	LDA_IDX: {Code: LDA_IDX, Operation: lda, ByteSize: 2, Cycles: 6, Mode: IndirectX},
-	LDA_IDY: {Code: LDA_IDY, Operation: lda, ByteSize: 2, Cycles: 5, Mode: IndirectY1},
-
-	LDX_IMM: {Code: LDX_IMM, Operation: ldx, ByteSize: 2, Cycles: 2, Mode: Immediate},
-	LDX_ZER: {Code: LDX_ZER, Operation: ldx, ByteSize: 2, Cycles: 3, Mode: ZeroPage},
-	LDX_ZRY: {Code: LDX_ZRY, Operation: ldx, ByteSize: 2, Cycles: 4, Mode: ZeroPageY},
-	LDX_ABS: {Code: LDX_ABS, Operation: ldx, ByteSize: 3, Cycles: 4, Mode: Absolute},
-	LDX_ABY: {Code: LDX_ABY, Operation: ldx, ByteSize: 3, Cycles: 4, Mode: AbsoluteY1},
-
-	LDY_IMM: {Code: LDY_IMM, Operation: ldy, ByteSize: 2, Cycles: 2, Mode: Immediate},
-	LDY_ZER: {Code: LDY_ZER, Operation: ldy, ByteSize: 2, Cycles: 3, Mode: ZeroPage},
-	LDY_ZRX: {Code: LDY_ZRX, Operation: ldy, ByteSize: 2, Cycles: 4, Mode: ZeroPageX},
-	LDY_ABS: {Code: LDY_ABS, Operation: ldy, ByteSize: 3, Cycles: 4, Mode: Absolute},
-	LDY_ABX: {Code: LDY_ABX, Operation: ldy, ByteSize: 3, Cycles: 4, Mode: AbsoluteX1},
+	LDA_IMM: {Code: LDA_IMM, Mnemonic: "LDA", Operation: lda, ByteSize: 2, Cycles: 2, Mode: Immediate, Access: ReadAccess},
+	LDA_ZER: {Code: LDA_ZER, Mnemonic: "LDA", Operation: lda, ByteSize: 2, Cycles: 3, Mode: ZeroPage, Access: ReadAccess},
+	LDA_ZRX: {Code: LDA_ZRX, Mnemonic: "LDA", Operation: lda, ByteSize: 2, Cycles: 4, Mode: ZeroPageX, Access: ReadAccess},
+	LDA_ABS: {Code: LDA_ABS, Mnemonic: "LDA", Operation: lda, ByteSize: 3, Cycles: 4, Mode: Absolute, Access: ReadAccess},
+	LDA_ABX: {Code: LDA_ABX, Mnemonic: "LDA", Operation: lda, ByteSize: 3, Cycles: 4, Mode: AbsoluteX1, Access: ReadAccess},
+	LDA_ABY: {Code: LDA_ABY, Mnemonic: "LDA", Operation: lda, ByteSize: 3, Cycles: 4, Mode: AbsoluteY1, Access: ReadAccess},
+	LDA_IDX: {Code: LDA_IDX, Mnemonic: "LDA", Operation: lda, ByteSize: 2, Cycles: 6, Mode: IndirectX, Access: ReadAccess},
+	LDA_IDY: {Code: LDA_IDY, Mnemonic: "LDA", Operation: lda, ByteSize: 2, Cycles: 5, Mode: IndirectY1, Access: ReadAccess},
+
+	LDX_IMM: {Code: LDX_IMM, Mnemonic: "LDX", Operation: ldx, ByteSize: 2, Cycles: 2, Mode: Immediate, Access: ReadAccess},
+	LDX_ZER: {Code: LDX_ZER, Mnemonic: "LDX", Operation: ldx, ByteSize: 2, Cycles: 3, Mode: ZeroPage, Access: ReadAccess},
+	LDX_ZRY: {Code: LDX_ZRY, Mnemonic: "LDX", Operation: ldx, ByteSize: 2, Cycles: 4, Mode: ZeroPageY, Access: ReadAccess},
+	LDX_ABS: {Code: LDX_ABS, Mnemonic: "LDX", Operation: ldx, ByteSize: 3, Cycles: 4, Mode: Absolute, Access: ReadAccess},
+	LDX_ABY: {Code: LDX_ABY, Mnemonic: "LDX", Operation: ldx, ByteSize: 3, Cycles: 4, Mode: AbsoluteY1, Access: ReadAccess},
+
+	LDY_IMM: {Code: LDY_IMM, Mnemonic: "LDY", Operation: ldy, ByteSize: 2, Cycles: 2, Mode: Immediate, Access: ReadAccess},
+	LDY_ZER: {Code: LDY_ZER, Mnemonic: "LDY", Operation: ldy, ByteSize: 2, Cycles: 3, Mode: ZeroPage, Access: ReadAccess},
+	LDY_ZRX: {Code: LDY_ZRX, Mnemonic: "LDY", Operation: ldy, ByteSize: 2, Cycles: 4, Mode: ZeroPageX, Access: ReadAccess},
+	LDY_ABS: {Code: LDY_ABS, Mnemonic: "LDY", Operation: ldy, ByteSize: 3, Cycles: 4, Mode: Absolute, Access: ReadAccess},
+	LDY_ABX: {Code: LDY_ABX, Mnemonic: "LDY", Operation: ldy, ByteSize: 3, Cycles: 4, Mode: AbsoluteX1, Access: ReadAccess},
 
 	// Store Operations
-	STA_ZER: {Code: STA_ZER, Operation: sta, ByteSize: 2, Cycles: 3, Mode: ZeroPage},
-	STA_ZRX: {Code: STA_ZRX, Operation: sta, ByteSize: 2, Cycles: 4, Mode: ZeroPageX},
-	STA_ABS: {Code: STA_ABS, Operation: sta, ByteSize: 3, Cycles: 4, Mode: Absolute},
-	STA_ABX: {Code: STA_ABX, Operation: sta, ByteSize: 3, Cycles: 5, Mode: AbsoluteX},
-	STA_ABY: {Code: STA_ABY, Operation: sta, ByteSize: 3, Cycles: 5, Mode: AbsoluteY},
-	STA_IDX: {Code: STA_IDX, Operation: sta, ByteSize: 2, Cycles: 6, Mode: IndirectX},
-	STA_IDY: {Code: STA_IDY, Operation: sta, ByteSize: 2, Cycles: 6, Mode: IndirectY},
-
-	STX_ZER: {Code: STX_ZER, Operation: stx, ByteSize: 2, Cycles: 3, Mode: ZeroPage},
-	STX_ZRY: {Code: STX_ZRY, Operation: stx, ByteSize: 2, Cycles: 4, Mode: ZeroPageY},
-	STX_ABS: {Code: STX_ABS, Operation: stx, ByteSize: 3, Cycles: 4, Mode: Absolute},
-
-	STY_ZER: {Code: STY_ZER, Operation: sty, ByteSize: 2, Cycles: 3, Mode: ZeroPage},
-	STY_ZRX: {Code: STY_ZRX, Operation: sty, ByteSize: 2, Cycles: 4, Mode: ZeroPageX},
-	STY_ABS: {Code: STY_ABS, Operation: sty, ByteSize: 3, Cycles: 4, Mode: Absolute},
+	STA_ZER: {Code: STA_ZER, Mnemonic: "STA", Operation: sta, ByteSize: 2, Cycles: 3, Mode: ZeroPage, Access: WriteAccess},
+	STA_ZRX: {Code: STA_ZRX, Mnemonic: "STA", Operation: sta, ByteSize: 2, Cycles: 4, Mode: ZeroPageX, Access: WriteAccess},
+	STA_ABS: {Code: STA_ABS, Mnemonic: "STA", Operation: sta, ByteSize: 3, Cycles: 4, Mode: Absolute, Access: WriteAccess},
+	STA_ABX: {Code: STA_ABX, Mnemonic: "STA", Operation: sta, ByteSize: 3, Cycles: 5, Mode: AbsoluteX, Access: WriteAccess},
+	STA_ABY: {Code: STA_ABY, Mnemonic: "STA", Operation: sta, ByteSize: 3, Cycles: 5, Mode: AbsoluteY, Access: WriteAccess},
+	STA_IDX: {Code: STA_IDX, Mnemonic: "STA", Operation: sta, ByteSize: 2, Cycles: 6, Mode: IndirectX, Access: WriteAccess},
+	STA_IDY: {Code: STA_IDY, Mnemonic: "STA", Operation: sta, ByteSize: 2, Cycles: 6, Mode: IndirectY, Access: WriteAccess},
+
+	STX_ZER: {Code: STX_ZER, Mnemonic: "STX", Operation: stx, ByteSize: 2, Cycles: 3, Mode: ZeroPage, Access: WriteAccess},
+	STX_ZRY: {Code: STX_ZRY, Mnemonic: "STX", Operation: stx, ByteSize: 2, Cycles: 4, Mode: ZeroPageY, Access: WriteAccess},
+	STX_ABS: {Code: STX_ABS, Mnemonic: "STX", Operation: stx, ByteSize: 3, Cycles: 4, Mode: Absolute, Access: WriteAccess},
+
+	STY_ZER: {Code: STY_ZER, Mnemonic: "STY", Operation: sty, ByteSize: 2, Cycles: 3, Mode: ZeroPage, Access: WriteAccess},
+	STY_ZRX: {Code: STY_ZRX, Mnemonic: "STY", Operation: sty, ByteSize: 2, Cycles: 4, Mode: ZeroPageX, Access: WriteAccess},
+	STY_ABS: {Code: STY_ABS, Mnemonic: "STY", Operation: sty, ByteSize: 3, Cycles: 4, Mode: Absolute, Access: WriteAccess},
 
 	// Register Transfers
-	TAX_IMP: {Code: TAX_IMP, Operation: tax, ByteSize: 1, Cycles: 2, Mode: Implied},
-	TAY_IMP: {Code: TAY_IMP, Operation: tay, ByteSize: 1, Cycles: 2, Mode: Implied},
-	TXA_IMP: {Code: TXA_IMP, Operation: txa, ByteSize: 1, Cycles: 2, Mode: Implied},
-	TYA_IMP: {Code: TYA_IMP, Operation: tya, ByteSize: 1, Cycles: 2, Mode: Implied},
+	TAX_IMP: {Code: TAX_IMP, Mnemonic: "TAX", Operation: tax, ByteSize: 1, Cycles: 2, Mode: Implied},
+	TAY_IMP: {Code: TAY_IMP, Mnemonic: "TAY", Operation: tay, ByteSize: 1, Cycles: 2, Mode: Implied},
+	TXA_IMP: {Code: TXA_IMP, Mnemonic: "TXA", Operation: txa, ByteSize: 1, Cycles: 2, Mode: Implied},
+	TYA_IMP: {Code: TYA_IMP, Mnemonic: "TYA", Operation: tya, ByteSize: 1, Cycles: 2, Mode: Implied},
 
 	// Stack
-	TSX_IMP: {Code: TSX_IMP, Operation: tsx, ByteSize: 1, Cycles: 2, Mode: Implied},
-	TXS_IMP: {Code: TXS_IMP, Operation: txs, ByteSize: 1, Cycles: 2, Mode: Implied},
-	PHA_IMP: {Code: PHA_IMP, Operation: pha, ByteSize: 1, Cycles: 3, Mode: Implied},
-	PHP_IMP: {Code: PHP_IMP, Operation: php, ByteSize: 1, Cycles: 3, Mode: Implied},
-	PLA_IMP: {Code: PLA_IMP, Operation: pla, ByteSize: 1, Cycles: 4, Mode: Implied},
-	PLP_IMP: {Code: PLP_IMP, Operation: plp, ByteSize: 1, Cycles: 4, Mode: Implied},
+	TSX_IMP: {Code: TSX_IMP, Mnemonic: "TSX", Operation: tsx, ByteSize: 1, Cycles: 2, Mode: Implied},
+	TXS_IMP: {Code: TXS_IMP, Mnemonic: "TXS", Operation: txs, ByteSize: 1, Cycles: 2, Mode: Implied},
+	PHA_IMP: {Code: PHA_IMP, Mnemonic: "PHA", Operation: pha, ByteSize: 1, Cycles: 3, Mode: Implied, Access: PushAccess},
+	PHP_IMP: {Code: PHP_IMP, Mnemonic: "PHP", Operation: php, ByteSize: 1, Cycles: 3, Mode: Implied, Access: PushAccess},
+	PLA_IMP: {Code: PLA_IMP, Mnemonic: "PLA", Operation: pla, ByteSize: 1, Cycles: 4, Mode: Implied, Access: PullAccess},
+	PLP_IMP: {Code: PLP_IMP, Mnemonic: "PLP", Operation: plp, ByteSize: 1, Cycles: 4, Mode: Implied, Access: PullAccess},
 
 	// Logical
-	AND_IMM: {Code: AND_IMM, Operation: and, ByteSize: 2, Cycles: 2, Mode: Immediate},
-	AND_ZER: {Code: AND_ZER, Operation: and, ByteSize: 2, Cycles: 3, Mode: ZeroPage},
-	AND_ZRX: {Code: AND_ZRX, Operation: and, ByteSize: 2, Cycles: 4, Mode: ZeroPageX},
-	AND_ABS: {Code: AND_ABS, Operation: and, ByteSize: 3, Cycles: 4, Mode: Absolute},
-	AND_ABX: {Code: AND_ABX, Operation: and, ByteSize: 3, Cycles: 4, Mode: AbsoluteX1},
-	AND_ABY: {Code: AND_ABY, Operation: and, ByteSize: 3, Cycles: 4, Mode: AbsoluteY1},
-	AND_IDX: {Code: AND_IDX, Operation: and, ByteSize: 2, Cycles: 6, Mode: IndirectX},
-	AND_IDY: {Code: AND_IDY, Operation: and, ByteSize: 2, Cycles: 5, Mode: IndirectY1},
-
-	EOR_IMM: {Code: EOR_IMM, Operation: eor, ByteSize: 2, Cycles: 2, Mode: Immediate},
-	EOR_ZER: {Code: EOR_ZER, Operation: eor, ByteSize: 2, Cycles: 3, Mode: ZeroPage},
-	EOR_ZRX: {Code: EOR_ZRX, Operation: eor, ByteSize: 2, Cycles: 4, Mode: ZeroPageX},
-	EOR_ABS: {Code: EOR_ABS, Operation: eor, ByteSize: 3, Cycles: 4, Mode: Absolute},
-	EOR_ABX: {Code: EOR_ABX, Operation: eor, ByteSize: 3, Cycles: 4, Mode: AbsoluteX1},
-	EOR_ABY: {Code: EOR_ABY, Operation: eor, ByteSize: 3, Cycles: 4, Mode: AbsoluteY1},
-	EOR_IDX: {Code: EOR_IDX, Operation: eor, ByteSize: 2, Cycles: 6, Mode: IndirectX},
-	EOR_IDY: {Code: EOR_IDY, Operation: eor, ByteSize: 2, Cycles: 5, Mode: IndirectY1},
-
-	ORA_IMM: {Code: ORA_IMM, Operation: aor, ByteSize: 2, Cycles: 2, Mode: Immediate},
-	ORA_ZER: {Code: ORA_ZER, Operation: aor, ByteSize: 2, Cycles: 3, Mode: ZeroPage},
-	ORA_ZRX: {Code: ORA_ZRX, Operation: aor, ByteSize: 2, Cycles: 4, Mode: ZeroPageX},
-	ORA_ABS: {Code: ORA_ABS, Operation: aor, ByteSize: 3, Cycles: 4, Mode: Absolute},
-	ORA_ABX: {Code: ORA_ABX, Operation: aor, ByteSize: 3, Cycles: 4, Mode: AbsoluteX1},
-	ORA_ABY: {Code: ORA_ABY, Operation: aor, ByteSize: 3, Cycles: 4, Mode: AbsoluteY1},
-	ORA_IDX: {Code: ORA_IDX, Operation: aor, ByteSize: 2, Cycles: 6, Mode: IndirectX},
-	ORA_IDY: {Code: ORA_IDY, Operation: aor, ByteSize: 2, Cycles: 5, Mode: IndirectY1},
-
-	BIT_ZER: {Code: BIT_ZER, Operation: bit, ByteSize: 2, Cycles: 3, Mode: ZeroPage},
-	BIT_ABS: {Code: BIT_ABS, Operation: bit, ByteSize: 3, Cycles: 4, Mode: Absolute},
+	AND_IMM: {Code: AND_IMM, Mnemonic: "AND", Operation: and, ByteSize: 2, Cycles: 2, Mode: Immediate, Access: ReadAccess},
+	AND_ZER: {Code: AND_ZER, Mnemonic: "AND", Operation: and, ByteSize: 2, Cycles: 3, Mode: ZeroPage, Access: ReadAccess},
+	AND_ZRX: {Code: AND_ZRX, Mnemonic: "AND", Operation: and, ByteSize: 2, Cycles: 4, Mode: ZeroPageX, Access: ReadAccess},
+	AND_ABS: {Code: AND_ABS, Mnemonic: "AND", Operation: and, ByteSize: 3, Cycles: 4, Mode: Absolute, Access: ReadAccess},
+	AND_ABX: {Code: AND_ABX, Mnemonic: "AND", Operation: and, ByteSize: 3, Cycles: 4, Mode: AbsoluteX1, Access: ReadAccess},
+	AND_ABY: {Code: AND_ABY, Mnemonic: "AND", Operation: and, ByteSize: 3, Cycles: 4, Mode: AbsoluteY1, Access: ReadAccess},
+	AND_IDX: {Code: AND_IDX, Mnemonic: "AND", Operation: and, ByteSize: 2, Cycles: 6, Mode: IndirectX, Access: ReadAccess},
+	AND_IDY: {Code: AND_IDY, Mnemonic: "AND", Operation: and, ByteSize: 2, Cycles: 5, Mode: IndirectY1, Access: ReadAccess},
+
+	EOR_IMM: {Code: EOR_IMM, Mnemonic: "EOR", Operation: eor, ByteSize: 2, Cycles: 2, Mode: Immediate, Access: ReadAccess},
+	EOR_ZER: {Code: EOR_ZER, Mnemonic: "EOR", Operation: eor, ByteSize: 2, Cycles: 3, Mode: ZeroPage, Access: ReadAccess},
+	EOR_ZRX: {Code: EOR_ZRX, Mnemonic: "EOR", Operation: eor, ByteSize: 2, Cycles: 4, Mode: ZeroPageX, Access: ReadAccess},
+	EOR_ABS: {Code: EOR_ABS, Mnemonic: "EOR", Operation: eor, ByteSize: 3, Cycles: 4, Mode: Absolute, Access: ReadAccess},
+	EOR_ABX: {Code: EOR_ABX, Mnemonic: "EOR", Operation: eor, ByteSize: 3, Cycles: 4, Mode: AbsoluteX1, Access: ReadAccess},
+	EOR_ABY: {Code: EOR_ABY, Mnemonic: "EOR", Operation: eor, ByteSize: 3, Cycles: 4, Mode: AbsoluteY1, Access: ReadAccess},
+	EOR_IDX: {Code: EOR_IDX, Mnemonic: "EOR", Operation: eor, ByteSize: 2, Cycles: 6, Mode: IndirectX, Access: ReadAccess},
+	EOR_IDY: {Code: EOR_IDY, Mnemonic: "EOR", Operation: eor, ByteSize: 2, Cycles: 5, Mode: IndirectY1, Access: ReadAccess},
+
+	ORA_IMM: {Code: ORA_IMM, Mnemonic: "ORA", Operation: aor, ByteSize: 2, Cycles: 2, Mode: Immediate, Access: ReadAccess},
+	ORA_ZER: {Code: ORA_ZER, Mnemonic: "ORA", Operation: aor, ByteSize: 2, Cycles: 3, Mode: ZeroPage, Access: ReadAccess},
+	ORA_ZRX: {Code: ORA_ZRX, Mnemonic: "ORA", Operation: aor, ByteSize: 2, Cycles: 4, Mode: ZeroPageX, Access: ReadAccess},
+	ORA_ABS: {Code: ORA_ABS, Mnemonic: "ORA", Operation: aor, ByteSize: 3, Cycles: 4, Mode: Absolute, Access: ReadAccess},
+	ORA_ABX: {Code: ORA_ABX, Mnemonic: "ORA", Operation: aor, ByteSize: 3, Cycles: 4, Mode: AbsoluteX1, Access: ReadAccess},
+	ORA_ABY: {Code: ORA_ABY, Mnemonic: "ORA", Operation: aor, ByteSize: 3, Cycles: 4, Mode: AbsoluteY1, Access: ReadAccess},
+	ORA_IDX: {Code: ORA_IDX, Mnemonic: "ORA", Operation: aor, ByteSize: 2, Cycles: 6, Mode: IndirectX, Access: ReadAccess},
+	ORA_IDY: {Code: ORA_IDY, Mnemonic: "ORA", Operation: aor, ByteSize: 2, Cycles: 5, Mode: IndirectY1, Access: ReadAccess},
+
+	BIT_ZER: {Code: BIT_ZER, Mnemonic: "BIT", Operation: bit, ByteSize: 2, Cycles: 3, Mode: ZeroPage, Access: ReadAccess},
+	BIT_ABS: {Code: BIT_ABS, Mnemonic: "BIT", Operation: bit, ByteSize: 3, Cycles: 4, Mode: Absolute, Access: ReadAccess},
 
 	// Arithmetic
-	ADC_IMM: {Code: ADC_IMM, Operation: adc, ByteSize: 2, Cycles: 2, Mode: Immediate},
-	ADC_ZER: {Code: ADC_ZER, Operation: adc, ByteSize: 2, Cycles: 3, Mode: ZeroPage},
-	ADC_ZRX: {Code: ADC_ZRX, Operation: adc, ByteSize: 2, Cycles: 4, Mode: ZeroPageX},
-	ADC_ABS: {Code: ADC_ABS, Operation: adc, ByteSize: 3, Cycles: 4, Mode: Absolute},
-	ADC_ABX: {Code: ADC_ABX, Operation: adc, ByteSize: 3, Cycles: 4, Mode: AbsoluteX1},
-	ADC_ABY: {Code: ADC_ABY, Operation: adc, ByteSize: 3, Cycles: 4, Mode: AbsoluteY1},
-	ADC_IDX: {Code: ADC_IDX, Operation: adc, ByteSize: 2, Cycles: 6, Mode: IndirectX},
-	ADC_IDY: {Code: ADC_IDY, Operation: adc, ByteSize: 2, Cycles: 5, Mode: IndirectY1},
-
-	SBC_IMM: {Code: SBC_IMM, Operation: sbc, ByteSize: 2, Cycles: 2, Mode: Immediate},
-	SBC_ZER: {Code: SBC_ZER, Operation: sbc, ByteSize: 2, Cycles: 3, Mode: ZeroPage},
-	SBC_ZRX: {Code: SBC_ZRX, Operation: sbc, ByteSize: 2, Cycles: 4, Mode: ZeroPageX},
-	SBC_ABS: {Code: SBC_ABS, Operation: sbc, ByteSize: 3, Cycles: 4, Mode: Absolute},
-	SBC_ABX: {Code: SBC_ABX, Operation: sbc, ByteSize: 3, Cycles: 4, Mode: AbsoluteX1},
-	SBC_ABY: {Code: SBC_ABY, Operation: sbc, ByteSize: 3, Cycles: 4, Mode: AbsoluteY1},
-	SBC_IDX: {Code: SBC_IDX, Operation: sbc, ByteSize: 2, Cycles: 6, Mode: IndirectX},
-	SBC_IDY: {Code: SBC_IDY, Operation: sbc, ByteSize: 2, Cycles: 5, Mode: IndirectY1},
-
-	CMP_IMM: {Code: CMP_IMM, Operation: cmp, ByteSize: 2, Cycles: 2, Mode: Immediate},
-	CMP_ZER: {Code: CMP_ZER, Operation: cmp, ByteSize: 2, Cycles: 3, Mode: ZeroPage},
-	CMP_ZRX: {Code: CMP_ZRX, Operation: cmp, ByteSize: 2, Cycles: 4, Mode: ZeroPageX},
-	CMP_ABS: {Code: CMP_ABS, Operation: cmp, ByteSize: 3, Cycles: 4, Mode: Absolute},
-	CMP_ABX: {Code: CMP_ABX, Operation: cmp, ByteSize: 3, Cycles: 4, Mode: AbsoluteX1},
-	CMP_ABY: {Code: CMP_ABY, Operation: cmp, ByteSize: 3, Cycles: 4, Mode: AbsoluteY1},
-	CMP_IDX: {Code: CMP_IDX, Operation: cmp, ByteSize: 2, Cycles: 6, Mode: IndirectX},
-	CMP_IDY: {Code: CMP_IDY, Operation: cmp, ByteSize: 2, Cycles: 5, Mode: IndirectY1},
-
-	CPX_IMM: {Code: CPX_IMM, Operation: cpx, ByteSize: 2, Cycles: 2, Mode: Immediate},
-	CPX_ZER: {Code: CPX_ZER, Operation: cpx, ByteSize: 2, Cycles: 3, Mode: ZeroPage},
-	CPX_ABS: {Code: CPX_ABS, Operation: cpx, ByteSize: 3, Cycles: 4, Mode: Absolute},
-
-	CPY_IMM: {Code: CPY_IMM, Operation: cpy, ByteSize: 2, Cycles: 2, Mode: Immediate},
-	CPY_ZER: {Code: CPY_ZER, Operation: cpy, ByteSize: 2, Cycles: 3, Mode: ZeroPage},
-	CPY_ABS: {Code: CPY_ABS, Operation: cpy, ByteSize: 3, Cycles: 4, Mode: Absolute},
+	ADC_IMM: {Code: ADC_IMM, Mnemonic: "ADC", Operation: adc, ByteSize: 2, Cycles: 2, Mode: Immediate, Access: ReadAccess},
+	ADC_ZER: {Code: ADC_ZER, Mnemonic: "ADC", Operation: adc, ByteSize: 2, Cycles: 3, Mode: ZeroPage, Access: ReadAccess},
+	ADC_ZRX: {Code: ADC_ZRX, Mnemonic: "ADC", Operation: adc, ByteSize: 2, Cycles: 4, Mode: ZeroPageX, Access: ReadAccess},
+	ADC_ABS: {Code: ADC_ABS, Mnemonic: "ADC", Operation: adc, ByteSize: 3, Cycles: 4, Mode: Absolute, Access: ReadAccess},
+	ADC_ABX: {Code: ADC_ABX, Mnemonic: "ADC", Operation: adc, ByteSize: 3, Cycles: 4, Mode: AbsoluteX1, Access: ReadAccess},
+	ADC_ABY: {Code: ADC_ABY, Mnemonic: "ADC", Operation: adc, ByteSize: 3, Cycles: 4, Mode: AbsoluteY1, Access: ReadAccess},
+	ADC_IDX: {Code: ADC_IDX, Mnemonic: "ADC", Operation: adc, ByteSize: 2, Cycles: 6, Mode: IndirectX, Access: ReadAccess},
+	ADC_IDY: {Code: ADC_IDY, Mnemonic: "ADC", Operation: adc, ByteSize: 2, Cycles: 5, Mode: IndirectY1, Access: ReadAccess},
+
+	SBC_IMM: {Code: SBC_IMM, Mnemonic: "SBC", Operation: sbc, ByteSize: 2, Cycles: 2, Mode: Immediate, Access: ReadAccess},
+	SBC_ZER: {Code: SBC_ZER, Mnemonic: "SBC", Operation: sbc, ByteSize: 2, Cycles: 3, Mode: ZeroPage, Access: ReadAccess},
+	SBC_ZRX: {Code: SBC_ZRX, Mnemonic: "SBC", Operation: sbc, ByteSize: 2, Cycles: 4, Mode: ZeroPageX, Access: ReadAccess},
+	SBC_ABS: {Code: SBC_ABS, Mnemonic: "SBC", Operation: sbc, ByteSize: 3, Cycles: 4, Mode: Absolute, Access: ReadAccess},
+	SBC_ABX: {Code: SBC_ABX, Mnemonic: "SBC", Operation: sbc, ByteSize: 3, Cycles: 4, Mode: AbsoluteX1, Access: ReadAccess},
+	SBC_ABY: {Code: SBC_ABY, Mnemonic: "SBC", Operation: sbc, ByteSize: 3, Cycles: 4, Mode: AbsoluteY1, Access: ReadAccess},
+	SBC_IDX: {Code: SBC_IDX, Mnemonic: "SBC", Operation: sbc, ByteSize: 2, Cycles: 6, Mode: IndirectX, Access: ReadAccess},
+	SBC_IDY: {Code: SBC_IDY, Mnemonic: "SBC", Operation: sbc, ByteSize: 2, Cycles: 5, Mode: IndirectY1, Access: ReadAccess},
+
+	CMP_IMM: {Code: CMP_IMM, Mnemonic: "CMP", Operation: cmp, ByteSize: 2, Cycles: 2, Mode: Immediate, Access: ReadAccess},
+	CMP_ZER: {Code: CMP_ZER, Mnemonic: "CMP", Operation: cmp, ByteSize: 2, Cycles: 3, Mode: ZeroPage, Access: ReadAccess},
+	CMP_ZRX: {Code: CMP_ZRX, Mnemonic: "CMP", Operation: cmp, ByteSize: 2, Cycles: 4, Mode: ZeroPageX, Access: ReadAccess},
+	CMP_ABS: {Code: CMP_ABS, Mnemonic: "CMP", Operation: cmp, ByteSize: 3, Cycles: 4, Mode: Absolute, Access: ReadAccess},
+	CMP_ABX: {Code: CMP_ABX, Mnemonic: "CMP", Operation: cmp, ByteSize: 3, Cycles: 4, Mode: AbsoluteX1, Access: ReadAccess},
+	CMP_ABY: {Code: CMP_ABY, Mnemonic: "CMP", Operation: cmp, ByteSize: 3, Cycles: 4, Mode: AbsoluteY1, Access: ReadAccess},
+	CMP_IDX: {Code: CMP_IDX, Mnemonic: "CMP", Operation: cmp, ByteSize: 2, Cycles: 6, Mode: IndirectX, Access: ReadAccess},
+	CMP_IDY: {Code: CMP_IDY, Mnemonic: "CMP", Operation: cmp, ByteSize: 2, Cycles: 5, Mode: IndirectY1, Access: ReadAccess},
+
+	CPX_IMM: {Code: CPX_IMM, Mnemonic: "CPX", Operation: cpx, ByteSize: 2, Cycles: 2, Mode: Immediate, Access: ReadAccess},
+	CPX_ZER: {Code: CPX_ZER, Mnemonic: "CPX", Operation: cpx, ByteSize: 2, Cycles: 3, Mode: ZeroPage, Access: ReadAccess},
+	CPX_ABS: {Code: CPX_ABS, Mnemonic: "CPX", Operation: cpx, ByteSize: 3, Cycles: 4, Mode: Absolute, Access: ReadAccess},
+
+	CPY_IMM: {Code: CPY_IMM, Mnemonic: "CPY", Operation: cpy, ByteSize: 2, Cycles: 2, Mode: Immediate, Access: ReadAccess},
+	CPY_ZER: {Code: CPY_ZER, Mnemonic: "CPY", Operation: cpy, ByteSize: 2, Cycles: 3, Mode: ZeroPage, Access: ReadAccess},
+	CPY_ABS: {Code: CPY_ABS, Mnemonic: "CPY", Operation: cpy, ByteSize: 3, Cycles: 4, Mode: Absolute, Access: ReadAccess},
 
 	// Increments
-	INC_ZER: {Code: INC_ZER, Operation: inc, ByteSize: 2, Cycles: 5, Mode: ZeroPage},
-	INC_ZRX: {Code: INC_ZRX, Operation: inc, ByteSize: 2, Cycles: 6, Mode: ZeroPageX},
-	INC_ABS: {Code: INC_ABS, Operation: inc, ByteSize: 3, Cycles: 6, Mode: Absolute},
-	INC_ABX: {Code: INC_ABX, Operation: inc, ByteSize: 3, Cycles: 7, Mode: AbsoluteX},
-	INX_IMP: {Code: INX_IMP, Operation: inx, ByteSize: 1, Cycles: 2, Mode: Implied},
-	INY_IMP: {Code: INY_IMP, Operation: iny, ByteSize: 1, Cycles: 2, Mode: Implied},
+	INC_ZER: {Code: INC_ZER, Mnemonic: "INC", Operation: inc, ByteSize: 2, Cycles: 5, Mode: ZeroPage, Access: ModifyAccess},
+	INC_ZRX: {Code: INC_ZRX, Mnemonic: "INC", Operation: inc, ByteSize: 2, Cycles: 6, Mode: ZeroPageX, Access: ModifyAccess},
+	INC_ABS: {Code: INC_ABS, Mnemonic: "INC", Operation: inc, ByteSize: 3, Cycles: 6, Mode: Absolute, Access: ModifyAccess},
+	INC_ABX: {Code: INC_ABX, Mnemonic: "INC", Operation: inc, ByteSize: 3, Cycles: 7, Mode: AbsoluteX, Access: ModifyAccess},
+	INX_IMP: {Code: INX_IMP, Mnemonic: "INX", Operation: inx, ByteSize: 1, Cycles: 2, Mode: Implied},
+	INY_IMP: {Code: INY_IMP, Mnemonic: "INY", Operation: iny, ByteSize: 1, Cycles: 2, Mode: Implied},
 
 	// Decrements
-	DEC_ZER: {Code: DEC_ZER, Operation: dec, ByteSize: 2, Cycles: 5, Mode: ZeroPage},
-	DEC_ZRX: {Code: DEC_ZRX, Operation: dec, ByteSize: 2, Cycles: 6, Mode: ZeroPageX},
-	DEC_ABS: {Code: DEC_ABS, Operation: dec, ByteSize: 3, Cycles: 6, Mode: Absolute},
-	DEC_ABX: {Code: DEC_ABX, Operation: dec, ByteSize: 3, Cycles: 7, Mode: AbsoluteX},
-	DEX_IMP: {Code: DEX_IMP, Operation: dex, ByteSize: 1, Cycles: 2, Mode: Implied},
-	DEY_IMP: {Code: DEY_IMP, Operation: dey, ByteSize: 1, Cycles: 2, Mode: Implied},
+	DEC_ZER: {Code: DEC_ZER, Mnemonic: "DEC", Operation: dec, ByteSize: 2, Cycles: 5, Mode: ZeroPage, Access: ModifyAccess},
+	DEC_ZRX: {Code: DEC_ZRX, Mnemonic: "DEC", Operation: dec, ByteSize: 2, Cycles: 6, Mode: ZeroPageX, Access: ModifyAccess},
+	DEC_ABS: {Code: DEC_ABS, Mnemonic: "DEC", Operation: dec, ByteSize: 3, Cycles: 6, Mode: Absolute, Access: ModifyAccess},
+	DEC_ABX: {Code: DEC_ABX, Mnemonic: "DEC", Operation: dec, ByteSize: 3, Cycles: 7, Mode: AbsoluteX, Access: ModifyAccess},
+	DEX_IMP: {Code: DEX_IMP, Mnemonic: "DEX", Operation: dex, ByteSize: 1, Cycles: 2, Mode: Implied},
+	DEY_IMP: {Code: DEY_IMP, Mnemonic: "DEY", Operation: dey, ByteSize: 1, Cycles: 2, Mode: Implied},
 
 	// Shifts
-	ASL_ACC: {Code: ASL_ACC, Operation: asl, ByteSize: 1, Cycles: 2, Mode: Accumulator},
-	ASL_ZER: {Code: ASL_ZER, Operation: asl, ByteSize: 2, Cycles: 5, Mode: ZeroPage},
-	ASL_ZRX: {Code: ASL_ZRX, Operation: asl, ByteSize: 2, Cycles: 6, Mode: ZeroPageX},
-	ASL_ABS: {Code: ASL_ABS, Operation: asl, ByteSize: 3, Cycles: 6, Mode: Absolute},
-	ASL_ABX: {Code: ASL_ABX, Operation: asl, ByteSize: 3, Cycles: 7, Mode: AbsoluteX},
-
-	LSR_ACC: {Code: LSR_ACC, Operation: lsr, ByteSize: 1, Cycles: 2, Mode: Accumulator},
-	LSR_ZER: {Code: LSR_ZER, Operation: lsr, ByteSize: 2, Cycles: 5, Mode: ZeroPage},
-	LSR_ZRX: {Code: LSR_ZRX, Operation: lsr, ByteSize: 2, Cycles: 6, Mode: ZeroPageX},
-	LSR_ABS: {Code: LSR_ABS, Operation: lsr, ByteSize: 3, Cycles: 6, Mode: Absolute},
-	LSR_ABX: {Code: LSR_ABX, Operation: lsr, ByteSize: 3, Cycles: 7, Mode: AbsoluteX},
-
-	ROL_ACC: {Code: ROL_ACC, Operation: rol, ByteSize: 1, Cycles: 2, Mode: Accumulator},
-	ROL_ZER: {Code: ROL_ZER, Operation: rol, ByteSize: 2, Cycles: 5, Mode: ZeroPage},
-	ROL_ZRX: {Code: ROL_ZRX, Operation: rol, ByteSize: 2, Cycles: 6, Mode: ZeroPageX},
-	ROL_ABS: {Code: ROL_ABS, Operation: rol, ByteSize: 3, Cycles: 6, Mode: Absolute},
-	ROL_ABX: {Code: ROL_ABX, Operation: rol, ByteSize: 3, Cycles: 7, Mode: AbsoluteX},
-
-	ROR_ACC: {Code: ROR_ACC, Operation: ror, ByteSize: 1, Cycles: 2, Mode: Accumulator},
-	ROR_ZER: {Code: ROR_ZER, Operation: ror, ByteSize: 2, Cycles: 5, Mode: ZeroPage},
-	ROR_ZRX: {Code: ROR_ZRX, Operation: ror, ByteSize: 2, Cycles: 6, Mode: ZeroPageX},
-	ROR_ABS: {Code: ROR_ABS, Operation: ror, ByteSize: 3, Cycles: 6, Mode: Absolute},
-	ROR_ABX: {Code: ROR_ABX, Operation: ror, ByteSize: 3, Cycles: 7, Mode: AbsoluteX},
+	ASL_ACC: {Code: ASL_ACC, Mnemonic: "ASL", Operation: asl, ByteSize: 1, Cycles: 2, Mode: Accumulator, Access: ModifyAccess},
+	ASL_ZER: {Code: ASL_ZER, Mnemonic: "ASL", Operation: asl, ByteSize: 2, Cycles: 5, Mode: ZeroPage, Access: ModifyAccess},
+	ASL_ZRX: {Code: ASL_ZRX, Mnemonic: "ASL", Operation: asl, ByteSize: 2, Cycles: 6, Mode: ZeroPageX, Access: ModifyAccess},
+	ASL_ABS: {Code: ASL_ABS, Mnemonic: "ASL", Operation: asl, ByteSize: 3, Cycles: 6, Mode: Absolute, Access: ModifyAccess},
+	ASL_ABX: {Code: ASL_ABX, Mnemonic: "ASL", Operation: asl, ByteSize: 3, Cycles: 7, Mode: AbsoluteX, Access: ModifyAccess},
+
+	LSR_ACC: {Code: LSR_ACC, Mnemonic: "LSR", Operation: lsr, ByteSize: 1, Cycles: 2, Mode: Accumulator, Access: ModifyAccess},
+	LSR_ZER: {Code: LSR_ZER, Mnemonic: "LSR", Operation: lsr, ByteSize: 2, Cycles: 5, Mode: ZeroPage, Access: ModifyAccess},
+	LSR_ZRX: {Code: LSR_ZRX, Mnemonic: "LSR", Operation: lsr, ByteSize: 2, Cycles: 6, Mode: ZeroPageX, Access: ModifyAccess},
+	LSR_ABS: {Code: LSR_ABS, Mnemonic: "LSR", Operation: lsr, ByteSize: 3, Cycles: 6, Mode: Absolute, Access: ModifyAccess},
+	LSR_ABX: {Code: LSR_ABX, Mnemonic: "LSR", Operation: lsr, ByteSize: 3, Cycles: 7, Mode: AbsoluteX, Access: ModifyAccess},
+
+	ROL_ACC: {Code: ROL_ACC, Mnemonic: "ROL", Operation: rol, ByteSize: 1, Cycles: 2, Mode: Accumulator, Access: ModifyAccess},
+	ROL_ZER: {Code: ROL_ZER, Mnemonic: "ROL", Operation: rol, ByteSize: 2, Cycles: 5, Mode: ZeroPage, Access: ModifyAccess},
+	ROL_ZRX: {Code: ROL_ZRX, Mnemonic: "ROL", Operation: rol, ByteSize: 2, Cycles: 6, Mode: ZeroPageX, Access: ModifyAccess},
+	ROL_ABS: {Code: ROL_ABS, Mnemonic: "ROL", Operation: rol, ByteSize: 3, Cycles: 6, Mode: Absolute, Access: ModifyAccess},
+	ROL_ABX: {Code: ROL_ABX, Mnemonic: "ROL", Operation: rol, ByteSize: 3, Cycles: 7, Mode: AbsoluteX, Access: ModifyAccess},
+
+	ROR_ACC: {Code: ROR_ACC, Mnemonic: "ROR", Operation: ror, ByteSize: 1, Cycles: 2, Mode: Accumulator, Access: ModifyAccess},
+	ROR_ZER: {Code: ROR_ZER, Mnemonic: "ROR", Operation: ror, ByteSize: 2, Cycles: 5, Mode: ZeroPage, Access: ModifyAccess},
+	ROR_ZRX: {Code: ROR_ZRX, Mnemonic: "ROR", Operation: ror, ByteSize: 2, Cycles: 6, Mode: ZeroPageX, Access: ModifyAccess},
+	ROR_ABS: {Code: ROR_ABS, Mnemonic: "ROR", Operation: ror, ByteSize: 3, Cycles: 6, Mode: Absolute, Access: ModifyAccess},
+	ROR_ABX: {Code: ROR_ABX, Mnemonic: "ROR", Operation: ror, ByteSize: 3, Cycles: 7, Mode: AbsoluteX, Access: ModifyAccess},
 
 	// Jumps
-	/* byte size set to 1 because to not change the prg counter after jump */
-	JMP_ABS: {Code: JMP_ABS, Operation: jmp, ByteSize: 1, Cycles: 3, Mode: Absolute},
-	JMP_IND: {Code: JMP_IND, Operation: jmp, ByteSize: 1, Cycles: 5, Mode: Indirect},
-
-	JSR_ABS: {Code: JSR_ABS, Operation: jsr, ByteSize: 1, Cycles: 6, Mode: Absolute},
-	RTS_IMP: {Code: RTS_IMP, Operation: rts, ByteSize: 1, Cycles: 6, Mode: Implied},
-
-	// Branching
-	BCC_REL: {Code: BCC_REL, Operation: bcc, ByteSize: 2, Cycles: 2 /*to+2*/, Mode: Relative},
-	BCS_REL: {Code: BCS_REL, Operation: bcs, ByteSize: 2, Cycles: 2 /*to+2*/, Mode: Relative},
-	BEQ_REL: {Code: BEQ_REL, Operation: beq, ByteSize: 2, Cycles: 2 /*to+2*/, Mode: Relative},
-	BMI_REL: {Code: BMI_REL, Operation: bmi, ByteSize: 2, Cycles: 2 /*to+2*/, Mode: Relative},
-	BNE_REL: {Code: BNE_REL, Operation: bne, ByteSize: 2, Cycles: 2 /*to+2*/, Mode: Relative},
-	BPL_REL: {Code: BPL_REL, Operation: bpl, ByteSize: 2, Cycles: 2 /*to+2*/, Mode: Relative},
-	BVC_REL: {Code: BVC_REL, Operation: bvc, ByteSize: 2, Cycles: 2 /*to+2*/, Mode: Relative},
-	BVS_REL: {Code: BVS_REL, Operation: bvs, ByteSize: 2, Cycles: 2 /*to+2*/, Mode: Relative},
+	JMP_ABS: {Code: JMP_ABS, Mnemonic: "JMP", ByteSize: 3, Cycles: 3, Mode: Absolute, program: jmpAbsProgram},
+	JMP_IND: {Code: JMP_IND, Mnemonic: "JMP", ByteSize: 3, Cycles: 5, Mode: Indirect, program: jmpIndProgram},
+
+	JSR_ABS: {Code: JSR_ABS, Mnemonic: "JSR", ByteSize: 3, Cycles: 6, Mode: Absolute, program: jsrProgram},
+	RTS_IMP: {Code: RTS_IMP, Mnemonic: "RTS", ByteSize: 1, Cycles: 6, Mode: Implied, program: rtsProgram},
+
+	// Branching, one more cycle when taken and another one when crossing a page
+	BCC_REL: {Code: BCC_REL, Mnemonic: "BCC", Operation: bcc, ByteSize: 2, Cycles: 2, Mode: Relative, Access: BranchAccess},
+	BCS_REL: {Code: BCS_REL, Mnemonic: "BCS", Operation: bcs, ByteSize: 2, Cycles: 2, Mode: Relative, Access: BranchAccess},
+	BEQ_REL: {Code: BEQ_REL, Mnemonic: "BEQ", Operation: beq, ByteSize: 2, Cycles: 2, Mode: Relative, Access: BranchAccess},
+	BMI_REL: {Code: BMI_REL, Mnemonic: "BMI", Operation: bmi, ByteSize: 2, Cycles: 2, Mode: Relative, Access: BranchAccess},
+	BNE_REL: {Code: BNE_REL, Mnemonic: "BNE", Operation: bne, ByteSize: 2, Cycles: 2, Mode: Relative, Access: BranchAccess},
+	BPL_REL: {Code: BPL_REL, Mnemonic: "BPL", Operation: bpl, ByteSize: 2, Cycles: 2, Mode: Relative, Access: BranchAccess},
+	BVC_REL: {Code: BVC_REL, Mnemonic: "BVC", Operation: bvc, ByteSize: 2, Cycles: 2, Mode: Relative, Access: BranchAccess},
+	BVS_REL: {Code: BVS_REL, Mnemonic: "BVS", Operation: bvs, ByteSize: 2, Cycles: 2, Mode: Relative, Access: BranchAccess},
 
 	// Status Flag Changes
-	CLC_IMP: {Code: CLC_IMP, Operation: clc, ByteSize: 1, Cycles: 2, Mode: Implied},
-	CLD_IMP: {Code: CLD_IMP, Operation: cld, ByteSize: 1, Cycles: 2, Mode: Implied},
-	CLI_IMP: {Code: CLI_IMP, Operation: cli, ByteSize: 1, Cycles: 2, Mode: Implied},
-	CLV_IMP: {Code: CLV_IMP, Operation: clv, ByteSize: 1, Cycles: 2, Mode: Implied},
+	CLC_IMP: {Code: CLC_IMP, Mnemonic: "CLC", Operation: clc, ByteSize: 1, Cycles: 2, Mode: Implied},
+	CLD_IMP: {Code: CLD_IMP, Mnemonic: "CLD", Operation: cld, ByteSize: 1, Cycles: 2, Mode: Implied},
+	CLI_IMP: {Code: CLI_IMP, Mnemonic: "CLI", Operation: cli, ByteSize: 1, Cycles: 2, Mode: Implied},
+	CLV_IMP: {Code: CLV_IMP, Mnemonic: "CLV", Operation: clv, ByteSize: 1, Cycles: 2, Mode: Implied},
 
-	SEC_IMP: {Code: SEC_IMP, Operation: sec, ByteSize: 1, Cycles: 2, Mode: Implied},
-	SED_IMP: {Code: SED_IMP, Operation: sed, ByteSize: 1, Cycles: 2, Mode: Implied},
-	SEI_IMP: {Code: SEI_IMP, Operation: sei, ByteSize: 1, Cycles: 2, Mode: Implied},
+	SEC_IMP: {Code: SEC_IMP, Mnemonic: "SEC", Operation: sec, ByteSize: 1, Cycles: 2, Mode: Implied},
+	SED_IMP: {Code: SED_IMP, Mnemonic: "SED", Operation: sed, ByteSize: 1, Cycles: 2, Mode: Implied},
+	SEI_IMP: {Code: SEI_IMP, Mnemonic: "SEI", Operation: sei, ByteSize: 1, Cycles: 2, Mode: Implied},
 
 	// System Functions
-	BRK_IMP: {Code: BRK_IMP, Operation: brk, ByteSize: 1, Cycles: 7, Mode: Implied},
-	NOP_IMP: {Code: NOP_IMP, Operation: nop, ByteSize: 1, Cycles: 2, Mode: Implied},
-	RTI_IMP: {Code: RTI_IMP, Operation: rti, ByteSize: 1, Cycles: 6, Mode: Implied},
+	BRK_IMP: {Code: BRK_IMP, Mnemonic: "BRK", ByteSize: 1, Cycles: 7, Mode: Implied, program: brkProgram},
+	NOP_IMP: {Code: NOP_IMP, Mnemonic: "NOP", Operation: nop, ByteSize: 1, Cycles: 2, Mode: Implied},
+	RTI_IMP: {Code: RTI_IMP, Mnemonic: "RTI", ByteSize: 1, Cycles: 6, Mode: Implied, program: rtiProgram},
+}
+
+var undocumentedOpcodes = map[uint8]Opcode{
+	// Undocumented No Operations
+	NOP_IMP_1A: {Code: NOP_IMP_1A, Mnemonic: "NOP", Operation: nop, ByteSize: 1, Cycles: 2, Mode: Implied},
+	NOP_IMP_3A: {Code: NOP_IMP_3A, Mnemonic: "NOP", Operation: nop, ByteSize: 1, Cycles: 2, Mode: Implied},
+	NOP_IMP_5A: {Code: NOP_IMP_5A, Mnemonic: "NOP", Operation: nop, ByteSize: 1, Cycles: 2, Mode: Implied},
+	NOP_IMP_7A: {Code: NOP_IMP_7A, Mnemonic: "NOP", Operation: nop, ByteSize: 1, Cycles: 2, Mode: Implied},
+	NOP_IMP_DA: {Code: NOP_IMP_DA, Mnemonic: "NOP", Operation: nop, ByteSize: 1, Cycles: 2, Mode: Implied},
+	NOP_IMP_FA: {Code: NOP_IMP_FA, Mnemonic: "NOP", Operation: nop, ByteSize: 1, Cycles: 2, Mode: Implied},
+	NOP_IMM_80: {Code: NOP_IMM_80, Mnemonic: "NOP", Operation: skb, ByteSize: 2, Cycles: 2, Mode: Immediate, Access: ReadAccess},
+	NOP_IMM_82: {Code: NOP_IMM_82, Mnemonic: "NOP", Operation: skb, ByteSize: 2, Cycles: 2, Mode: Immediate, Access: ReadAccess},
+	NOP_IMM_89: {Code: NOP_IMM_89, Mnemonic: "NOP", Operation: skb, ByteSize: 2, Cycles: 2, Mode: Immediate, Access: ReadAccess},
+	NOP_IMM_C2: {Code: NOP_IMM_C2, Mnemonic: "NOP", Operation: skb, ByteSize: 2, Cycles: 2, Mode: Immediate, Access: ReadAccess},
+	NOP_IMM_E2: {Code: NOP_IMM_E2, Mnemonic: "NOP", Operation: skb, ByteSize: 2, Cycles: 2, Mode: Immediate, Access: ReadAccess},
+	NOP_ZER_04: {Code: NOP_ZER_04, Mnemonic: "NOP", Operation: skb, ByteSize: 2, Cycles: 3, Mode: ZeroPage, Access: ReadAccess},
+	NOP_ZER_44: {Code: NOP_ZER_44, Mnemonic: "NOP", Operation: skb, ByteSize: 2, Cycles: 3, Mode: ZeroPage, Access: ReadAccess},
+	NOP_ZER_64: {Code: NOP_ZER_64, Mnemonic: "NOP", Operation: skb, ByteSize: 2, Cycles: 3, Mode: ZeroPage, Access: ReadAccess},
+	NOP_ZRX_14: {Code: NOP_ZRX_14, Mnemonic: "NOP", Operation: skb, ByteSize: 2, Cycles: 4, Mode: ZeroPageX, Access: ReadAccess},
+	NOP_ZRX_34: {Code: NOP_ZRX_34, Mnemonic: "NOP", Operation: skb, ByteSize: 2, Cycles: 4, Mode: ZeroPageX, Access: ReadAccess},
+	NOP_ZRX_54: {Code: NOP_ZRX_54, Mnemonic: "NOP", Operation: skb, ByteSize: 2, Cycles: 4, Mode: ZeroPageX, Access: ReadAccess},
+	NOP_ZRX_74: {Code: NOP_ZRX_74, Mnemonic: "NOP", Operation: skb, ByteSize: 2, Cycles: 4, Mode: ZeroPageX, Access: ReadAccess},
+	NOP_ZRX_D4: {Code: NOP_ZRX_D4, Mnemonic: "NOP", Operation: skb, ByteSize: 2, Cycles: 4, Mode: ZeroPageX, Access: ReadAccess},
+	NOP_ZRX_F4: {Code: NOP_ZRX_F4, Mnemonic: "NOP", Operation: skb, ByteSize: 2, Cycles: 4, Mode: ZeroPageX, Access: ReadAccess},
+	NOP_ABS_0C: {Code: NOP_ABS_0C, Mnemonic: "NOP", Operation: skb, ByteSize: 3, Cycles: 4, Mode: Absolute, Access: ReadAccess},
+	NOP_ABX_1C: {Code: NOP_ABX_1C, Mnemonic: "NOP", Operation: skb, ByteSize: 3, Cycles: 4, Mode: AbsoluteX1, Access: ReadAccess},
+	NOP_ABX_3C: {Code: NOP_ABX_3C, Mnemonic: "NOP", Operation: skb, ByteSize: 3, Cycles: 4, Mode: AbsoluteX1, Access: ReadAccess},
+	NOP_ABX_5C: {Code: NOP_ABX_5C, Mnemonic: "NOP", Operation: skb, ByteSize: 3, Cycles: 4, Mode: AbsoluteX1, Access: ReadAccess},
+	NOP_ABX_7C: {Code: NOP_ABX_7C, Mnemonic: "NOP", Operation: skb, ByteSize: 3, Cycles: 4, Mode: AbsoluteX1, Access: ReadAccess},
+	NOP_ABX_DC: {Code: NOP_ABX_DC, Mnemonic: "NOP", Operation: skb, ByteSize: 3, Cycles: 4, Mode: AbsoluteX1, Access: ReadAccess},
+	NOP_ABX_FC: {Code: NOP_ABX_FC, Mnemonic: "NOP", Operation: skb, ByteSize: 3, Cycles: 4, Mode: AbsoluteX1, Access: ReadAccess},
+
+	// Undocumented Halts
+	JAM_IMP_02: {Code: JAM_IMP_02, Mnemonic: "JAM", ByteSize: 1, Cycles: 2, Mode: Implied, program: jamProgram},
+	JAM_IMP_12: {Code: JAM_IMP_12, Mnemonic: "JAM", ByteSize: 1, Cycles: 2, Mode: Implied, program: jamProgram},
+	JAM_IMP_22: {Code: JAM_IMP_22, Mnemonic: "JAM", ByteSize: 1, Cycles: 2, Mode: Implied, program: jamProgram},
+	JAM_IMP_32: {Code: JAM_IMP_32, Mnemonic: "JAM", ByteSize: 1, Cycles: 2, Mode: Implied, program: jamProgram},
+	JAM_IMP_42: {Code: JAM_IMP_42, Mnemonic: "JAM", ByteSize: 1, Cycles: 2, Mode: Implied, program: jamProgram},
+	JAM_IMP_52: {Code: JAM_IMP_52, Mnemonic: "JAM", ByteSize: 1, Cycles: 2, Mode: Implied, program: jamProgram},
+	JAM_IMP_62: {Code: JAM_IMP_62, Mnemonic: "JAM", ByteSize: 1, Cycles: 2, Mode: Implied, program: jamProgram},
+	JAM_IMP_72: {Code: JAM_IMP_72, Mnemonic: "JAM", ByteSize: 1, Cycles: 2, Mode: Implied, program: jamProgram},
+	JAM_IMP_92: {Code: JAM_IMP_92, Mnemonic: "JAM", ByteSize: 1, Cycles: 2, Mode: Implied, program: jamProgram},
+	JAM_IMP_B2: {Code: JAM_IMP_B2, Mnemonic: "JAM", ByteSize: 1, Cycles: 2, Mode: Implied, program: jamProgram},
+	JAM_IMP_D2: {Code: JAM_IMP_D2, Mnemonic: "JAM", ByteSize: 1, Cycles: 2, Mode: Implied, program: jamProgram},
+	JAM_IMP_F2: {Code: JAM_IMP_F2, Mnemonic: "JAM", ByteSize: 1, Cycles: 2, Mode: Implied, program: jamProgram},
+
+	// Undocumented Read-Modify-Write
+	SLO_ZER: {Code: SLO_ZER, Mnemonic: "SLO", Operation: slo, ByteSize: 2, Cycles: 5, Mode: ZeroPage, Access: ModifyAccess},
+	SLO_ZRX: {Code: SLO_ZRX, Mnemonic: "SLO", Operation: slo, ByteSize: 2, Cycles: 6, Mode: ZeroPageX, Access: ModifyAccess},
+	SLO_ABS: {Code: SLO_ABS, Mnemonic: "SLO", Operation: slo, ByteSize: 3, Cycles: 6, Mode: Absolute, Access: ModifyAccess},
+	SLO_ABX: {Code: SLO_ABX, Mnemonic: "SLO", Operation: slo, ByteSize: 3, Cycles: 7, Mode: AbsoluteX, Access: ModifyAccess},
+	SLO_ABY: {Code: SLO_ABY, Mnemonic: "SLO", Operation: slo, ByteSize: 3, Cycles: 7, Mode: AbsoluteY, Access: ModifyAccess},
+	SLO_IDX: {Code: SLO_IDX, Mnemonic: "SLO", Operation: slo, ByteSize: 2, Cycles: 8, Mode: IndirectX, Access: ModifyAccess},
+	SLO_IDY: {Code: SLO_IDY, Mnemonic: "SLO", Operation: slo, ByteSize: 2, Cycles: 8, Mode: IndirectY, Access: ModifyAccess},
+
+	RLA_ZER: {Code: RLA_ZER, Mnemonic: "RLA", Operation: rla, ByteSize: 2, Cycles: 5, Mode: ZeroPage, Access: ModifyAccess},
+	RLA_ZRX: {Code: RLA_ZRX, Mnemonic: "RLA", Operation: rla, ByteSize: 2, Cycles: 6, Mode: ZeroPageX, Access: ModifyAccess},
+	RLA_ABS: {Code: RLA_ABS, Mnemonic: "RLA", Operation: rla, ByteSize: 3, Cycles: 6, Mode: Absolute, Access: ModifyAccess},
+	RLA_ABX: {Code: RLA_ABX, Mnemonic: "RLA", Operation: rla, ByteSize: 3, Cycles: 7, Mode: AbsoluteX, Access: ModifyAccess},
+	RLA_ABY: {Code: RLA_ABY, Mnemonic: "RLA", Operation: rla, ByteSize: 3, Cycles: 7, Mode: AbsoluteY, Access: ModifyAccess},
+	RLA_IDX: {Code: RLA_IDX, Mnemonic: "RLA", Operation: rla, ByteSize: 2, Cycles: 8, Mode: IndirectX, Access: ModifyAccess},
+	RLA_IDY: {Code: RLA_IDY, Mnemonic: "RLA", Operation: rla, ByteSize: 2, Cycles: 8, Mode: IndirectY, Access: ModifyAccess},
+
+	SRE_ZER: {Code: SRE_ZER, Mnemonic: "SRE", Operation: sre, ByteSize: 2, Cycles: 5, Mode: ZeroPage, Access: ModifyAccess},
+	SRE_ZRX: {Code: SRE_ZRX, Mnemonic: "SRE", Operation: sre, ByteSize: 2, Cycles: 6, Mode: ZeroPageX, Access: ModifyAccess},
+	SRE_ABS: {Code: SRE_ABS, Mnemonic: "SRE", Operation: sre, ByteSize: 3, Cycles: 6, Mode: Absolute, Access: ModifyAccess},
+	SRE_ABX: {Code: SRE_ABX, Mnemonic: "SRE", Operation: sre, ByteSize: 3, Cycles: 7, Mode: AbsoluteX, Access: ModifyAccess},
+	SRE_ABY: {Code: SRE_ABY, Mnemonic: "SRE", Operation: sre, ByteSize: 3, Cycles: 7, Mode: AbsoluteY, Access: ModifyAccess},
+	SRE_IDX: {Code: SRE_IDX, Mnemonic: "SRE", Operation: sre, ByteSize: 2, Cycles: 8, Mode: IndirectX, Access: ModifyAccess},
+	SRE_IDY: {Code: SRE_IDY, Mnemonic: "SRE", Operation: sre, ByteSize: 2, Cycles: 8, Mode: IndirectY, Access: ModifyAccess},
+
+	RRA_ZER: {Code: RRA_ZER, Mnemonic: "RRA", Operation: rra, ByteSize: 2, Cycles: 5, Mode: ZeroPage, Access: ModifyAccess},
+	RRA_ZRX: {Code: RRA_ZRX, Mnemonic: "RRA", Operation: rra, ByteSize: 2, Cycles: 6, Mode: ZeroPageX, Access: ModifyAccess},
+	RRA_ABS: {Code: RRA_ABS, Mnemonic: "RRA", Operation: rra, ByteSize: 3, Cycles: 6, Mode: Absolute, Access: ModifyAccess},
+	RRA_ABX: {Code: RRA_ABX, Mnemonic: "RRA", Operation: rra, ByteSize: 3, Cycles: 7, Mode: AbsoluteX, Access: ModifyAccess},
+	RRA_ABY: {Code: RRA_ABY, Mnemonic: "RRA", Operation: rra, ByteSize: 3, Cycles: 7, Mode: AbsoluteY, Access: ModifyAccess},
+	RRA_IDX: {Code: RRA_IDX, Mnemonic: "RRA", Operation: rra, ByteSize: 2, Cycles: 8, Mode: IndirectX, Access: ModifyAccess},
+	RRA_IDY: {Code: RRA_IDY, Mnemonic: "RRA", Operation: rra, ByteSize: 2, Cycles: 8, Mode: IndirectY, Access: ModifyAccess},
+
+	DCP_ZER: {Code: DCP_ZER, Mnemonic: "DCP", Operation: dcp, ByteSize: 2, Cycles: 5, Mode: ZeroPage, Access: ModifyAccess},
+	DCP_ZRX: {Code: DCP_ZRX, Mnemonic: "DCP", Operation: dcp, ByteSize: 2, Cycles: 6, Mode: ZeroPageX, Access: ModifyAccess},
+	DCP_ABS: {Code: DCP_ABS, Mnemonic: "DCP", Operation: dcp, ByteSize: 3, Cycles: 6, Mode: Absolute, Access: ModifyAccess},
+	DCP_ABX: {Code: DCP_ABX, Mnemonic: "DCP", Operation: dcp, ByteSize: 3, Cycles: 7, Mode: AbsoluteX, Access: ModifyAccess},
+	DCP_ABY: {Code: DCP_ABY, Mnemonic: "DCP", Operation: dcp, ByteSize: 3, Cycles: 7, Mode: AbsoluteY, Access: ModifyAccess},
+	DCP_IDX: {Code: DCP_IDX, Mnemonic: "DCP", Operation: dcp, ByteSize: 2, Cycles: 8, Mode: IndirectX, Access: ModifyAccess},
+	DCP_IDY: {Code: DCP_IDY, Mnemonic: "DCP", Operation: dcp, ByteSize: 2, Cycles: 8, Mode: IndirectY, Access: ModifyAccess},
+
+	ISC_ZER: {Code: ISC_ZER, Mnemonic: "ISC", Operation: isc, ByteSize: 2, Cycles: 5, Mode: ZeroPage, Access: ModifyAccess},
+	ISC_ZRX: {Code: ISC_ZRX, Mnemonic: "ISC", Operation: isc, ByteSize: 2, Cycles: 6, Mode: ZeroPageX, Access: ModifyAccess},
+	ISC_ABS: {Code: ISC_ABS, Mnemonic: "ISC", Operation: isc, ByteSize: 3, Cycles: 6, Mode: Absolute, Access: ModifyAccess},
+	ISC_ABX: {Code: ISC_ABX, Mnemonic: "ISC", Operation: isc, ByteSize: 3, Cycles: 7, Mode: AbsoluteX, Access: ModifyAccess},
+	ISC_ABY: {Code: ISC_ABY, Mnemonic: "ISC", Operation: isc, ByteSize: 3, Cycles: 7, Mode: AbsoluteY, Access: ModifyAccess},
+	ISC_IDX: {Code: ISC_IDX, Mnemonic: "ISC", Operation: isc, ByteSize: 2, Cycles: 8, Mode: IndirectX, Access: ModifyAccess},
+	ISC_IDY: {Code: ISC_IDY, Mnemonic: "ISC", Operation: isc, ByteSize: 2, Cycles: 8, Mode: IndirectY, Access: ModifyAccess},
+
+	// Undocumented Loads and Stores
+	SAX_ZER: {Code: SAX_ZER, Mnemonic: "SAX", Operation: sax, ByteSize: 2, Cycles: 3, Mode: ZeroPage, Access: WriteAccess},
+	SAX_ZRY: {Code: SAX_ZRY, Mnemonic: "SAX", Operation: sax, ByteSize: 2, Cycles: 4, Mode: ZeroPageY, Access: WriteAccess},
+	SAX_ABS: {Code: SAX_ABS, Mnemonic: "SAX", Operation: sax, ByteSize: 3, Cycles: 4, Mode: Absolute, Access: WriteAccess},
+	SAX_IDX: {Code: SAX_IDX, Mnemonic: "SAX", Operation: sax, ByteSize: 2, Cycles: 6, Mode: IndirectX, Access: WriteAccess},
+
+	LAX_ZER: {Code: LAX_ZER, Mnemonic: "LAX", Operation: lax, ByteSize: 2, Cycles: 3, Mode: ZeroPage, Access: ReadAccess},
+	LAX_ZRY: {Code: LAX_ZRY, Mnemonic: "LAX", Operation: lax, ByteSize: 2, Cycles: 4, Mode: ZeroPageY, Access: ReadAccess},
+	LAX_ABS: {Code: LAX_ABS, Mnemonic: "LAX", Operation: lax, ByteSize: 3, Cycles: 4, Mode: Absolute, Access: ReadAccess},
+	LAX_ABY: {Code: LAX_ABY, Mnemonic: "LAX", Operation: lax, ByteSize: 3, Cycles: 4, Mode: AbsoluteY1, Access: ReadAccess},
+	LAX_IDX: {Code: LAX_IDX, Mnemonic: "LAX", Operation: lax, ByteSize: 2, Cycles: 6, Mode: IndirectX, Access: ReadAccess},
+	LAX_IDY: {Code: LAX_IDY, Mnemonic: "LAX", Operation: lax, ByteSize: 2, Cycles: 5, Mode: IndirectY1, Access: ReadAccess},
+
+	// Undocumented Immediates
+	ANC_IMM_0B: {Code: ANC_IMM_0B, Mnemonic: "ANC", Operation: anc, ByteSize: 2, Cycles: 2, Mode: Immediate, Access: ReadAccess},
+	ANC_IMM_2B: {Code: ANC_IMM_2B, Mnemonic: "ANC", Operation: anc, ByteSize: 2, Cycles: 2, Mode: Immediate, Access: ReadAccess},
+	ALR_IMM:    {Code: ALR_IMM, Mnemonic: "ALR", Operation: alr, ByteSize: 2, Cycles: 2, Mode: Immediate, Access: ReadAccess},
+	ARR_IMM:    {Code: ARR_IMM, Mnemonic: "ARR", Operation: arr, ByteSize: 2, Cycles: 2, Mode: Immediate, Access: ReadAccess},
+	SBX_IMM:    {Code: SBX_IMM, Mnemonic: "SBX", Operation: sbx, ByteSize: 2, Cycles: 2, Mode: Immediate, Access: ReadAccess},
+	SBC_IMM_EB: {Code: SBC_IMM_EB, Mnemonic: "SBC", Operation: sbc, ByteSize: 2, Cycles: 2, Mode: Immediate, Access: ReadAccess},
+
+	// Undocumented Unstable
+	XAA_IMM: {Code: XAA_IMM, Mnemonic: "XAA", Operation: xaa, ByteSize: 2, Cycles: 2, Mode: Immediate, Access: ReadAccess},
+	LXA_IMM: {Code: LXA_IMM, Mnemonic: "LXA", Operation: lxa, ByteSize: 2, Cycles: 2, Mode: Immediate, Access: ReadAccess},
+	AHX_ABY: {Code: AHX_ABY, Mnemonic: "AHX", Operation: ahx, ByteSize: 3, Cycles: 5, Mode: AbsoluteY, Access: UnstableAccess},
+	AHX_IDY: {Code: AHX_IDY, Mnemonic: "AHX", Operation: ahx, ByteSize: 2, Cycles: 6, Mode: IndirectY, Access: UnstableAccess},
+	TAS_ABY: {Code: TAS_ABY, Mnemonic: "TAS", Operation: tas, ByteSize: 3, Cycles: 5, Mode: AbsoluteY, Access: UnstableAccess},
+	SHY_ABX: {Code: SHY_ABX, Mnemonic: "SHY", Operation: shy, ByteSize: 3, Cycles: 5, Mode: AbsoluteX, Access: UnstableAccess},
+	SHX_ABY: {Code: SHX_ABY, Mnemonic: "SHX", Operation: shx, ByteSize: 3, Cycles: 5, Mode: AbsoluteY, Access: UnstableAccess},
+	LAS_ABY: {Code: LAS_ABY, Mnemonic: "LAS", Operation: las, ByteSize: 3, Cycles: 4, Mode: AbsoluteY1, Access: ReadAccess},
 }
 
 const (
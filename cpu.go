@@ -1,10 +1,55 @@
 package go6502
 
-// import "fmt"
+type StopCondition func(c *Cpu, bus Bus) bool
+
+// Tracer is called before each instruction, interrupt sequences excluded.
+// The registers and the cycle count are the ones before the opcode fetch.
+type Tracer func(c *Cpu, bus Bus)
 
 type Cpu struct {
 	Cycle int
 	Registers
+
+	StopWhen StopCondition
+	Trace    Tracer
+	Variant  Variant
+	// HaltOnJam turns JAM opcodes into a halted state instead of an error
+	HaltOnJam bool
+	// TrackCalls follows JSR, RTS, the interrupts and RTI on a call stack,
+	// see Backtrace and Diagnostics
+	TrackCalls bool
+
+	irqLine    bool
+	nmiLine    bool
+	nmiPending bool
+	waiting    bool
+	halted     bool
+	fault      error
+
+	// instruction in progress, program is nil between instructions
+	program   []microStep
+	step      int
+	operation func(c *Cpu, value uint8) uint8
+	opcode    uint8
+	opcodePC  uint16
+
+	// internal latches of the microcode
+	addr        uint16
+	base        uint16
+	data        uint8
+	pageCrossed bool
+	extraCycle  bool
+	vector      uint16
+
+	// the interrupt flag before the last cycle, CLI, SEI and PLP only
+	// affect the interrupt polling after the next instruction
+	iBefore  bool
+	iDelayed bool
+
+	// call stack, with the stack pointer before the instruction
+	spBefore    Register8
+	calls       []Frame
+	diagnostics []Diagnostic
 }
 
 func (c *Cpu) updateZeroAndNegativeFlags(value Register8) {
@@ -21,404 +66,189 @@ func (c *Cpu) updateZeroAndNegativeFlags(value Register8) {
 	}
 }
 
-func zeroPageOperandAddr(pc Register16, bus Bus, register Register8) uint16 {
-	operandAddr := bus.Read(uint16(pc))
-	operandAddr += uint8(register)
-	return uint16(operandAddr)
-}
-
-func absoluteOperandAddr(pc Register16, bus Bus, register Register8) uint16 {
-	operandAddr := bus.ReadWord(uint16(pc))
-	addr := operandAddr + uint16(register)
-	return addr
-}
-
-func incrementWhenPageCrossed(c *Cpu, operandAddr uint16, resAddr uint16) {
-	if (resAddr >> 8) != (operandAddr >> 8) {
-		c.Cycle++
-	}
-}
-
-func (c *Cpu) getOperandAddress(bus Bus, mode int) uint16 {
-	switch mode {
-	case Implied:
-		panic("Implied mode not supported")
-	case Immediate:
-		return uint16(c.ProgramCounter)
-	case ZeroPage:
-		return zeroPageOperandAddr(c.ProgramCounter, bus, 0)
-	case ZeroPageX:
-		return zeroPageOperandAddr(c.ProgramCounter, bus, c.XIndex)
-	case ZeroPageY:
-		return zeroPageOperandAddr(c.ProgramCounter, bus, c.YIndex)
-	case Absolute:
-		return absoluteOperandAddr(c.ProgramCounter, bus, 0)
-	case AbsoluteX:
-		return absoluteOperandAddr(c.ProgramCounter, bus, c.XIndex)
-	case AbsoluteX1:
-		operandAddr := bus.ReadWord(uint16(c.ProgramCounter))
-		res := operandAddr + uint16(c.XIndex)
-		incrementWhenPageCrossed(c, operandAddr, res)
-		return res
-	case AbsoluteY:
-		return absoluteOperandAddr(c.ProgramCounter, bus, c.YIndex)
-	case AbsoluteY1:
-		operandAddr := bus.ReadWord(uint16(c.ProgramCounter))
-		res := operandAddr + uint16(c.YIndex)
-		incrementWhenPageCrossed(c, operandAddr, res)
-		return res
-	case Indirect:
-		operand := bus.ReadWord(uint16(c.ProgramCounter))
-		indirectValue := bus.ReadWord(operand)
-		return indirectValue
-	case IndirectX:
-		operand := bus.Read(uint16(c.ProgramCounter))
-		operand += uint8(c.XIndex)
-		return bus.ReadWord(uint16(operand))
-	case IndirectY:
-		operand := bus.Read(uint16(c.ProgramCounter))
-		word := bus.ReadWord(uint16(operand))
-		res := word + uint16(c.YIndex)
-		return res
-	case IndirectY1:
-		operand := bus.Read(uint16(c.ProgramCounter))
-		word := bus.ReadWord(uint16(operand))
-		res := word + uint16(c.YIndex)
-		incrementWhenPageCrossed(c, word, res)
-		return res
-	default:
-		panic("Addressing mode not implemented")
-	}
-}
-
-func lda(c *Cpu, bus Bus, mode int) {
-	operand := bus.Read(c.getOperandAddress(bus, mode))
-	c.Accumulator = Register8(operand)
-	c.updateZeroAndNegativeFlags(Register8(operand))
+func lda(c *Cpu, value uint8) uint8 {
+	c.Accumulator = Register8(value)
+	c.updateZeroAndNegativeFlags(c.Accumulator)
+	return 0
 }
 
-func ldx(c *Cpu, bus Bus, mode int) {
-	operand := bus.Read(c.getOperandAddress(bus, mode))
-	c.XIndex = Register8(operand)
-	c.updateZeroAndNegativeFlags(Register8(operand))
+func ldx(c *Cpu, value uint8) uint8 {
+	c.XIndex = Register8(value)
+	c.updateZeroAndNegativeFlags(c.XIndex)
+	return 0
 }
 
-func ldy(c *Cpu, bus Bus, mode int) {
-	operand := bus.Read(c.getOperandAddress(bus, mode))
-	c.YIndex = Register8(operand)
-	c.updateZeroAndNegativeFlags(Register8(operand))
+func ldy(c *Cpu, value uint8) uint8 {
+	c.YIndex = Register8(value)
+	c.updateZeroAndNegativeFlags(c.YIndex)
+	return 0
 }
 
-func sta(c *Cpu, bus Bus, mode int) {
-	addrToFill := c.getOperandAddress(bus, mode)
-	bus.Write(uint16(addrToFill), uint8(c.Accumulator))
+func sta(c *Cpu, value uint8) uint8 {
+	return uint8(c.Accumulator)
 }
 
-func stx(c *Cpu, bus Bus, mode int) {
-	addrToFill := c.getOperandAddress(bus, mode)
-	bus.Write(uint16(addrToFill), uint8(c.XIndex))
+func stx(c *Cpu, value uint8) uint8 {
+	return uint8(c.XIndex)
 }
 
-// TODO: impl this func
-// should call the interrupt
-func brk(c *Cpu, bus Bus, mode int) {
+func sty(c *Cpu, value uint8) uint8 {
+	return uint8(c.YIndex)
 }
 
-func nop(c *Cpu, bus Bus, mode int) {
+func nop(c *Cpu, value uint8) uint8 {
 	// purposely does nothing
+	return 0
 }
 
-// TODO: add some tests maybe ?
-func rti(c *Cpu, bus Bus, mode int) {
-	statusFlags := popStack(c, bus)
-	pc := popStack(c, bus)
-
-	c.ProgramCounter = Register16(pc)
-	c.Status = Register8(statusFlags)
-}
-
-func sty(c *Cpu, bus Bus, mode int) {
-	addrToFill := c.getOperandAddress(bus, mode)
-	bus.Write(uint16(addrToFill), uint8(c.YIndex))
-}
-
-func tax(c *Cpu, bus Bus, mode int) {
+func tax(c *Cpu, value uint8) uint8 {
 	c.XIndex = c.Accumulator
 	c.updateZeroAndNegativeFlags(c.XIndex)
+	return 0
 }
 
-func tay(c *Cpu, bus Bus, mode int) {
+func tay(c *Cpu, value uint8) uint8 {
 	c.YIndex = c.Accumulator
 	c.updateZeroAndNegativeFlags(c.YIndex)
+	return 0
 }
 
-func txa(c *Cpu, bus Bus, mode int) {
+func txa(c *Cpu, value uint8) uint8 {
 	c.Accumulator = c.XIndex
 	c.updateZeroAndNegativeFlags(c.Accumulator)
+	return 0
 }
 
-func tya(c *Cpu, bus Bus, mode int) {
+func tya(c *Cpu, value uint8) uint8 {
 	c.Accumulator = c.YIndex
 	c.updateZeroAndNegativeFlags(c.Accumulator)
+	return 0
 }
 
-// TODO: Maybe add tests for other mods
-func and(c *Cpu, bus Bus, mode int) {
-	operand := bus.Read(c.getOperandAddress(bus, mode))
-	c.Accumulator &= Register8(operand)
-
+func and(c *Cpu, value uint8) uint8 {
+	c.Accumulator &= Register8(value)
 	c.updateZeroAndNegativeFlags(c.Accumulator)
+	return 0
 }
 
-func eor(c *Cpu, bus Bus, mode int) {
-	operand := bus.Read(c.getOperandAddress(bus, mode))
-	c.Accumulator ^= Register8(operand)
-
+func eor(c *Cpu, value uint8) uint8 {
+	c.Accumulator ^= Register8(value)
 	c.updateZeroAndNegativeFlags(c.Accumulator)
+	return 0
 }
 
-func aor(c *Cpu, bus Bus, mode int) {
-	operand := bus.Read(c.getOperandAddress(bus, mode))
-	c.Accumulator |= Register8(operand)
-
+func aor(c *Cpu, value uint8) uint8 {
+	c.Accumulator |= Register8(value)
 	c.updateZeroAndNegativeFlags(c.Accumulator)
+	return 0
 }
 
-// TODO: may need some verif
-func bit(c *Cpu, bus Bus, mode int) {
-	data := bus.Read(c.getOperandAddress(bus, mode))
-
-	and := uint8(c.Accumulator) & data
-
-	if and == 0 {
-		c.Status.Add(Zero)
-	} else {
-		c.Status.Remove(Zero)
-	}
-
-	if data&0b1000_0000 > 0 {
-		c.Status.Add(Negative)
-	} else {
-		c.Status.Remove(Negative)
-	}
-
-	if data&0b0100_0000 > 0 {
-		c.Status.Add(Verflow)
-	} else {
-		c.Status.Remove(Verflow)
-	}
-
-	// aCopy := c.Accumulator
-	// aCopy &= Register8(operand)
-
-	// if aCopy == 0 {
-	// 	c.Status.Add(Zero)
-	// } else {
-	// 	c.Status.Remove(Zero)
-	// }
-
-	// if operand&Negative == 0 {
-	// 	c.Status.Remove(Negative)
-	// } else {
-	// 	c.Status.Add(Negative)
-	// }
+func bit(c *Cpu, value uint8) uint8 {
+	bitImmediate(c, value)
+	c.Status.Set(7, value&0b1000_0000 != 0)
+	c.Status.Set(6, value&0b0100_0000 != 0)
+	return 0
+}
 
-	// if operand&Verflow == 0 {
-	// 	c.Status.Remove(Verflow)
-	// } else {
-	// 	c.Status.Add(Verflow)
-	// }
+// the 65C02 immediate form only touches the zero flag
+func bitImmediate(c *Cpu, value uint8) uint8 {
+	c.Status.Set(1, uint8(c.Accumulator)&value == 0)
+	return 0
 }
 
-func tsx(c *Cpu, bus Bus, mode int) {
+func tsx(c *Cpu, value uint8) uint8 {
 	c.XIndex = c.StackPointer
 	c.updateZeroAndNegativeFlags(c.XIndex)
+	return 0
 }
 
-func txs(c *Cpu, bus Bus, mode int) {
+func txs(c *Cpu, value uint8) uint8 {
 	c.StackPointer = c.XIndex
+	return 0
 }
 
-func pushStack(c *Cpu, bus Bus, value uint8) {
-	bus.Write(0x0100+uint16(c.StackPointer), value)
-	c.StackPointer--
-}
-
-func popStack(c *Cpu, bus Bus) uint8 {
-	c.StackPointer++
-	val := bus.Read(0x0100 + uint16(c.StackPointer))
-
-	return val
+func pha(c *Cpu, value uint8) uint8 {
+	return uint8(c.Accumulator)
 }
 
-// TODO: may wrap the stack pointer
-func pha(c *Cpu, bus Bus, mode int) {
-	pushStack(c, bus, uint8(c.Accumulator))
-}
-
-func php(c *Cpu, bus Bus, mode int) {
+func php(c *Cpu, value uint8) uint8 {
 	flags := c.Status
 	flags.Add(Break)
 	flags.Add(Break2)
-
-	pushStack(c, bus, uint8(c.Status))
+	return uint8(flags)
 }
 
-// TODO: may wrap the stack pointer also
-// Should learn about the B flag
-func pla(c *Cpu, bus Bus, mode int) {
-	val := popStack(c, bus)
-	c.Accumulator = Register8(val)
+func pla(c *Cpu, value uint8) uint8 {
+	c.Accumulator = Register8(value)
 	c.updateZeroAndNegativeFlags(c.Accumulator)
+	return 0
 }
 
-// TODO: should go deeper for tests
-func plp(c *Cpu, bus Bus, mode int) {
-	val := popStack(c, bus)
-	c.Status = Register8(val)
-
+func plp(c *Cpu, value uint8) uint8 {
+	c.Status = Register8(value)
 	c.Status.Remove(Break)
 	c.Status.Add(Break2)
+	return 0
 }
 
-func inc(c *Cpu, bus Bus, mode int) {
-	addr := c.getOperandAddress(bus, mode)
-	b := bus.Read(addr)
-	bus.Write(addr, b+1)
-	c.updateZeroAndNegativeFlags(Register8(b + 1))
+func inc(c *Cpu, value uint8) uint8 {
+	value++
+	c.updateZeroAndNegativeFlags(Register8(value))
+	return value
 }
 
-func inx(c *Cpu, bus Bus, mode int) {
+func inx(c *Cpu, value uint8) uint8 {
 	c.XIndex++
 	c.updateZeroAndNegativeFlags(c.XIndex)
+	return 0
 }
 
-func iny(c *Cpu, bus Bus, mode int) {
+func iny(c *Cpu, value uint8) uint8 {
 	c.YIndex++
 	c.updateZeroAndNegativeFlags(c.YIndex)
+	return 0
 }
 
-func dec(c *Cpu, bus Bus, mode int) {
-	addr := c.getOperandAddress(bus, mode)
-	b := bus.Read(addr)
-	bus.Write(addr, b-1)
-	c.updateZeroAndNegativeFlags(Register8(b - 1))
+func dec(c *Cpu, value uint8) uint8 {
+	value--
+	c.updateZeroAndNegativeFlags(Register8(value))
+	return value
 }
 
-func dex(c *Cpu, bus Bus, mode int) {
+func dex(c *Cpu, value uint8) uint8 {
 	c.XIndex--
 	c.updateZeroAndNegativeFlags(c.XIndex)
+	return 0
 }
 
-func dey(c *Cpu, bus Bus, mode int) {
+func dey(c *Cpu, value uint8) uint8 {
 	c.YIndex--
 	c.updateZeroAndNegativeFlags(c.YIndex)
+	return 0
 }
 
-func rol(c *Cpu, bus Bus, mode int) {
-	if mode == Accumulator {
-		isOldBit7Set := c.Accumulator&0b1000_0000 != 0
-		c.Accumulator <<= 1
-		c.Accumulator.Set(0, c.Status.Has(Carry))
-		c.Status.Set(0, isOldBit7Set)
-		c.updateZeroAndNegativeFlags(c.Accumulator)
-	} else {
-		addr := c.getOperandAddress(bus, mode)
-		value := bus.Read(addr)
-
-		isOldBit7Set := value&0b1000_0000 != 0
-		value <<= 1
-		if c.Status.Has(Carry) {
-			value |= 0b0000_0001
-		} else {
-			value &^= 0b0000_0001
-		}
-		c.Status.Set(0, isOldBit7Set)
-
-		bus.Write(addr, value)
-		c.updateZeroAndNegativeFlags(Register8(value))
-	}
+func rol(c *Cpu, value uint8) uint8 {
+	value = c.shiftLeft(value, c.Status.Has(Carry))
+	c.updateZeroAndNegativeFlags(Register8(value))
+	return value
 }
 
-// TODO: Maybe adding more tests
-func ror(c *Cpu, bus Bus, mode int) {
-	if mode == Accumulator {
-		isOldBit0Set := c.Accumulator&0b0000_0001 != 0
-		c.Accumulator >>= 1
-		c.Accumulator.Set(7, c.Status.Has(Carry))
-		c.Status.Set(0, isOldBit0Set)
-		c.updateZeroAndNegativeFlags(c.Accumulator)
-	} else {
-		addr := c.getOperandAddress(bus, mode)
-		value := bus.Read(addr)
-
-		isOldBit0Set := value&0b0000_0001 != 0
-		value >>= 1
-
-		if c.Status.Has(Carry) {
-			value |= 0b1000_0000
-		} else {
-			value &^= 0b1000_0000
-		}
-		c.Status.Set(0, isOldBit0Set)
-
-		bus.Write(addr, value)
-		c.updateZeroAndNegativeFlags(Register8(value))
-	}
+func ror(c *Cpu, value uint8) uint8 {
+	value = c.shiftRight(value, c.Status.Has(Carry))
+	c.updateZeroAndNegativeFlags(Register8(value))
+	return value
 }
 
-func lsr(c *Cpu, bus Bus, mode int) {
-	if mode == Accumulator {
-		if c.Accumulator.Has(0b0000_0001) {
-			c.Status.Add(Carry)
-		} else {
-			c.Status.Remove(Carry)
-		}
-
-		c.Accumulator >>= 1
-		c.updateZeroAndNegativeFlags(c.Accumulator)
-	} else {
-		addr := c.getOperandAddress(bus, mode)
-		value := bus.Read(addr)
-
-		if value&0b0000_0001 == 0 {
-			c.Status.Remove(Carry)
-		} else {
-			c.Status.Add(Carry)
-		}
-
-		value >>= 1
-
-		bus.Write(addr, value)
-		c.updateZeroAndNegativeFlags(Register8(value))
-	}
+func lsr(c *Cpu, value uint8) uint8 {
+	value = c.shiftRight(value, false)
+	c.updateZeroAndNegativeFlags(Register8(value))
+	return value
 }
 
-func asl(c *Cpu, bus Bus, mode int) {
-	if mode == Accumulator {
-		if c.Accumulator.Has(0b1000_0000) {
-			c.Status.Add(Carry)
-		} else {
-			c.Status.Remove(Carry)
-		}
-
-		c.Accumulator <<= 1
-		c.updateZeroAndNegativeFlags(c.Accumulator)
-	} else {
-		addr := c.getOperandAddress(bus, mode)
-		value := bus.Read(addr)
-
-		if value&Negative == 0 {
-			c.Status.Remove(Carry)
-		} else {
-			c.Status.Add(Carry)
-		}
-
-		value <<= 1
-
-		bus.Write(addr, value)
-		c.updateZeroAndNegativeFlags(Register8(value))
-	}
+func asl(c *Cpu, value uint8) uint8 {
+	value = c.shiftLeft(value, false)
+	c.updateZeroAndNegativeFlags(Register8(value))
+	return value
 }
 
 func (c *Cpu) addToRegisterA(value uint8) {
@@ -444,153 +274,187 @@ func (c *Cpu) addToRegisterA(value uint8) {
 	c.Accumulator = Register8(result)
 }
 
-// TODO: adding decilam addition later
-func adc(c *Cpu, bus Bus, mode int) {
-	addr := c.getOperandAddress(bus, mode)
-	data := bus.Read(addr)
-	c.addToRegisterA(data)
-	c.updateZeroAndNegativeFlags(c.Accumulator)
-}
-
-// TODO: add tests
-func sbc(c *Cpu, bus Bus, mode int) {
-	addr := c.getOperandAddress(bus, mode)
-	data := bus.Read(addr)
-	data = -data
-	c.addToRegisterA(data)
+func adc(c *Cpu, value uint8) uint8 {
+	if c.decimalMode() {
+		c.addDecimalToRegisterA(value)
+		return 0
+	}
+	c.addToRegisterA(value)
 	c.updateZeroAndNegativeFlags(c.Accumulator)
+	return 0
 }
 
-// TODO: add tests
-func (c *Cpu) compare(bus Bus, compareWith uint8) {
-
-	if compareWith <= uint8(c.Accumulator) {
-		c.Status.Add(Carry)
-	} else {
-		c.Status.Remove(Carry)
+func sbc(c *Cpu, value uint8) uint8 {
+	if c.decimalMode() {
+		c.subDecimalFromRegisterA(value)
+		return 0
 	}
-
-}
-
-func cmp(c *Cpu, bus Bus, mode int) {
-	data := bus.Read(c.getOperandAddress(bus, mode))
-	c.compare(bus, data)
-	c.updateZeroAndNegativeFlags(c.Accumulator - Register8(data))
+	c.addToRegisterA(^value)
+	c.updateZeroAndNegativeFlags(c.Accumulator)
+	return 0
 }
 
-func cpx(c *Cpu, bus Bus, mode int) {
-	data := bus.Read(c.getOperandAddress(bus, mode))
-	c.compare(bus, data)
-	c.updateZeroAndNegativeFlags(c.XIndex - Register8(data))
+// compare sets the flags of register minus value, the carry when there is
+// no borrow
+func (c *Cpu) compare(register Register8, value uint8) {
+	c.Status.Set(0, uint8(register) >= value)
+	c.updateZeroAndNegativeFlags(register - Register8(value))
 }
 
-func cpy(c *Cpu, bus Bus, mode int) {
-	data := bus.Read(c.getOperandAddress(bus, mode))
-	c.compare(bus, data)
-	c.updateZeroAndNegativeFlags(c.YIndex - Register8(data))
+func cmp(c *Cpu, value uint8) uint8 {
+	c.compare(c.Accumulator, value)
+	return 0
 }
 
-func jsr(c *Cpu, bus Bus, mode int) {
-	operand := c.getOperandAddress(bus, mode)
-	pushStack(c, bus, uint8((c.ProgramCounter+2)>>8))
-	pushStack(c, bus, uint8((c.ProgramCounter + 2)))
-	c.ProgramCounter = Register16(operand)
+func cpx(c *Cpu, value uint8) uint8 {
+	c.compare(c.XIndex, value)
+	return 0
 }
 
-// TODO: check if the order of operation is right
-func rts(c *Cpu, bus Bus, mode int) {
-	var lo uint16 = uint16(popStack(c, bus))
-	var hi uint16 = uint16(popStack(c, bus))
-
-	// fmt.Printf("before rts: %04x\n", c.ProgramCounter)
-	c.ProgramCounter = Register16(((hi << 8) | lo))
-	// fmt.Printf("after  rts: %04x\n", c.ProgramCounter)
+func cpy(c *Cpu, value uint8) uint8 {
+	c.compare(c.YIndex, value)
+	return 0
 }
 
-func branch(c *Cpu, bus Bus, condition bool) {
+func taken(condition bool) uint8 {
 	if condition {
-		var jump int8 = int8(bus.Read(uint16(c.ProgramCounter)))
-		c.ProgramCounter += Register16(jump)
+		return 1
 	}
+	return 0
 }
 
-// TODO: may check if branching works correctly to the TODO
-func bcc(c *Cpu, bus Bus, mode int) {
-	branch(c, bus, !c.Status.Has(Carry))
+func bcc(c *Cpu, value uint8) uint8 {
+	return taken(!c.Status.Has(Carry))
 }
 
-func bcs(c *Cpu, bus Bus, mode int) {
-	branch(c, bus, c.Status.Has(Carry))
+func bcs(c *Cpu, value uint8) uint8 {
+	return taken(c.Status.Has(Carry))
 }
 
-func beq(c *Cpu, bus Bus, mode int) {
-	branch(c, bus, c.Status.Has(Zero))
+func beq(c *Cpu, value uint8) uint8 {
+	return taken(c.Status.Has(Zero))
 }
 
-func bmi(c *Cpu, bus Bus, mode int) {
-	branch(c, bus, c.Status.Has(Negative))
+func bmi(c *Cpu, value uint8) uint8 {
+	return taken(c.Status.Has(Negative))
 }
 
-func bne(c *Cpu, bus Bus, mode int) {
-	branch(c, bus, !c.Status.Has(Zero))
+func bne(c *Cpu, value uint8) uint8 {
+	return taken(!c.Status.Has(Zero))
 }
 
-func bpl(c *Cpu, bus Bus, mode int) {
-	branch(c, bus, !c.Status.Has(Negative))
+func bpl(c *Cpu, value uint8) uint8 {
+	return taken(!c.Status.Has(Negative))
 }
 
-func bvc(c *Cpu, bus Bus, mode int) {
-	branch(c, bus, !c.Status.Has(Verflow))
+func bvc(c *Cpu, value uint8) uint8 {
+	return taken(!c.Status.Has(Verflow))
 }
 
-func bvs(c *Cpu, bus Bus, mode int) {
-	branch(c, bus, c.Status.Has(Verflow))
+func bvs(c *Cpu, value uint8) uint8 {
+	return taken(c.Status.Has(Verflow))
 }
 
-// TODO: check if the increment doesn't happen in the same instruction cycle
-func jmp(c *Cpu, bus Bus, mode int) {
-	operand := c.getOperandAddress(bus, mode)
-	c.ProgramCounter = Register16(operand)
-}
-
-func clc(c *Cpu, bus Bus, mode int) {
+func clc(c *Cpu, value uint8) uint8 {
 	c.Status.Remove(Carry)
+	return 0
 }
 
-func cld(c *Cpu, bus Bus, mode int) {
+func cld(c *Cpu, value uint8) uint8 {
 	c.Status.Remove(Decimal)
+	return 0
 }
 
-func cli(c *Cpu, bus Bus, mode int) {
+func cli(c *Cpu, value uint8) uint8 {
 	c.Status.Remove(Interrupt)
+	return 0
 }
 
-func clv(c *Cpu, bus Bus, mode int) {
+func clv(c *Cpu, value uint8) uint8 {
 	c.Status.Remove(Verflow)
+	return 0
 }
 
-func sec(c *Cpu, bus Bus, mode int) {
+func sec(c *Cpu, value uint8) uint8 {
 	c.Status.Add(Carry)
+	return 0
 }
 
-func sed(c *Cpu, bus Bus, mode int) {
+func sed(c *Cpu, value uint8) uint8 {
 	c.Status.Add(Decimal)
+	return 0
 }
 
-func sei(c *Cpu, bus Bus, mode int) {
+func sei(c *Cpu, value uint8) uint8 {
 	c.Status.Add(Interrupt)
-}
+	return 0
+}
+
+// begin runs the first cycle of an instruction, it fetches the opcode or
+// starts the interrupt sequence in place of it
+func (c *Cpu) begin(bus Bus) error {
+	// a masked IRQ still wakes up WAI, execution resumes after it. The
+	// waiting cpu keeps reading the next opcode.
+	if c.waiting {
+		if !c.irqLine && !c.nmiPending {
+			c.Cycle++
+			bus.Read(uint16(c.ProgramCounter))
+			return nil
+		}
+		c.waiting = false
+	}
+
+	c.step = 0
+	c.opcodePC = uint16(c.ProgramCounter)
+	c.spBefore = c.StackPointer
+	if c.interruptRequested() {
+		c.Cycle++
+		bus.Read(uint16(c.ProgramCounter))
+		c.program = interruptProgram
+		return nil
+	}
+
+	if c.Trace != nil {
+		c.Trace(c, bus)
+	}
+	c.Cycle++
+	c.iBefore = c.Status.Has(Interrupt)
+	c.opcode = c.fetch(bus)
+	opc := &c.Variant.dispatch()[c.opcode]
+	if !opc.known {
+		c.ProgramCounter--
+		return &OpcodeError{PC: c.opcodePC, Opcode: c.opcode, Cycle: c.Cycle, Err: ErrUnknownOpcode}
+	}
 
-func (c *Cpu) interpret(opcode uint8, bus Bus) {
-	opc, ok := Opcodes[opcode]
-	if !ok {
-		panic("UNKOWN OPCODE")
+	c.operation = opc.operation
+	c.program = opc.program
+	if len(c.program) == 0 {
+		return c.finish()
 	}
+	return nil
+}
 
-	opc.Operation(c, bus, opc.Mode)
+func (c *Cpu) finish() error {
+	c.iDelayed = c.iBefore != c.Status.Has(Interrupt)
+
+	// the 65C02 pays one more cycle for decimal arithmetic
+	if c.extraCycle {
+		c.extraCycle = false
+		c.program = extraCycleProgram
+		c.step = 0
+		return nil
+	}
 
-	c.Cycle += opc.Cycles
-	c.ProgramCounter += Register16(opc.ByteSize - 1)
+	if c.TrackCalls {
+		c.trackCalls()
+	}
+	c.program = nil
+	if c.fault != nil {
+		err := &OpcodeError{PC: c.opcodePC, Opcode: c.opcode, Cycle: c.Cycle, Err: c.fault}
+		c.fault = nil
+		return err
+	}
+	return nil
 }
 
 func (c *Cpu) Reset(bus Bus) {
@@ -599,24 +463,70 @@ func (c *Cpu) Reset(bus Bus) {
 	c.YIndex = 0
 	c.StackPointer = 0xFF
 	c.Status = 0
+	c.nmiPending = false
+	c.waiting = false
+	c.halted = false
+	c.fault = nil
+	c.program = nil
+	c.extraCycle = false
+	c.iDelayed = false
+	c.calls = c.calls[:0]
+	c.diagnostics = c.diagnostics[:0]
+
+	c.ProgramCounter = Register16(bus.ReadWord(ResetVector))
+}
+
+// Halted reports whether the cpu is stopped by STP or by a JAM opcode with
+// HaltOnJam set. Only Reset resumes it.
+func (c *Cpu) Halted() bool {
+	return c.halted
+}
+
+// Tick advances the cpu by one clock cycle, issuing exactly one bus access.
+// A halted cpu does nothing. An instruction that cannot be executed returns
+// an *OpcodeError on its last cycle.
+func (c *Cpu) Tick(bus Bus) error {
+	if c.halted {
+		return nil
+	}
+	if c.program == nil {
+		return c.begin(bus)
+	}
 
-	c.ProgramCounter = Register16(bus.ReadWord(0xFFFC))
+	c.Cycle++
+	c.iBefore = c.Status.Has(Interrupt)
+	c.program[c.step](c, bus)
+	c.step++
+	if c.step < len(c.program) {
+		return nil
+	}
+	return c.finish()
 }
 
-func (c *Cpu) Step(bus Bus) bool {
-	opcode := bus.Read(uint16(c.ProgramCounter))
-	c.ProgramCounter++
-
-	c.interpret(opcode, bus)
-	return opcode == BRK_IMP
+// Step executes one instruction or services one interrupt. When Tick left
+// an instruction half done, Step completes it instead. An instruction that
+// cannot be executed returns an *OpcodeError.
+func (c *Cpu) Step(bus Bus) error {
+	if err := c.Tick(bus); err != nil {
+		return err
+	}
+	for c.program != nil {
+		if err := c.Tick(bus); err != nil {
+			return err
+		}
+	}
+	return nil
 }
 
-func (c *Cpu) Run(bus Bus) {
-	for {
-		shouldExit := c.Step(bus)
-
-		if shouldExit {
-			break
+// Run executes instructions until StopWhen reports true, the cpu halts or
+// an instruction fails. The condition is checked before each instruction,
+// a nil StopWhen never stops. RunFor, RunInstructions, RunUntil and
+// RunContext bound the execution.
+func (c *Cpu) Run(bus Bus) error {
+	for !c.halted && (c.StopWhen == nil || !c.StopWhen(c, bus)) {
+		if err := c.Step(bus); err != nil {
+			return err
 		}
 	}
+	return nil
 }
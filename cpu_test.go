@@ -10,37 +10,37 @@ func TestLdaImmediatePositiveValue(t *testing.T) {
 	memory := Mem{
 		LDA_IMM, 0x10, BRK_IMP,
 	}
-	cpu := Cpu{}
+	cpu := Cpu{StopWhen: StopOnOpcode(BRK_IMP)}
 	cpu.Run(BusEx{&memory})
 
 	asrt.Equal(t, cpu.Accumulator, Register8(0x10))
 	asrt.False(t, cpu.Status.Has(Negative))
 	asrt.False(t, cpu.Status.Has(Zero))
-	asrt.Equal(t, cpu.Cycle, Opcodes[LDA_IMM].Cycles+Opcodes[BRK_IMP].Cycles)
+	asrt.Equal(t, cpu.Cycle, Opcodes[LDA_IMM].Cycles)
 }
 
 func TestLdaImmediateZeroValue(t *testing.T) {
 	memory := Mem{
 		LDA_IMM, 0x00, BRK_IMP,
 	}
-	cpu := Cpu{}
+	cpu := Cpu{StopWhen: StopOnOpcode(BRK_IMP)}
 	cpu.Run(BusEx{&memory})
 
 	asrt.Equal(t, cpu.Accumulator, Register8(0x00))
 	asrt.False(t, cpu.Status.Has(Negative))
 	asrt.True(t, cpu.Status.Has(Zero))
-	asrt.Equal(t, cpu.Cycle, Opcodes[LDA_IMM].Cycles+Opcodes[BRK_IMP].Cycles)
+	asrt.Equal(t, cpu.Cycle, Opcodes[LDA_IMM].Cycles)
 }
 
 func TestLdaImmediateNegativeValue(t *testing.T) {
 	memory := Mem{
 		LDA_IMM, 0x80, BRK_IMP,
 	}
-	cpu := Cpu{}
+	cpu := Cpu{StopWhen: StopOnOpcode(BRK_IMP)}
 	cpu.Run(BusEx{&memory})
 
 	asrt.Equal(t, cpu.Accumulator, Register8(0x80))
-	asrt.Equal(t, cpu.Cycle, Opcodes[LDA_IMM].Cycles+Opcodes[BRK_IMP].Cycles)
+	asrt.Equal(t, cpu.Cycle, Opcodes[LDA_IMM].Cycles)
 	asrt.True(t, cpu.Status.Has(Negative))
 	asrt.False(t, cpu.Status.Has(Zero))
 }
@@ -51,11 +51,11 @@ func TestLdaZeroPagePositiveValue(t *testing.T) {
 	}
 	memory[0xAA] = 0x67
 
-	cpu := Cpu{}
+	cpu := Cpu{StopWhen: StopOnOpcode(BRK_IMP)}
 	cpu.Run(BusEx{&memory})
 
 	asrt.Equal(t, cpu.Accumulator, Register8(0x67))
-	asrt.Equal(t, cpu.Cycle, Opcodes[LDA_ZER].Cycles+Opcodes[BRK_IMP].Cycles)
+	asrt.Equal(t, cpu.Cycle, Opcodes[LDA_ZER].Cycles)
 	asrt.False(t, cpu.Status.Has(Negative))
 	asrt.False(t, cpu.Status.Has(Zero))
 }
@@ -66,11 +66,11 @@ func TestLdaZeroPageNegativeValue(t *testing.T) {
 	}
 	memory[0xAA] = 0xDF
 
-	cpu := Cpu{}
+	cpu := Cpu{StopWhen: StopOnOpcode(BRK_IMP)}
 	cpu.Run(BusEx{&memory})
 
 	asrt.Equal(t, cpu.Accumulator, Register8(0xDF))
-	asrt.Equal(t, cpu.Cycle, Opcodes[LDA_ZER].Cycles+Opcodes[BRK_IMP].Cycles)
+	asrt.Equal(t, cpu.Cycle, Opcodes[LDA_ZER].Cycles)
 	asrt.True(t, cpu.Status.Has(Negative))
 	asrt.False(t, cpu.Status.Has(Zero))
 }
@@ -81,11 +81,11 @@ func TestLdaZeroPageZeroValue(t *testing.T) {
 	}
 	memory[0xAA] = 0x00
 
-	cpu := Cpu{}
+	cpu := Cpu{StopWhen: StopOnOpcode(BRK_IMP)}
 	cpu.Run(BusEx{&memory})
 
 	asrt.Equal(t, cpu.Accumulator, Register8(0x00))
-	asrt.Equal(t, cpu.Cycle, Opcodes[LDA_ZER].Cycles+Opcodes[BRK_IMP].Cycles)
+	asrt.Equal(t, cpu.Cycle, Opcodes[LDA_ZER].Cycles)
 	asrt.False(t, cpu.Status.Has(Negative))
 	asrt.True(t, cpu.Status.Has(Zero))
 }
@@ -96,12 +96,12 @@ func TestLdaZeroPageXPositiveValue(t *testing.T) {
 	}
 	memory[0x30] = 0x79
 
-	cpu := Cpu{}
+	cpu := Cpu{StopWhen: StopOnOpcode(BRK_IMP)}
 	cpu.XIndex = 0x10
 	cpu.Run(BusEx{&memory})
 
 	asrt.Equal(t, cpu.Accumulator, Register8(0x79))
-	asrt.Equal(t, cpu.Cycle, Opcodes[LDA_ZRX].Cycles+Opcodes[BRK_IMP].Cycles)
+	asrt.Equal(t, cpu.Cycle, Opcodes[LDA_ZRX].Cycles)
 	asrt.False(t, cpu.Status.Has(Negative))
 	asrt.False(t, cpu.Status.Has(Zero))
 }
@@ -112,12 +112,12 @@ func TestLdaZeroPageXNegativeValue(t *testing.T) {
 	}
 	memory[0x30] = 0xEF
 
-	cpu := Cpu{}
+	cpu := Cpu{StopWhen: StopOnOpcode(BRK_IMP)}
 	cpu.XIndex = 0x10
 	cpu.Run(BusEx{&memory})
 
 	asrt.Equal(t, cpu.Accumulator, Register8(0xEF))
-	asrt.Equal(t, cpu.Cycle, Opcodes[LDA_ZRX].Cycles+Opcodes[BRK_IMP].Cycles)
+	asrt.Equal(t, cpu.Cycle, Opcodes[LDA_ZRX].Cycles)
 	asrt.True(t, cpu.Status.Has(Negative))
 	asrt.False(t, cpu.Status.Has(Zero))
 }
@@ -128,12 +128,12 @@ func TestLdaZeroPageXZeroValue(t *testing.T) {
 	}
 	memory[0x30] = 0x00
 
-	cpu := Cpu{}
+	cpu := Cpu{StopWhen: StopOnOpcode(BRK_IMP)}
 	cpu.XIndex = 0x10
 	cpu.Run(BusEx{&memory})
 
 	asrt.Equal(t, cpu.Accumulator, Register8(0x00))
-	asrt.Equal(t, cpu.Cycle, Opcodes[LDA_ZRX].Cycles+Opcodes[BRK_IMP].Cycles)
+	asrt.Equal(t, cpu.Cycle, Opcodes[LDA_ZRX].Cycles)
 	asrt.False(t, cpu.Status.Has(Negative))
 	asrt.True(t, cpu.Status.Has(Zero))
 }
@@ -144,12 +144,12 @@ func TestLdaZeroPageXWrappingValue(t *testing.T) {
 	}
 	memory[0x7F] = 0x22
 
-	cpu := Cpu{}
+	cpu := Cpu{StopWhen: StopOnOpcode(BRK_IMP)}
 	cpu.XIndex = 0xFF
 	cpu.Run(BusEx{&memory})
 
 	asrt.Equal(t, cpu.Accumulator, Register8(0x22))
-	asrt.Equal(t, cpu.Cycle, Opcodes[LDA_ZRX].Cycles+Opcodes[BRK_IMP].Cycles)
+	asrt.Equal(t, cpu.Cycle, Opcodes[LDA_ZRX].Cycles)
 	asrt.False(t, cpu.Status.Has(Negative))
 	asrt.False(t, cpu.Status.Has(Zero))
 }
@@ -160,11 +160,11 @@ func TestLdaAbsolutePositiveValue(t *testing.T) {
 	}
 	memory[0x01fe] = 0x33
 
-	cpu := Cpu{}
+	cpu := Cpu{StopWhen: StopOnOpcode(BRK_IMP)}
 	cpu.Run(BusEx{&memory})
 
 	asrt.Equal(t, cpu.Accumulator, Register8(0x33))
-	asrt.Equal(t, cpu.Cycle, Opcodes[LDA_ABS].Cycles+Opcodes[BRK_IMP].Cycles)
+	asrt.Equal(t, cpu.Cycle, Opcodes[LDA_ABS].Cycles)
 	asrt.False(t, cpu.Status.Has(Negative))
 	asrt.False(t, cpu.Status.Has(Zero))
 }
@@ -175,11 +175,11 @@ func TestLdaAbsoluteNegativeValue(t *testing.T) {
 	}
 	memory[0x01fe] = 0xAA
 
-	cpu := Cpu{}
+	cpu := Cpu{StopWhen: StopOnOpcode(BRK_IMP)}
 	cpu.Run(BusEx{&memory})
 
 	asrt.Equal(t, cpu.Accumulator, Register8(0xAA))
-	asrt.Equal(t, cpu.Cycle, Opcodes[LDA_ABS].Cycles+Opcodes[BRK_IMP].Cycles)
+	asrt.Equal(t, cpu.Cycle, Opcodes[LDA_ABS].Cycles)
 	asrt.True(t, cpu.Status.Has(Negative))
 	asrt.False(t, cpu.Status.Has(Zero))
 }
@@ -190,11 +190,11 @@ func TestLdaAbsoluteZeroValue(t *testing.T) {
 	}
 	memory[0x01fe] = 0x00
 
-	cpu := Cpu{}
+	cpu := Cpu{StopWhen: StopOnOpcode(BRK_IMP)}
 	cpu.Run(BusEx{&memory})
 
 	asrt.Equal(t, cpu.Accumulator, Register8(0x00))
-	asrt.Equal(t, cpu.Cycle, Opcodes[LDA_ABS].Cycles+Opcodes[BRK_IMP].Cycles)
+	asrt.Equal(t, cpu.Cycle, Opcodes[LDA_ABS].Cycles)
 	asrt.False(t, cpu.Status.Has(Negative))
 	asrt.True(t, cpu.Status.Has(Zero))
 }
@@ -206,12 +206,12 @@ func TestLdaAbsoluteXPositiveValueCrossedPage(t *testing.T) {
 	// 0x01ff+0x0010 = 0x020f
 	memory[0x020f] = 0x66
 
-	cpu := Cpu{}
+	cpu := Cpu{StopWhen: StopOnOpcode(BRK_IMP)}
 	cpu.XIndex = 0x10
 	cpu.Run(BusEx{&memory})
 
 	asrt.Equal(t, cpu.Accumulator, Register8(0x66))
-	asrt.Equal(t, cpu.Cycle, Opcodes[LDA_ABX].Cycles+Opcodes[BRK_IMP].Cycles+1)
+	asrt.Equal(t, cpu.Cycle, Opcodes[LDA_ABX].Cycles+1)
 	asrt.False(t, cpu.Status.Has(Negative))
 	asrt.False(t, cpu.Status.Has(Zero))
 }
@@ -222,12 +222,12 @@ func TestLdaAbsoluteXPositiveValueNotCrossedPage(t *testing.T) {
 	}
 	memory[0x0110] = 0x77
 
-	cpu := Cpu{}
+	cpu := Cpu{StopWhen: StopOnOpcode(BRK_IMP)}
 	cpu.XIndex = 0x10
 	cpu.Run(BusEx{&memory})
 
 	asrt.Equal(t, cpu.Accumulator, Register8(0x77))
-	asrt.Equal(t, cpu.Cycle, Opcodes[LDA_ABX].Cycles+Opcodes[BRK_IMP].Cycles)
+	asrt.Equal(t, cpu.Cycle, Opcodes[LDA_ABX].Cycles)
 	asrt.False(t, cpu.Status.Has(Negative))
 	asrt.False(t, cpu.Status.Has(Zero))
 }
@@ -238,12 +238,12 @@ func TestLdaAbsoluteXNegativeValue(t *testing.T) {
 	}
 	memory[0x0130] = 0x90
 
-	cpu := Cpu{}
+	cpu := Cpu{StopWhen: StopOnOpcode(BRK_IMP)}
 	cpu.XIndex = 0x10
 	cpu.Run(BusEx{&memory})
 
 	asrt.Equal(t, cpu.Accumulator, Register8(0x90))
-	asrt.Equal(t, cpu.Cycle, Opcodes[LDA_ABX].Cycles+Opcodes[BRK_IMP].Cycles)
+	asrt.Equal(t, cpu.Cycle, Opcodes[LDA_ABX].Cycles)
 	asrt.True(t, cpu.Status.Has(Negative))
 	asrt.False(t, cpu.Status.Has(Zero))
 }
@@ -254,12 +254,12 @@ func TestLdaAbsoluteXZeroValue(t *testing.T) {
 	}
 	memory[0x0110] = 0x00
 
-	cpu := Cpu{}
+	cpu := Cpu{StopWhen: StopOnOpcode(BRK_IMP)}
 	cpu.XIndex = 0x10
 	cpu.Run(BusEx{&memory})
 
 	asrt.Equal(t, cpu.Accumulator, Register8(0x00))
-	asrt.Equal(t, cpu.Cycle, Opcodes[LDA_ABX].Cycles+Opcodes[BRK_IMP].Cycles)
+	asrt.Equal(t, cpu.Cycle, Opcodes[LDA_ABX].Cycles)
 	asrt.False(t, cpu.Status.Has(Negative))
 	asrt.True(t, cpu.Status.Has(Zero))
 }
@@ -270,12 +270,12 @@ func TestLdaAbsoluteYPositiveValueNotCrossed(t *testing.T) {
 	}
 	memory[0x0A30] = 0x45
 
-	cpu := Cpu{}
+	cpu := Cpu{StopWhen: StopOnOpcode(BRK_IMP)}
 	cpu.YIndex = 0x20
 	cpu.Run(BusEx{&memory})
 
 	asrt.Equal(t, cpu.Accumulator, Register8(0x45))
-	asrt.Equal(t, cpu.Cycle, Opcodes[LDA_ABX].Cycles+Opcodes[BRK_IMP].Cycles)
+	asrt.Equal(t, cpu.Cycle, Opcodes[LDA_ABX].Cycles)
 	asrt.False(t, cpu.Status.Has(Negative))
 	asrt.False(t, cpu.Status.Has(Zero))
 }
@@ -286,12 +286,12 @@ func TestLdaAbsoluteYNegativeValue(t *testing.T) {
 	}
 	memory[0x0A30] = 0xA5
 
-	cpu := Cpu{}
+	cpu := Cpu{StopWhen: StopOnOpcode(BRK_IMP)}
 	cpu.YIndex = 0x20
 	cpu.Run(BusEx{&memory})
 
 	asrt.Equal(t, cpu.Accumulator, Register8(0xA5))
-	asrt.Equal(t, cpu.Cycle, Opcodes[LDA_ABY].Cycles+Opcodes[BRK_IMP].Cycles)
+	asrt.Equal(t, cpu.Cycle, Opcodes[LDA_ABY].Cycles)
 	asrt.True(t, cpu.Status.Has(Negative))
 	asrt.False(t, cpu.Status.Has(Zero))
 }
@@ -305,12 +305,12 @@ func TestLdaIndirectXPositiveValue(t *testing.T) {
 
 	memory[0x0907] = 0x79
 
-	cpu := Cpu{}
+	cpu := Cpu{StopWhen: StopOnOpcode(BRK_IMP)}
 	cpu.XIndex = 0x05
 	cpu.Run(BusEx{&memory})
 
 	asrt.Equal(t, cpu.Accumulator, Register8(0x79))
-	asrt.Equal(t, cpu.Cycle, Opcodes[LDA_IDX].Cycles+Opcodes[BRK_IMP].Cycles)
+	asrt.Equal(t, cpu.Cycle, Opcodes[LDA_IDX].Cycles)
 	asrt.False(t, cpu.Status.Has(Negative))
 	asrt.False(t, cpu.Status.Has(Zero))
 }
@@ -324,12 +324,12 @@ func TestLdaIndirectXNegativeValue(t *testing.T) {
 
 	memory[0x0907] = 0xFF
 
-	cpu := Cpu{}
+	cpu := Cpu{StopWhen: StopOnOpcode(BRK_IMP)}
 	cpu.XIndex = 0x05
 	cpu.Run(BusEx{&memory})
 
 	asrt.Equal(t, cpu.Accumulator, Register8(0xFF))
-	asrt.Equal(t, cpu.Cycle, Opcodes[LDA_IDX].Cycles+Opcodes[BRK_IMP].Cycles)
+	asrt.Equal(t, cpu.Cycle, Opcodes[LDA_IDX].Cycles)
 	asrt.True(t, cpu.Status.Has(Negative))
 	asrt.False(t, cpu.Status.Has(Zero))
 }
@@ -343,12 +343,12 @@ func TestLdaIndirectXZeroValue(t *testing.T) {
 
 	memory[0x0907] = 0x00
 
-	cpu := Cpu{}
+	cpu := Cpu{StopWhen: StopOnOpcode(BRK_IMP)}
 	cpu.XIndex = 0x05
 	cpu.Run(BusEx{&memory})
 
 	asrt.Equal(t, cpu.Accumulator, Register8(0x00))
-	asrt.Equal(t, cpu.Cycle, Opcodes[LDA_IDX].Cycles+Opcodes[BRK_IMP].Cycles)
+	asrt.Equal(t, cpu.Cycle, Opcodes[LDA_IDX].Cycles)
 	asrt.False(t, cpu.Status.Has(Negative))
 	asrt.True(t, cpu.Status.Has(Zero))
 }
@@ -362,12 +362,12 @@ func TestLdaIndirectYPositiveValueNotCrossed(t *testing.T) {
 
 	memory[0x0704] = 0x55
 
-	cpu := Cpu{}
+	cpu := Cpu{StopWhen: StopOnOpcode(BRK_IMP)}
 	cpu.YIndex = 0x01
 	cpu.Run(BusEx{&memory})
 
 	asrt.Equal(t, cpu.Accumulator, Register8(0x55))
-	asrt.Equal(t, cpu.Cycle, Opcodes[LDA_IDY].Cycles+Opcodes[BRK_IMP].Cycles)
+	asrt.Equal(t, cpu.Cycle, Opcodes[LDA_IDY].Cycles)
 	asrt.False(t, cpu.Status.Has(Negative))
 	asrt.False(t, cpu.Status.Has(Zero))
 }
@@ -381,12 +381,12 @@ func TestLdaIndirectYPositiveValueCrossed(t *testing.T) {
 
 	memory[0x080f] = 0x66
 
-	cpu := Cpu{}
+	cpu := Cpu{StopWhen: StopOnOpcode(BRK_IMP)}
 	cpu.YIndex = 0x10
 	cpu.Run(BusEx{&memory})
 
 	asrt.Equal(t, cpu.Accumulator, Register8(0x66))
-	asrt.Equal(t, cpu.Cycle, Opcodes[LDA_IDY].Cycles+Opcodes[BRK_IMP].Cycles+1)
+	asrt.Equal(t, cpu.Cycle, Opcodes[LDA_IDY].Cycles+1)
 	asrt.False(t, cpu.Status.Has(Negative))
 	asrt.False(t, cpu.Status.Has(Zero))
 }
@@ -395,11 +395,11 @@ func TestLdxImmediatePositiveValue(t *testing.T) {
 	memory := Mem{
 		LDX_IMM, 0x50, BRK_IMP,
 	}
-	cpu := Cpu{}
+	cpu := Cpu{StopWhen: StopOnOpcode(BRK_IMP)}
 	cpu.Run(BusEx{&memory})
 
 	asrt.Equal(t, cpu.XIndex, Register8(0x50))
-	asrt.Equal(t, cpu.Cycle, Opcodes[LDX_IMM].Cycles+Opcodes[BRK_IMP].Cycles)
+	asrt.Equal(t, cpu.Cycle, Opcodes[LDX_IMM].Cycles)
 	asrt.False(t, cpu.Status.Has(Negative))
 	asrt.False(t, cpu.Status.Has(Zero))
 }
@@ -408,11 +408,11 @@ func TestLdxImmediateNegativeValue(t *testing.T) {
 	memory := Mem{
 		LDX_IMM, 0x81, BRK_IMP,
 	}
-	cpu := Cpu{}
+	cpu := Cpu{StopWhen: StopOnOpcode(BRK_IMP)}
 	cpu.Run(BusEx{&memory})
 
 	asrt.Equal(t, cpu.XIndex, Register8(0x81))
-	asrt.Equal(t, cpu.Cycle, Opcodes[LDX_IMM].Cycles+Opcodes[BRK_IMP].Cycles)
+	asrt.Equal(t, cpu.Cycle, Opcodes[LDX_IMM].Cycles)
 	asrt.True(t, cpu.Status.Has(Negative))
 	asrt.False(t, cpu.Status.Has(Zero))
 }
@@ -421,11 +421,11 @@ func TestLdxImmediateZeroValue(t *testing.T) {
 	memory := Mem{
 		LDX_IMM, 0x00, BRK_IMP,
 	}
-	cpu := Cpu{}
+	cpu := Cpu{StopWhen: StopOnOpcode(BRK_IMP)}
 	cpu.Run(BusEx{&memory})
 
 	asrt.Equal(t, cpu.XIndex, Register8(0x00))
-	asrt.Equal(t, cpu.Cycle, Opcodes[LDX_IMM].Cycles+Opcodes[BRK_IMP].Cycles)
+	asrt.Equal(t, cpu.Cycle, Opcodes[LDX_IMM].Cycles)
 	asrt.False(t, cpu.Status.Has(Negative))
 	asrt.True(t, cpu.Status.Has(Zero))
 }
@@ -437,11 +437,11 @@ func TestLdxZeroPagePositiveValue(t *testing.T) {
 
 	memory[0x0045] = 0x22
 
-	cpu := Cpu{}
+	cpu := Cpu{StopWhen: StopOnOpcode(BRK_IMP)}
 	cpu.Run(BusEx{&memory})
 
 	asrt.Equal(t, cpu.XIndex, Register8(0x22))
-	asrt.Equal(t, cpu.Cycle, Opcodes[LDX_ZER].Cycles+Opcodes[BRK_IMP].Cycles)
+	asrt.Equal(t, cpu.Cycle, Opcodes[LDX_ZER].Cycles)
 	asrt.False(t, cpu.Status.Has(Negative))
 	asrt.False(t, cpu.Status.Has(Zero))
 }
@@ -453,11 +453,11 @@ func TestLdxZeroPageNegativeValue(t *testing.T) {
 
 	memory[0x0045] = 0xAE
 
-	cpu := Cpu{}
+	cpu := Cpu{StopWhen: StopOnOpcode(BRK_IMP)}
 	cpu.Run(BusEx{&memory})
 
 	asrt.Equal(t, cpu.XIndex, Register8(0xAE))
-	asrt.Equal(t, cpu.Cycle, Opcodes[LDX_ZER].Cycles+Opcodes[BRK_IMP].Cycles)
+	asrt.Equal(t, cpu.Cycle, Opcodes[LDX_ZER].Cycles)
 	asrt.True(t, cpu.Status.Has(Negative))
 	asrt.False(t, cpu.Status.Has(Zero))
 }
@@ -470,11 +470,11 @@ func TestLdxZeroPageZeroValue(t *testing.T) {
 
 	memory[0x0045] = 0x00
 
-	cpu := Cpu{}
+	cpu := Cpu{StopWhen: StopOnOpcode(BRK_IMP)}
 	cpu.Run(BusEx{&memory})
 
 	asrt.Equal(t, cpu.XIndex, Register8(0x00))
-	asrt.Equal(t, cpu.Cycle, Opcodes[LDX_ZER].Cycles+Opcodes[BRK_IMP].Cycles)
+	asrt.Equal(t, cpu.Cycle, Opcodes[LDX_ZER].Cycles)
 	asrt.False(t, cpu.Status.Has(Negative))
 	asrt.True(t, cpu.Status.Has(Zero))
 }
@@ -486,12 +486,12 @@ func TestLdxZeroPageYPositiveValue(t *testing.T) {
 
 	memory[0x0060] = 0x25
 
-	cpu := Cpu{}
+	cpu := Cpu{StopWhen: StopOnOpcode(BRK_IMP)}
 	cpu.YIndex = 0x10
 	cpu.Run(BusEx{&memory})
 
 	asrt.Equal(t, cpu.XIndex, Register8(0x25))
-	asrt.Equal(t, cpu.Cycle, Opcodes[LDX_ZRY].Cycles+Opcodes[BRK_IMP].Cycles)
+	asrt.Equal(t, cpu.Cycle, Opcodes[LDX_ZRY].Cycles)
 	asrt.False(t, cpu.Status.Has(Negative))
 	asrt.False(t, cpu.Status.Has(Zero))
 }
@@ -503,12 +503,12 @@ func TestLdxZeroPageYNegativeValue(t *testing.T) {
 
 	memory[0x0060] = 0xDE
 
-	cpu := Cpu{}
+	cpu := Cpu{StopWhen: StopOnOpcode(BRK_IMP)}
 	cpu.YIndex = 0x10
 	cpu.Run(BusEx{&memory})
 
 	asrt.Equal(t, cpu.XIndex, Register8(0xDE))
-	asrt.Equal(t, cpu.Cycle, Opcodes[LDX_ZRY].Cycles+Opcodes[BRK_IMP].Cycles)
+	asrt.Equal(t, cpu.Cycle, Opcodes[LDX_ZRY].Cycles)
 	asrt.True(t, cpu.Status.Has(Negative))
 	asrt.False(t, cpu.Status.Has(Zero))
 }
@@ -520,11 +520,11 @@ func TestLdxAbsolutePositiveValue(t *testing.T) {
 
 	memory[0x2050] = 0x10
 
-	cpu := Cpu{}
+	cpu := Cpu{StopWhen: StopOnOpcode(BRK_IMP)}
 	cpu.Run(BusEx{&memory})
 
 	asrt.Equal(t, cpu.XIndex, Register8(0x10))
-	asrt.Equal(t, cpu.Cycle, Opcodes[LDX_ABS].Cycles+Opcodes[BRK_IMP].Cycles)
+	asrt.Equal(t, cpu.Cycle, Opcodes[LDX_ABS].Cycles)
 	asrt.False(t, cpu.Status.Has(Negative))
 	asrt.False(t, cpu.Status.Has(Zero))
 }
@@ -536,11 +536,11 @@ func TestLdxAbsoluteNegativeValue(t *testing.T) {
 
 	memory[0x2050] = 0xCE
 
-	cpu := Cpu{}
+	cpu := Cpu{StopWhen: StopOnOpcode(BRK_IMP)}
 	cpu.Run(BusEx{&memory})
 
 	asrt.Equal(t, cpu.XIndex, Register8(0xCE))
-	asrt.Equal(t, cpu.Cycle, Opcodes[LDX_ABS].Cycles+Opcodes[BRK_IMP].Cycles)
+	asrt.Equal(t, cpu.Cycle, Opcodes[LDX_ABS].Cycles)
 	asrt.True(t, cpu.Status.Has(Negative))
 	asrt.False(t, cpu.Status.Has(Zero))
 }
@@ -552,11 +552,11 @@ func TestLdxAbsoluteZeroValue(t *testing.T) {
 
 	memory[0x2050] = 0x00
 
-	cpu := Cpu{}
+	cpu := Cpu{StopWhen: StopOnOpcode(BRK_IMP)}
 	cpu.Run(BusEx{&memory})
 
 	asrt.Equal(t, cpu.XIndex, Register8(0x00))
-	asrt.Equal(t, cpu.Cycle, Opcodes[LDX_ABS].Cycles+Opcodes[BRK_IMP].Cycles)
+	asrt.Equal(t, cpu.Cycle, Opcodes[LDX_ABS].Cycles)
 	asrt.False(t, cpu.Status.Has(Negative))
 	asrt.True(t, cpu.Status.Has(Zero))
 }
@@ -568,12 +568,12 @@ func TestLdxAbsoluteYPositiveValue(t *testing.T) {
 
 	memory[0x2061] = 0x35
 
-	cpu := Cpu{}
+	cpu := Cpu{StopWhen: StopOnOpcode(BRK_IMP)}
 	cpu.YIndex = 0x11
 	cpu.Run(BusEx{&memory})
 
 	asrt.Equal(t, cpu.XIndex, Register8(0x35))
-	asrt.Equal(t, cpu.Cycle, Opcodes[LDX_ABY].Cycles+Opcodes[BRK_IMP].Cycles)
+	asrt.Equal(t, cpu.Cycle, Opcodes[LDX_ABY].Cycles)
 	asrt.False(t, cpu.Status.Has(Negative))
 	asrt.False(t, cpu.Status.Has(Zero))
 }
@@ -585,12 +585,12 @@ func TestLdxAbsoluteYPositiveValueCrossedPage(t *testing.T) {
 
 	memory[0x210F] = 0x35
 
-	cpu := Cpu{}
+	cpu := Cpu{StopWhen: StopOnOpcode(BRK_IMP)}
 	cpu.YIndex = 0x10
 	cpu.Run(BusEx{&memory})
 
 	asrt.Equal(t, cpu.XIndex, Register8(0x35))
-	asrt.Equal(t, cpu.Cycle, Opcodes[LDX_ABY].Cycles+Opcodes[BRK_IMP].Cycles+1)
+	asrt.Equal(t, cpu.Cycle, Opcodes[LDX_ABY].Cycles+1)
 	asrt.False(t, cpu.Status.Has(Negative))
 	asrt.False(t, cpu.Status.Has(Zero))
 }
@@ -602,12 +602,12 @@ func TestLdxAbsoluteYPositiveValueCrossedPageAndNegative(t *testing.T) {
 
 	memory[0x210F] = 0xCC
 
-	cpu := Cpu{}
+	cpu := Cpu{StopWhen: StopOnOpcode(BRK_IMP)}
 	cpu.YIndex = 0x10
 	cpu.Run(BusEx{&memory})
 
 	asrt.Equal(t, cpu.XIndex, Register8(0xCC))
-	asrt.Equal(t, cpu.Cycle, Opcodes[LDX_ABY].Cycles+Opcodes[BRK_IMP].Cycles+1)
+	asrt.Equal(t, cpu.Cycle, Opcodes[LDX_ABY].Cycles+1)
 	asrt.True(t, cpu.Status.Has(Negative))
 	asrt.False(t, cpu.Status.Has(Zero))
 }
@@ -617,11 +617,11 @@ func TestLdyImmediatePositiveValue(t *testing.T) {
 		LDY_IMM, 0x25, BRK_IMP,
 	}
 
-	cpu := Cpu{}
+	cpu := Cpu{StopWhen: StopOnOpcode(BRK_IMP)}
 	cpu.Run(BusEx{&memory})
 
 	asrt.Equal(t, cpu.YIndex, Register8(0x25))
-	asrt.Equal(t, cpu.Cycle, Opcodes[LDY_IMM].Cycles+Opcodes[BRK_IMP].Cycles)
+	asrt.Equal(t, cpu.Cycle, Opcodes[LDY_IMM].Cycles)
 	asrt.False(t, cpu.Status.Has(Negative))
 	asrt.False(t, cpu.Status.Has(Zero))
 }
@@ -631,11 +631,11 @@ func TestLdyImmediateNegativeValue(t *testing.T) {
 		LDY_IMM, 0xBE, BRK_IMP,
 	}
 
-	cpu := Cpu{}
+	cpu := Cpu{StopWhen: StopOnOpcode(BRK_IMP)}
 	cpu.Run(BusEx{&memory})
 
 	asrt.Equal(t, cpu.YIndex, Register8(0xBE))
-	asrt.Equal(t, cpu.Cycle, Opcodes[LDY_IMM].Cycles+Opcodes[BRK_IMP].Cycles)
+	asrt.Equal(t, cpu.Cycle, Opcodes[LDY_IMM].Cycles)
 	asrt.True(t, cpu.Status.Has(Negative))
 	asrt.False(t, cpu.Status.Has(Zero))
 }
@@ -645,11 +645,11 @@ func TestLdyImmediateZeroValue(t *testing.T) {
 		LDY_IMM, 0x00, BRK_IMP,
 	}
 
-	cpu := Cpu{}
+	cpu := Cpu{StopWhen: StopOnOpcode(BRK_IMP)}
 	cpu.Run(BusEx{&memory})
 
 	asrt.Equal(t, cpu.YIndex, Register8(0x00))
-	asrt.Equal(t, cpu.Cycle, Opcodes[LDY_IMM].Cycles+Opcodes[BRK_IMP].Cycles)
+	asrt.Equal(t, cpu.Cycle, Opcodes[LDY_IMM].Cycles)
 	asrt.False(t, cpu.Status.Has(Negative))
 	asrt.True(t, cpu.Status.Has(Zero))
 }
@@ -661,11 +661,11 @@ func TestLdyZeroPagePositiveValue(t *testing.T) {
 
 	memory[0x0045] = 0x63
 
-	cpu := Cpu{}
+	cpu := Cpu{StopWhen: StopOnOpcode(BRK_IMP)}
 	cpu.Run(BusEx{&memory})
 
 	asrt.Equal(t, cpu.YIndex, Register8(0x63))
-	asrt.Equal(t, cpu.Cycle, Opcodes[LDY_ZER].Cycles+Opcodes[BRK_IMP].Cycles)
+	asrt.Equal(t, cpu.Cycle, Opcodes[LDY_ZER].Cycles)
 	asrt.False(t, cpu.Status.Has(Negative))
 	asrt.False(t, cpu.Status.Has(Zero))
 }
@@ -677,11 +677,11 @@ func TestLdyZeroPageZeroValue(t *testing.T) {
 
 	memory[0x0045] = 0x00
 
-	cpu := Cpu{}
+	cpu := Cpu{StopWhen: StopOnOpcode(BRK_IMP)}
 	cpu.Run(BusEx{&memory})
 
 	asrt.Equal(t, cpu.YIndex, Register8(0x00))
-	asrt.Equal(t, cpu.Cycle, Opcodes[LDY_ZER].Cycles+Opcodes[BRK_IMP].Cycles)
+	asrt.Equal(t, cpu.Cycle, Opcodes[LDY_ZER].Cycles)
 	asrt.False(t, cpu.Status.Has(Negative))
 	asrt.True(t, cpu.Status.Has(Zero))
 }
@@ -693,12 +693,12 @@ func TestLdyZeroPageXPositiveValue(t *testing.T) {
 
 	memory[0x0055] = 0x22
 
-	cpu := Cpu{}
+	cpu := Cpu{StopWhen: StopOnOpcode(BRK_IMP)}
 	cpu.XIndex = 0x10
 	cpu.Run(BusEx{&memory})
 
 	asrt.Equal(t, cpu.YIndex, Register8(0x22))
-	asrt.Equal(t, cpu.Cycle, Opcodes[LDY_ZRX].Cycles+Opcodes[BRK_IMP].Cycles)
+	asrt.Equal(t, cpu.Cycle, Opcodes[LDY_ZRX].Cycles)
 	asrt.False(t, cpu.Status.Has(Negative))
 	asrt.False(t, cpu.Status.Has(Zero))
 }
@@ -710,12 +710,12 @@ func TestLdyZeroPageXNegativeValue(t *testing.T) {
 
 	memory[0x0055] = 0xF3
 
-	cpu := Cpu{}
+	cpu := Cpu{StopWhen: StopOnOpcode(BRK_IMP)}
 	cpu.XIndex = 0x10
 	cpu.Run(BusEx{&memory})
 
 	asrt.Equal(t, cpu.YIndex, Register8(0xF3))
-	asrt.Equal(t, cpu.Cycle, Opcodes[LDY_ZRX].Cycles+Opcodes[BRK_IMP].Cycles)
+	asrt.Equal(t, cpu.Cycle, Opcodes[LDY_ZRX].Cycles)
 	asrt.True(t, cpu.Status.Has(Negative))
 	asrt.False(t, cpu.Status.Has(Zero))
 }
@@ -727,11 +727,11 @@ func TestLdyAbsolutePositiveValue(t *testing.T) {
 
 	memory[0x2fa5] = 0x78
 
-	cpu := Cpu{}
+	cpu := Cpu{StopWhen: StopOnOpcode(BRK_IMP)}
 	cpu.Run(BusEx{&memory})
 
 	asrt.Equal(t, cpu.YIndex, Register8(0x78))
-	asrt.Equal(t, cpu.Cycle, Opcodes[LDY_ABS].Cycles+Opcodes[BRK_IMP].Cycles)
+	asrt.Equal(t, cpu.Cycle, Opcodes[LDY_ABS].Cycles)
 	asrt.False(t, cpu.Status.Has(Negative))
 	asrt.False(t, cpu.Status.Has(Zero))
 }
@@ -743,11 +743,11 @@ func TestLdyAbsoluteNegativeValue(t *testing.T) {
 
 	memory[0x2fa5] = 0xaf
 
-	cpu := Cpu{}
+	cpu := Cpu{StopWhen: StopOnOpcode(BRK_IMP)}
 	cpu.Run(BusEx{&memory})
 
 	asrt.Equal(t, cpu.YIndex, Register8(0xaf))
-	asrt.Equal(t, cpu.Cycle, Opcodes[LDY_ABS].Cycles+Opcodes[BRK_IMP].Cycles)
+	asrt.Equal(t, cpu.Cycle, Opcodes[LDY_ABS].Cycles)
 	asrt.True(t, cpu.Status.Has(Negative))
 	asrt.False(t, cpu.Status.Has(Zero))
 }
@@ -759,11 +759,11 @@ func TestLdyAbsoluteZeroValue(t *testing.T) {
 
 	memory[0x2fa5] = 0x00
 
-	cpu := Cpu{}
+	cpu := Cpu{StopWhen: StopOnOpcode(BRK_IMP)}
 	cpu.Run(BusEx{&memory})
 
 	asrt.Equal(t, cpu.YIndex, Register8(0x00))
-	asrt.Equal(t, cpu.Cycle, Opcodes[LDY_ABS].Cycles+Opcodes[BRK_IMP].Cycles)
+	asrt.Equal(t, cpu.Cycle, Opcodes[LDY_ABS].Cycles)
 	asrt.False(t, cpu.Status.Has(Negative))
 	asrt.True(t, cpu.Status.Has(Zero))
 }
@@ -775,12 +775,12 @@ func TestLdyAbsoluteXPositiveValueNotCrossed(t *testing.T) {
 
 	memory[0x2f70] = 0x78
 
-	cpu := Cpu{}
+	cpu := Cpu{StopWhen: StopOnOpcode(BRK_IMP)}
 	cpu.XIndex = 0x20
 	cpu.Run(BusEx{&memory})
 
 	asrt.Equal(t, cpu.YIndex, Register8(0x78))
-	asrt.Equal(t, cpu.Cycle, Opcodes[LDY_ABX].Cycles+Opcodes[BRK_IMP].Cycles)
+	asrt.Equal(t, cpu.Cycle, Opcodes[LDY_ABX].Cycles)
 	asrt.False(t, cpu.Status.Has(Negative))
 	asrt.False(t, cpu.Status.Has(Zero))
 }
@@ -792,12 +792,12 @@ func TestLdyAbsoluteXPositiveValueCrossed(t *testing.T) {
 
 	memory[0x300f] = 0x78
 
-	cpu := Cpu{}
+	cpu := Cpu{StopWhen: StopOnOpcode(BRK_IMP)}
 	cpu.XIndex = 0x10
 	cpu.Run(BusEx{&memory})
 
 	asrt.Equal(t, cpu.YIndex, Register8(0x78))
-	asrt.Equal(t, cpu.Cycle, Opcodes[LDY_ABX].Cycles+Opcodes[BRK_IMP].Cycles+1)
+	asrt.Equal(t, cpu.Cycle, Opcodes[LDY_ABX].Cycles+1)
 	asrt.False(t, cpu.Status.Has(Negative))
 	asrt.False(t, cpu.Status.Has(Zero))
 }
@@ -809,12 +809,12 @@ func TestLdyAbsoluteXPositiveValueCrossedAndZero(t *testing.T) {
 
 	memory[0x300f] = 0x00
 
-	cpu := Cpu{}
+	cpu := Cpu{StopWhen: StopOnOpcode(BRK_IMP)}
 	cpu.XIndex = 0x10
 	cpu.Run(BusEx{&memory})
 
 	asrt.Equal(t, cpu.YIndex, Register8(0x00))
-	asrt.Equal(t, cpu.Cycle, Opcodes[LDY_ABX].Cycles+Opcodes[BRK_IMP].Cycles+1)
+	asrt.Equal(t, cpu.Cycle, Opcodes[LDY_ABX].Cycles+1)
 	asrt.False(t, cpu.Status.Has(Negative))
 	asrt.True(t, cpu.Status.Has(Zero))
 }
@@ -824,12 +824,12 @@ func TestStaZeroPage(t *testing.T) {
 		STA_ZER, 0x50, BRK_IMP,
 	}
 
-	cpu := Cpu{}
+	cpu := Cpu{StopWhen: StopOnOpcode(BRK_IMP)}
 	cpu.Accumulator = 0xAA
 	cpu.Run(BusEx{&memory})
 
 	asrt.Equal(t, cpu.Accumulator, Register8(memory[0x0050]))
-	asrt.Equal(t, cpu.Cycle, Opcodes[STA_ZER].Cycles+Opcodes[BRK_IMP].Cycles)
+	asrt.Equal(t, cpu.Cycle, Opcodes[STA_ZER].Cycles)
 }
 
 func TestStaZeroPageX(t *testing.T) {
@@ -837,13 +837,13 @@ func TestStaZeroPageX(t *testing.T) {
 		STA_ZRX, 0x50, BRK_IMP,
 	}
 
-	cpu := Cpu{}
+	cpu := Cpu{StopWhen: StopOnOpcode(BRK_IMP)}
 	cpu.XIndex = 0x20
 	cpu.Accumulator = 0xFE
 	cpu.Run(BusEx{&memory})
 
 	asrt.Equal(t, cpu.Accumulator, Register8(memory[0x0070]))
-	asrt.Equal(t, cpu.Cycle, Opcodes[STA_ZRX].Cycles+Opcodes[BRK_IMP].Cycles)
+	asrt.Equal(t, cpu.Cycle, Opcodes[STA_ZRX].Cycles)
 }
 
 func TestStaAbsolute(t *testing.T) {
@@ -851,12 +851,12 @@ func TestStaAbsolute(t *testing.T) {
 		STA_ABS, 0x50, 0xFA, BRK_IMP,
 	}
 
-	cpu := Cpu{}
+	cpu := Cpu{StopWhen: StopOnOpcode(BRK_IMP)}
 	cpu.Accumulator = 0xBC
 	cpu.Run(BusEx{&memory})
 
 	asrt.Equal(t, cpu.Accumulator, Register8(memory[0xFA50]))
-	asrt.Equal(t, cpu.Cycle, Opcodes[STA_ABS].Cycles+Opcodes[BRK_IMP].Cycles)
+	asrt.Equal(t, cpu.Cycle, Opcodes[STA_ABS].Cycles)
 }
 
 func TestStaAbsoluteX(t *testing.T) {
@@ -864,13 +864,13 @@ func TestStaAbsoluteX(t *testing.T) {
 		STA_ABX, 0x50, 0xFA, BRK_IMP,
 	}
 
-	cpu := Cpu{}
+	cpu := Cpu{StopWhen: StopOnOpcode(BRK_IMP)}
 	cpu.Accumulator = 0xBC
 	cpu.XIndex = 0x30
 	cpu.Run(BusEx{&memory})
 
 	asrt.Equal(t, cpu.Accumulator, Register8(memory[0xFA80]))
-	asrt.Equal(t, cpu.Cycle, Opcodes[STA_ABX].Cycles+Opcodes[BRK_IMP].Cycles)
+	asrt.Equal(t, cpu.Cycle, Opcodes[STA_ABX].Cycles)
 }
 
 func TestStaAbsoluteY(t *testing.T) {
@@ -878,13 +878,13 @@ func TestStaAbsoluteY(t *testing.T) {
 		STA_ABY, 0x50, 0xFA, BRK_IMP,
 	}
 
-	cpu := Cpu{}
+	cpu := Cpu{StopWhen: StopOnOpcode(BRK_IMP)}
 	cpu.Accumulator = 0xBC
 	cpu.YIndex = 0x30
 	cpu.Run(BusEx{&memory})
 
 	asrt.Equal(t, cpu.Accumulator, Register8(memory[0xFA80]))
-	asrt.Equal(t, cpu.Cycle, Opcodes[STA_ABY].Cycles+Opcodes[BRK_IMP].Cycles)
+	asrt.Equal(t, cpu.Cycle, Opcodes[STA_ABY].Cycles)
 }
 
 func TestStaIndirectX(t *testing.T) {
@@ -895,13 +895,13 @@ func TestStaIndirectX(t *testing.T) {
 	memory[0x0025] = 0x33
 	memory[0x0026] = 0xA7
 
-	cpu := Cpu{}
+	cpu := Cpu{StopWhen: StopOnOpcode(BRK_IMP)}
 	cpu.Accumulator = 0xBC
 	cpu.XIndex = 0x05
 	cpu.Run(BusEx{&memory})
 
 	asrt.Equal(t, cpu.Accumulator, Register8(memory[0xA733]))
-	asrt.Equal(t, cpu.Cycle, Opcodes[STA_IDX].Cycles+Opcodes[BRK_IMP].Cycles)
+	asrt.Equal(t, cpu.Cycle, Opcodes[STA_IDX].Cycles)
 }
 
 func TestStaIndirectY(t *testing.T) {
@@ -912,13 +912,13 @@ func TestStaIndirectY(t *testing.T) {
 	memory[0x0020] = 0x33
 	memory[0x0021] = 0xA7
 
-	cpu := Cpu{}
+	cpu := Cpu{StopWhen: StopOnOpcode(BRK_IMP)}
 	cpu.Accumulator = 0xBC
 	cpu.YIndex = 0x05
 	cpu.Run(BusEx{&memory})
 
 	asrt.Equal(t, cpu.Accumulator, Register8(memory[0xA738]))
-	asrt.Equal(t, cpu.Cycle, Opcodes[STA_IDY].Cycles+Opcodes[BRK_IMP].Cycles)
+	asrt.Equal(t, cpu.Cycle, Opcodes[STA_IDY].Cycles)
 }
 
 func TestStxZeroPage(t *testing.T) {
@@ -926,12 +926,12 @@ func TestStxZeroPage(t *testing.T) {
 		STX_ZER, 0x50, BRK_IMP,
 	}
 
-	cpu := Cpu{}
+	cpu := Cpu{StopWhen: StopOnOpcode(BRK_IMP)}
 	cpu.XIndex = 0xAA
 	cpu.Run(BusEx{&memory})
 
 	asrt.Equal(t, cpu.XIndex, Register8(memory[0x50]))
-	asrt.Equal(t, cpu.Cycle, Opcodes[STX_ZER].Cycles+Opcodes[BRK_IMP].Cycles)
+	asrt.Equal(t, cpu.Cycle, Opcodes[STX_ZER].Cycles)
 }
 
 func TestStxZeroPageY(t *testing.T) {
@@ -939,13 +939,13 @@ func TestStxZeroPageY(t *testing.T) {
 		STX_ZRY, 0x50, BRK_IMP,
 	}
 
-	cpu := Cpu{}
+	cpu := Cpu{StopWhen: StopOnOpcode(BRK_IMP)}
 	cpu.XIndex = 0x20
 	cpu.YIndex = 0x10
 	cpu.Run(BusEx{&memory})
 
 	asrt.Equal(t, cpu.XIndex, Register8(memory[0x060]))
-	asrt.Equal(t, cpu.Cycle, Opcodes[STX_ZRY].Cycles+Opcodes[BRK_IMP].Cycles)
+	asrt.Equal(t, cpu.Cycle, Opcodes[STX_ZRY].Cycles)
 }
 
 func TestStxAbsolute(t *testing.T) {
@@ -953,12 +953,12 @@ func TestStxAbsolute(t *testing.T) {
 		STX_ABS, 0x50, 0xFA, BRK_IMP,
 	}
 
-	cpu := Cpu{}
+	cpu := Cpu{StopWhen: StopOnOpcode(BRK_IMP)}
 	cpu.XIndex = 0xBC
 	cpu.Run(BusEx{&memory})
 
 	asrt.Equal(t, cpu.XIndex, Register8(memory[0xFA50]))
-	asrt.Equal(t, cpu.Cycle, Opcodes[STA_ABS].Cycles+Opcodes[BRK_IMP].Cycles)
+	asrt.Equal(t, cpu.Cycle, Opcodes[STA_ABS].Cycles)
 }
 
 func TestStyZeroPage(t *testing.T) {
@@ -966,12 +966,12 @@ func TestStyZeroPage(t *testing.T) {
 		STY_ZER, 0x50, BRK_IMP,
 	}
 
-	cpu := Cpu{}
+	cpu := Cpu{StopWhen: StopOnOpcode(BRK_IMP)}
 	cpu.YIndex = 0xAA
 	cpu.Run(BusEx{&memory})
 
 	asrt.Equal(t, cpu.YIndex, Register8(memory[0x50]))
-	asrt.Equal(t, cpu.Cycle, Opcodes[STY_ZER].Cycles+Opcodes[BRK_IMP].Cycles)
+	asrt.Equal(t, cpu.Cycle, Opcodes[STY_ZER].Cycles)
 }
 
 func TestStyZeroPageX(t *testing.T) {
@@ -979,13 +979,13 @@ func TestStyZeroPageX(t *testing.T) {
 		STY_ZRX, 0x50, BRK_IMP,
 	}
 
-	cpu := Cpu{}
+	cpu := Cpu{StopWhen: StopOnOpcode(BRK_IMP)}
 	cpu.YIndex = 0x20
 	cpu.XIndex = 0x10
 	cpu.Run(BusEx{&memory})
 
 	asrt.Equal(t, cpu.YIndex, Register8(memory[0x060]))
-	asrt.Equal(t, cpu.Cycle, Opcodes[STY_ZRX].Cycles+Opcodes[BRK_IMP].Cycles)
+	asrt.Equal(t, cpu.Cycle, Opcodes[STY_ZRX].Cycles)
 }
 
 func TestStyAbsolute(t *testing.T) {
@@ -993,12 +993,12 @@ func TestStyAbsolute(t *testing.T) {
 		STY_ABS, 0x50, 0xFA, BRK_IMP,
 	}
 
-	cpu := Cpu{}
+	cpu := Cpu{StopWhen: StopOnOpcode(BRK_IMP)}
 	cpu.YIndex = 0xBC
 	cpu.Run(BusEx{&memory})
 
 	asrt.Equal(t, cpu.YIndex, Register8(memory[0xFA50]))
-	asrt.Equal(t, cpu.Cycle, Opcodes[STY_ABS].Cycles+Opcodes[BRK_IMP].Cycles)
+	asrt.Equal(t, cpu.Cycle, Opcodes[STY_ABS].Cycles)
 }
 
 func TestTaxPositiveValue(t *testing.T) {
@@ -1006,7 +1006,7 @@ func TestTaxPositiveValue(t *testing.T) {
 		TAX_IMP, BRK_IMP,
 	}
 
-	cpu := Cpu{}
+	cpu := Cpu{StopWhen: StopOnOpcode(BRK_IMP)}
 	cpu.Accumulator = 0x10
 	cpu.XIndex = 0xFE
 	cpu.Run(BusEx{&memory})
@@ -1014,7 +1014,7 @@ func TestTaxPositiveValue(t *testing.T) {
 	asrt.Equal(t, cpu.Accumulator, cpu.XIndex)
 	asrt.False(t, cpu.Status.Has(Negative))
 	asrt.False(t, cpu.Status.Has(Zero))
-	asrt.Equal(t, cpu.Cycle, Opcodes[TAX_IMP].Cycles+Opcodes[BRK_IMP].Cycles)
+	asrt.Equal(t, cpu.Cycle, Opcodes[TAX_IMP].Cycles)
 }
 
 func TestTaxNegativeValue(t *testing.T) {
@@ -1022,7 +1022,7 @@ func TestTaxNegativeValue(t *testing.T) {
 		TAX_IMP, BRK_IMP,
 	}
 
-	cpu := Cpu{}
+	cpu := Cpu{StopWhen: StopOnOpcode(BRK_IMP)}
 	cpu.Accumulator = 0xCE
 	cpu.XIndex = 0xFE
 	cpu.Run(BusEx{&memory})
@@ -1030,7 +1030,7 @@ func TestTaxNegativeValue(t *testing.T) {
 	asrt.Equal(t, cpu.Accumulator, cpu.XIndex)
 	asrt.True(t, cpu.Status.Has(Negative))
 	asrt.False(t, cpu.Status.Has(Zero))
-	asrt.Equal(t, cpu.Cycle, Opcodes[TAX_IMP].Cycles+Opcodes[BRK_IMP].Cycles)
+	asrt.Equal(t, cpu.Cycle, Opcodes[TAX_IMP].Cycles)
 }
 
 func TestTaxZeroValue(t *testing.T) {
@@ -1038,7 +1038,7 @@ func TestTaxZeroValue(t *testing.T) {
 		TAX_IMP, BRK_IMP,
 	}
 
-	cpu := Cpu{}
+	cpu := Cpu{StopWhen: StopOnOpcode(BRK_IMP)}
 	cpu.Accumulator = 0x00
 	cpu.XIndex = 0xFE
 	cpu.Run(BusEx{&memory})
@@ -1053,7 +1053,7 @@ func TestTayPositiveValue(t *testing.T) {
 		TAY_IMP, BRK_IMP,
 	}
 
-	cpu := Cpu{}
+	cpu := Cpu{StopWhen: StopOnOpcode(BRK_IMP)}
 	cpu.Accumulator = 0x10
 	cpu.YIndex = 0xFE
 	cpu.Run(BusEx{&memory})
@@ -1061,7 +1061,7 @@ func TestTayPositiveValue(t *testing.T) {
 	asrt.Equal(t, cpu.Accumulator, cpu.YIndex)
 	asrt.False(t, cpu.Status.Has(Negative))
 	asrt.False(t, cpu.Status.Has(Zero))
-	asrt.Equal(t, cpu.Cycle, Opcodes[TAY_IMP].Cycles+Opcodes[BRK_IMP].Cycles)
+	asrt.Equal(t, cpu.Cycle, Opcodes[TAY_IMP].Cycles)
 }
 
 func TestTayNegativeValue(t *testing.T) {
@@ -1069,7 +1069,7 @@ func TestTayNegativeValue(t *testing.T) {
 		TAY_IMP, BRK_IMP,
 	}
 
-	cpu := Cpu{}
+	cpu := Cpu{StopWhen: StopOnOpcode(BRK_IMP)}
 	cpu.Accumulator = 0xCE
 	cpu.YIndex = 0xFE
 	cpu.Run(BusEx{&memory})
@@ -1077,7 +1077,7 @@ func TestTayNegativeValue(t *testing.T) {
 	asrt.Equal(t, cpu.Accumulator, cpu.YIndex)
 	asrt.True(t, cpu.Status.Has(Negative))
 	asrt.False(t, cpu.Status.Has(Zero))
-	asrt.Equal(t, cpu.Cycle, Opcodes[TAY_IMP].Cycles+Opcodes[BRK_IMP].Cycles)
+	asrt.Equal(t, cpu.Cycle, Opcodes[TAY_IMP].Cycles)
 }
 
 func TestTayZeroValue(t *testing.T) {
@@ -1085,7 +1085,7 @@ func TestTayZeroValue(t *testing.T) {
 		TAY_IMP, BRK_IMP,
 	}
 
-	cpu := Cpu{}
+	cpu := Cpu{StopWhen: StopOnOpcode(BRK_IMP)}
 	cpu.Accumulator = 0x00
 	cpu.YIndex = 0xFE
 	cpu.Run(BusEx{&memory})
@@ -1100,7 +1100,7 @@ func TestTxaPositiveValue(t *testing.T) {
 		TXA_IMP, BRK_IMP,
 	}
 
-	cpu := Cpu{}
+	cpu := Cpu{StopWhen: StopOnOpcode(BRK_IMP)}
 	cpu.XIndex = 0x10
 	cpu.Accumulator = 0xFE
 	cpu.Run(BusEx{&memory})
@@ -1108,7 +1108,7 @@ func TestTxaPositiveValue(t *testing.T) {
 	asrt.Equal(t, cpu.Accumulator, cpu.XIndex)
 	asrt.False(t, cpu.Status.Has(Negative))
 	asrt.False(t, cpu.Status.Has(Zero))
-	asrt.Equal(t, cpu.Cycle, Opcodes[TXA_IMP].Cycles+Opcodes[BRK_IMP].Cycles)
+	asrt.Equal(t, cpu.Cycle, Opcodes[TXA_IMP].Cycles)
 }
 
 func TestTxaNegativeValue(t *testing.T) {
@@ -1116,7 +1116,7 @@ func TestTxaNegativeValue(t *testing.T) {
 		TXA_IMP, BRK_IMP,
 	}
 
-	cpu := Cpu{}
+	cpu := Cpu{StopWhen: StopOnOpcode(BRK_IMP)}
 	cpu.XIndex = 0xCE
 	cpu.Accumulator = 0xFE
 	cpu.Run(BusEx{&memory})
@@ -1124,7 +1124,7 @@ func TestTxaNegativeValue(t *testing.T) {
 	asrt.Equal(t, cpu.Accumulator, cpu.XIndex)
 	asrt.True(t, cpu.Status.Has(Negative))
 	asrt.False(t, cpu.Status.Has(Zero))
-	asrt.Equal(t, cpu.Cycle, Opcodes[TXA_IMP].Cycles+Opcodes[BRK_IMP].Cycles)
+	asrt.Equal(t, cpu.Cycle, Opcodes[TXA_IMP].Cycles)
 }
 
 func TestTxaZeroValue(t *testing.T) {
@@ -1132,7 +1132,7 @@ func TestTxaZeroValue(t *testing.T) {
 		TXA_IMP, BRK_IMP,
 	}
 
-	cpu := Cpu{}
+	cpu := Cpu{StopWhen: StopOnOpcode(BRK_IMP)}
 	cpu.XIndex = 0x00
 	cpu.Accumulator = 0xFE
 	cpu.Run(BusEx{&memory})
@@ -1147,7 +1147,7 @@ func TestTyaPositiveValue(t *testing.T) {
 		TYA_IMP, BRK_IMP,
 	}
 
-	cpu := Cpu{}
+	cpu := Cpu{StopWhen: StopOnOpcode(BRK_IMP)}
 	cpu.YIndex = 0x10
 	cpu.Accumulator = 0xFE
 	cpu.Run(BusEx{&memory})
@@ -1155,7 +1155,7 @@ func TestTyaPositiveValue(t *testing.T) {
 	asrt.Equal(t, cpu.Accumulator, cpu.YIndex)
 	asrt.False(t, cpu.Status.Has(Negative))
 	asrt.False(t, cpu.Status.Has(Zero))
-	asrt.Equal(t, cpu.Cycle, Opcodes[TYA_IMP].Cycles+Opcodes[BRK_IMP].Cycles)
+	asrt.Equal(t, cpu.Cycle, Opcodes[TYA_IMP].Cycles)
 }
 
 func TestTyaNegativeValue(t *testing.T) {
@@ -1163,7 +1163,7 @@ func TestTyaNegativeValue(t *testing.T) {
 		TYA_IMP, BRK_IMP,
 	}
 
-	cpu := Cpu{}
+	cpu := Cpu{StopWhen: StopOnOpcode(BRK_IMP)}
 	cpu.YIndex = 0xCE
 	cpu.Accumulator = 0xFE
 	cpu.Run(BusEx{&memory})
@@ -1171,7 +1171,7 @@ func TestTyaNegativeValue(t *testing.T) {
 	asrt.Equal(t, cpu.Accumulator, cpu.YIndex)
 	asrt.True(t, cpu.Status.Has(Negative))
 	asrt.False(t, cpu.Status.Has(Zero))
-	asrt.Equal(t, cpu.Cycle, Opcodes[TYA_IMP].Cycles+Opcodes[BRK_IMP].Cycles)
+	asrt.Equal(t, cpu.Cycle, Opcodes[TYA_IMP].Cycles)
 }
 
 func TestTyaZeroValue(t *testing.T) {
@@ -1179,7 +1179,7 @@ func TestTyaZeroValue(t *testing.T) {
 		TYA_IMP, BRK_IMP,
 	}
 
-	cpu := Cpu{}
+	cpu := Cpu{StopWhen: StopOnOpcode(BRK_IMP)}
 	cpu.YIndex = 0x00
 	cpu.Accumulator = 0xFE
 	cpu.Run(BusEx{&memory})
@@ -1195,13 +1195,13 @@ func TestIncPositiveValue(t *testing.T) {
 	}
 
 	memory[0x10] = 0x20
-	cpu := Cpu{}
+	cpu := Cpu{StopWhen: StopOnOpcode(BRK_IMP)}
 	cpu.Run(BusEx{&memory})
 
 	asrt.Equal(t, memory[0x10], uint8(0x21))
 	asrt.False(t, cpu.Status.Has(Negative))
 	asrt.False(t, cpu.Status.Has(Zero))
-	asrt.Equal(t, cpu.Cycle, Opcodes[INC_ZER].Cycles+Opcodes[BRK_IMP].Cycles)
+	asrt.Equal(t, cpu.Cycle, Opcodes[INC_ZER].Cycles)
 }
 
 func TestIncNegativeValue(t *testing.T) {
@@ -1210,13 +1210,13 @@ func TestIncNegativeValue(t *testing.T) {
 	}
 
 	memory[0x10] = 0x7F
-	cpu := Cpu{}
+	cpu := Cpu{StopWhen: StopOnOpcode(BRK_IMP)}
 	cpu.Run(BusEx{&memory})
 
 	asrt.Equal(t, memory[0x10], uint8(0x80))
 	asrt.True(t, cpu.Status.Has(Negative))
 	asrt.False(t, cpu.Status.Has(Zero))
-	asrt.Equal(t, cpu.Cycle, Opcodes[INC_ZER].Cycles+Opcodes[BRK_IMP].Cycles)
+	asrt.Equal(t, cpu.Cycle, Opcodes[INC_ZER].Cycles)
 }
 
 func TestIncZeroValue(t *testing.T) {
@@ -1225,7 +1225,7 @@ func TestIncZeroValue(t *testing.T) {
 	}
 
 	memory[0x10] = 0xFF
-	cpu := Cpu{}
+	cpu := Cpu{StopWhen: StopOnOpcode(BRK_IMP)}
 	cpu.Run(BusEx{&memory})
 
 	asrt.Equal(t, memory[0x10], uint8(0x00))
@@ -1240,14 +1240,14 @@ func TestIncZeroPageXPositiveValue(t *testing.T) {
 	}
 
 	memory[0x30] = 0x20
-	cpu := Cpu{}
+	cpu := Cpu{StopWhen: StopOnOpcode(BRK_IMP)}
 	cpu.XIndex = 0x20
 	cpu.Run(BusEx{&memory})
 
 	asrt.Equal(t, memory[0x30], uint8(0x21))
 	asrt.False(t, cpu.Status.Has(Negative))
 	asrt.False(t, cpu.Status.Has(Zero))
-	asrt.Equal(t, cpu.Cycle, Opcodes[INC_ZRX].Cycles+Opcodes[BRK_IMP].Cycles)
+	asrt.Equal(t, cpu.Cycle, Opcodes[INC_ZRX].Cycles)
 }
 
 func TestIncZeroPageXNegativeValue(t *testing.T) {
@@ -1256,14 +1256,14 @@ func TestIncZeroPageXNegativeValue(t *testing.T) {
 	}
 
 	memory[0x30] = 0x7F
-	cpu := Cpu{}
+	cpu := Cpu{StopWhen: StopOnOpcode(BRK_IMP)}
 	cpu.XIndex = 0x20
 	cpu.Run(BusEx{&memory})
 
 	asrt.Equal(t, memory[0x30], uint8(0x80))
 	asrt.True(t, cpu.Status.Has(Negative))
 	asrt.False(t, cpu.Status.Has(Zero))
-	asrt.Equal(t, cpu.Cycle, Opcodes[INC_ZRX].Cycles+Opcodes[BRK_IMP].Cycles)
+	asrt.Equal(t, cpu.Cycle, Opcodes[INC_ZRX].Cycles)
 }
 
 func TestIncZeroPageXZeroValue(t *testing.T) {
@@ -1272,7 +1272,7 @@ func TestIncZeroPageXZeroValue(t *testing.T) {
 	}
 
 	memory[0x30] = 0xFF
-	cpu := Cpu{}
+	cpu := Cpu{StopWhen: StopOnOpcode(BRK_IMP)}
 	cpu.XIndex = 0x20
 	cpu.Run(BusEx{&memory})
 
@@ -1288,13 +1288,13 @@ func TestIncAbsolutePositiveValue(t *testing.T) {
 	}
 
 	memory[0xAE10] = 0x20
-	cpu := Cpu{}
+	cpu := Cpu{StopWhen: StopOnOpcode(BRK_IMP)}
 	cpu.Run(BusEx{&memory})
 
 	asrt.Equal(t, memory[0xAE10], uint8(0x21))
 	asrt.False(t, cpu.Status.Has(Negative))
 	asrt.False(t, cpu.Status.Has(Zero))
-	asrt.Equal(t, cpu.Cycle, Opcodes[INC_ABS].Cycles+Opcodes[BRK_IMP].Cycles)
+	asrt.Equal(t, cpu.Cycle, Opcodes[INC_ABS].Cycles)
 }
 
 func TestIncAbsoluteNegativeValue(t *testing.T) {
@@ -1303,13 +1303,13 @@ func TestIncAbsoluteNegativeValue(t *testing.T) {
 	}
 
 	memory[0xAE10] = 0x7F
-	cpu := Cpu{}
+	cpu := Cpu{StopWhen: StopOnOpcode(BRK_IMP)}
 	cpu.Run(BusEx{&memory})
 
 	asrt.Equal(t, memory[0xAE10], uint8(0x80))
 	asrt.True(t, cpu.Status.Has(Negative))
 	asrt.False(t, cpu.Status.Has(Zero))
-	asrt.Equal(t, cpu.Cycle, Opcodes[INC_ABS].Cycles+Opcodes[BRK_IMP].Cycles)
+	asrt.Equal(t, cpu.Cycle, Opcodes[INC_ABS].Cycles)
 }
 
 func TestIncAbsoluteZeroValue(t *testing.T) {
@@ -1318,7 +1318,7 @@ func TestIncAbsoluteZeroValue(t *testing.T) {
 	}
 
 	memory[0xAE10] = 0xFF
-	cpu := Cpu{}
+	cpu := Cpu{StopWhen: StopOnOpcode(BRK_IMP)}
 	cpu.Run(BusEx{&memory})
 
 	asrt.Equal(t, memory[0xAE10], uint8(0x00))
@@ -1333,14 +1333,14 @@ func TestIncAbsoluteXPositiveValue(t *testing.T) {
 	}
 
 	memory[0xAE20] = 0x03
-	cpu := Cpu{}
+	cpu := Cpu{StopWhen: StopOnOpcode(BRK_IMP)}
 	cpu.XIndex = 0x10
 	cpu.Run(BusEx{&memory})
 
 	asrt.Equal(t, memory[0xAE20], uint8(0x04))
 	asrt.False(t, cpu.Status.Has(Negative))
 	asrt.False(t, cpu.Status.Has(Zero))
-	asrt.Equal(t, cpu.Cycle, Opcodes[INC_ABX].Cycles+Opcodes[BRK_IMP].Cycles)
+	asrt.Equal(t, cpu.Cycle, Opcodes[INC_ABX].Cycles)
 }
 
 func TestIncAbsoluteXNegativeValue(t *testing.T) {
@@ -1349,14 +1349,14 @@ func TestIncAbsoluteXNegativeValue(t *testing.T) {
 	}
 
 	memory[0xAE20] = 0x7F
-	cpu := Cpu{}
+	cpu := Cpu{StopWhen: StopOnOpcode(BRK_IMP)}
 	cpu.XIndex = 0x10
 	cpu.Run(BusEx{&memory})
 
 	asrt.Equal(t, memory[0xAE20], uint8(0x80))
 	asrt.True(t, cpu.Status.Has(Negative))
 	asrt.False(t, cpu.Status.Has(Zero))
-	asrt.Equal(t, cpu.Cycle, Opcodes[INC_ABX].Cycles+Opcodes[BRK_IMP].Cycles)
+	asrt.Equal(t, cpu.Cycle, Opcodes[INC_ABX].Cycles)
 }
 
 func TestIncAbsoluteXZeroValue(t *testing.T) {
@@ -1365,7 +1365,7 @@ func TestIncAbsoluteXZeroValue(t *testing.T) {
 	}
 
 	memory[0xAE20] = 0xFF
-	cpu := Cpu{}
+	cpu := Cpu{StopWhen: StopOnOpcode(BRK_IMP)}
 	cpu.XIndex = 0x10
 	cpu.Run(BusEx{&memory})
 
@@ -1380,14 +1380,14 @@ func TestInxImpliedPositiveValue(t *testing.T) {
 		INX_IMP, BRK_IMP,
 	}
 
-	cpu := Cpu{}
+	cpu := Cpu{StopWhen: StopOnOpcode(BRK_IMP)}
 	cpu.XIndex = 0x12
 	cpu.Run(BusEx{&memory})
 
 	asrt.Equal(t, cpu.XIndex, Register8(0x13))
 	asrt.False(t, cpu.Status.Has(Negative))
 	asrt.False(t, cpu.Status.Has(Zero))
-	asrt.Equal(t, cpu.Cycle, Opcodes[INX_IMP].Cycles+Opcodes[BRK_IMP].Cycles)
+	asrt.Equal(t, cpu.Cycle, Opcodes[INX_IMP].Cycles)
 }
 
 func TestInxImpliedNegativeValue(t *testing.T) {
@@ -1395,14 +1395,14 @@ func TestInxImpliedNegativeValue(t *testing.T) {
 		INX_IMP, BRK_IMP,
 	}
 
-	cpu := Cpu{}
+	cpu := Cpu{StopWhen: StopOnOpcode(BRK_IMP)}
 	cpu.XIndex = 0x7F
 	cpu.Run(BusEx{&memory})
 
 	asrt.Equal(t, cpu.XIndex, Register8(0x80))
 	asrt.True(t, cpu.Status.Has(Negative))
 	asrt.False(t, cpu.Status.Has(Zero))
-	asrt.Equal(t, cpu.Cycle, Opcodes[INX_IMP].Cycles+Opcodes[BRK_IMP].Cycles)
+	asrt.Equal(t, cpu.Cycle, Opcodes[INX_IMP].Cycles)
 }
 
 func TestInxImpliedZeroValue(t *testing.T) {
@@ -1410,14 +1410,14 @@ func TestInxImpliedZeroValue(t *testing.T) {
 		INX_IMP, BRK_IMP,
 	}
 
-	cpu := Cpu{}
+	cpu := Cpu{StopWhen: StopOnOpcode(BRK_IMP)}
 	cpu.XIndex = 0xFF
 	cpu.Run(BusEx{&memory})
 
 	asrt.Equal(t, cpu.XIndex, Register8(0x00))
 	asrt.False(t, cpu.Status.Has(Negative))
 	asrt.True(t, cpu.Status.Has(Zero))
-	asrt.Equal(t, cpu.Cycle, Opcodes[INX_IMP].Cycles+Opcodes[BRK_IMP].Cycles)
+	asrt.Equal(t, cpu.Cycle, Opcodes[INX_IMP].Cycles)
 }
 
 func TestInyImpliedPositiveValue(t *testing.T) {
@@ -1425,14 +1425,14 @@ func TestInyImpliedPositiveValue(t *testing.T) {
 		INY_IMP, BRK_IMP,
 	}
 
-	cpu := Cpu{}
+	cpu := Cpu{StopWhen: StopOnOpcode(BRK_IMP)}
 	cpu.YIndex = 0x12
 	cpu.Run(BusEx{&memory})
 
 	asrt.Equal(t, cpu.YIndex, Register8(0x13))
 	asrt.False(t, cpu.Status.Has(Negative))
 	asrt.False(t, cpu.Status.Has(Zero))
-	asrt.Equal(t, cpu.Cycle, Opcodes[INY_IMP].Cycles+Opcodes[BRK_IMP].Cycles)
+	asrt.Equal(t, cpu.Cycle, Opcodes[INY_IMP].Cycles)
 }
 
 func TestInyImpliedNegativeValue(t *testing.T) {
@@ -1440,14 +1440,14 @@ func TestInyImpliedNegativeValue(t *testing.T) {
 		INY_IMP, BRK_IMP,
 	}
 
-	cpu := Cpu{}
+	cpu := Cpu{StopWhen: StopOnOpcode(BRK_IMP)}
 	cpu.YIndex = 0x7F
 	cpu.Run(BusEx{&memory})
 
 	asrt.Equal(t, cpu.YIndex, Register8(0x80))
 	asrt.True(t, cpu.Status.Has(Negative))
 	asrt.False(t, cpu.Status.Has(Zero))
-	asrt.Equal(t, cpu.Cycle, Opcodes[INY_IMP].Cycles+Opcodes[BRK_IMP].Cycles)
+	asrt.Equal(t, cpu.Cycle, Opcodes[INY_IMP].Cycles)
 }
 
 func TestInyImpliedZeroValue(t *testing.T) {
@@ -1455,14 +1455,14 @@ func TestInyImpliedZeroValue(t *testing.T) {
 		INY_IMP, BRK_IMP,
 	}
 
-	cpu := Cpu{}
+	cpu := Cpu{StopWhen: StopOnOpcode(BRK_IMP)}
 	cpu.YIndex = 0xFF
 	cpu.Run(BusEx{&memory})
 
 	asrt.Equal(t, cpu.YIndex, Register8(0x00))
 	asrt.False(t, cpu.Status.Has(Negative))
 	asrt.True(t, cpu.Status.Has(Zero))
-	asrt.Equal(t, cpu.Cycle, Opcodes[INY_IMP].Cycles+Opcodes[BRK_IMP].Cycles)
+	asrt.Equal(t, cpu.Cycle, Opcodes[INY_IMP].Cycles)
 }
 
 func TestDecZeroPagePositiveValue(t *testing.T) {
@@ -1471,13 +1471,13 @@ func TestDecZeroPagePositiveValue(t *testing.T) {
 	}
 
 	memory[0xAE] = 0x15
-	cpu := Cpu{}
+	cpu := Cpu{StopWhen: StopOnOpcode(BRK_IMP)}
 	cpu.Run(BusEx{&memory})
 
 	asrt.Equal(t, memory[0xAE], uint8(0x14))
 	asrt.False(t, cpu.Status.Has(Negative))
 	asrt.False(t, cpu.Status.Has(Zero))
-	asrt.Equal(t, cpu.Cycle, Opcodes[DEC_ZER].Cycles+Opcodes[BRK_IMP].Cycles)
+	asrt.Equal(t, cpu.Cycle, Opcodes[DEC_ZER].Cycles)
 }
 
 func TestDecZeroPageNegativeValue(t *testing.T) {
@@ -1486,13 +1486,13 @@ func TestDecZeroPageNegativeValue(t *testing.T) {
 	}
 
 	memory[0xAE] = 0x81
-	cpu := Cpu{}
+	cpu := Cpu{StopWhen: StopOnOpcode(BRK_IMP)}
 	cpu.Run(BusEx{&memory})
 
 	asrt.Equal(t, memory[0xAE], uint8(0x80))
 	asrt.True(t, cpu.Status.Has(Negative))
 	asrt.False(t, cpu.Status.Has(Zero))
-	asrt.Equal(t, cpu.Cycle, Opcodes[DEC_ZER].Cycles+Opcodes[BRK_IMP].Cycles)
+	asrt.Equal(t, cpu.Cycle, Opcodes[DEC_ZER].Cycles)
 }
 
 func TestDecZeroPageZeroValue(t *testing.T) {
@@ -1501,13 +1501,13 @@ func TestDecZeroPageZeroValue(t *testing.T) {
 	}
 
 	memory[0xAE] = 0x01
-	cpu := Cpu{}
+	cpu := Cpu{StopWhen: StopOnOpcode(BRK_IMP)}
 	cpu.Run(BusEx{&memory})
 
 	asrt.Equal(t, memory[0xAE], uint8(0x00))
 	asrt.False(t, cpu.Status.Has(Negative))
 	asrt.True(t, cpu.Status.Has(Zero))
-	asrt.Equal(t, cpu.Cycle, Opcodes[DEC_ZER].Cycles+Opcodes[BRK_IMP].Cycles)
+	asrt.Equal(t, cpu.Cycle, Opcodes[DEC_ZER].Cycles)
 }
 
 func TestDecZeroPageXPositiveValue(t *testing.T) {
@@ -1516,14 +1516,14 @@ func TestDecZeroPageXPositiveValue(t *testing.T) {
 	}
 
 	memory[0x6A] = 0x15
-	cpu := Cpu{}
+	cpu := Cpu{StopWhen: StopOnOpcode(BRK_IMP)}
 	cpu.XIndex = 0x10
 	cpu.Run(BusEx{&memory})
 
 	asrt.Equal(t, memory[0x6A], uint8(0x14))
 	asrt.False(t, cpu.Status.Has(Negative))
 	asrt.False(t, cpu.Status.Has(Zero))
-	asrt.Equal(t, cpu.Cycle, Opcodes[DEC_ZRX].Cycles+Opcodes[BRK_IMP].Cycles)
+	asrt.Equal(t, cpu.Cycle, Opcodes[DEC_ZRX].Cycles)
 }
 
 func TestDecZeroPageXNagativeValue(t *testing.T) {
@@ -1532,14 +1532,14 @@ func TestDecZeroPageXNagativeValue(t *testing.T) {
 	}
 
 	memory[0x6A] = 0x81
-	cpu := Cpu{}
+	cpu := Cpu{StopWhen: StopOnOpcode(BRK_IMP)}
 	cpu.XIndex = 0x10
 	cpu.Run(BusEx{&memory})
 
 	asrt.Equal(t, memory[0x6A], uint8(0x80))
 	asrt.True(t, cpu.Status.Has(Negative))
 	asrt.False(t, cpu.Status.Has(Zero))
-	asrt.Equal(t, cpu.Cycle, Opcodes[DEC_ZRX].Cycles+Opcodes[BRK_IMP].Cycles)
+	asrt.Equal(t, cpu.Cycle, Opcodes[DEC_ZRX].Cycles)
 }
 
 func TestDecZeroPageXZeroValue(t *testing.T) {
@@ -1548,14 +1548,14 @@ func TestDecZeroPageXZeroValue(t *testing.T) {
 	}
 
 	memory[0x6A] = 0x01
-	cpu := Cpu{}
+	cpu := Cpu{StopWhen: StopOnOpcode(BRK_IMP)}
 	cpu.XIndex = 0x10
 	cpu.Run(BusEx{&memory})
 
 	asrt.Equal(t, memory[0x6A], uint8(0x00))
 	asrt.False(t, cpu.Status.Has(Negative))
 	asrt.True(t, cpu.Status.Has(Zero))
-	asrt.Equal(t, cpu.Cycle, Opcodes[DEC_ZRX].Cycles+Opcodes[BRK_IMP].Cycles)
+	asrt.Equal(t, cpu.Cycle, Opcodes[DEC_ZRX].Cycles)
 }
 
 func TestDecAbsolutePositiveValue(t *testing.T) {
@@ -1564,13 +1564,13 @@ func TestDecAbsolutePositiveValue(t *testing.T) {
 	}
 
 	memory[0xEA5A] = 0x15
-	cpu := Cpu{}
+	cpu := Cpu{StopWhen: StopOnOpcode(BRK_IMP)}
 	cpu.Run(BusEx{&memory})
 
 	asrt.Equal(t, memory[0xEA5A], uint8(0x14))
 	asrt.False(t, cpu.Status.Has(Negative))
 	asrt.False(t, cpu.Status.Has(Zero))
-	asrt.Equal(t, cpu.Cycle, Opcodes[DEC_ABS].Cycles+Opcodes[BRK_IMP].Cycles)
+	asrt.Equal(t, cpu.Cycle, Opcodes[DEC_ABS].Cycles)
 }
 
 func TestDecAbsoluteNegativeValue(t *testing.T) {
@@ -1579,13 +1579,13 @@ func TestDecAbsoluteNegativeValue(t *testing.T) {
 	}
 
 	memory[0xEA5A] = 0x90
-	cpu := Cpu{}
+	cpu := Cpu{StopWhen: StopOnOpcode(BRK_IMP)}
 	cpu.Run(BusEx{&memory})
 
 	asrt.Equal(t, memory[0xEA5A], uint8(0x8F))
 	asrt.True(t, cpu.Status.Has(Negative))
 	asrt.False(t, cpu.Status.Has(Zero))
-	asrt.Equal(t, cpu.Cycle, Opcodes[DEC_ABS].Cycles+Opcodes[BRK_IMP].Cycles)
+	asrt.Equal(t, cpu.Cycle, Opcodes[DEC_ABS].Cycles)
 }
 
 func TestDecAbsoluteZeroValue(t *testing.T) {
@@ -1594,13 +1594,13 @@ func TestDecAbsoluteZeroValue(t *testing.T) {
 	}
 
 	memory[0xEA5A] = 0x01
-	cpu := Cpu{}
+	cpu := Cpu{StopWhen: StopOnOpcode(BRK_IMP)}
 	cpu.Run(BusEx{&memory})
 
 	asrt.Equal(t, memory[0xEA5A], uint8(0x00))
 	asrt.False(t, cpu.Status.Has(Negative))
 	asrt.True(t, cpu.Status.Has(Zero))
-	asrt.Equal(t, cpu.Cycle, Opcodes[DEC_ABS].Cycles+Opcodes[BRK_IMP].Cycles)
+	asrt.Equal(t, cpu.Cycle, Opcodes[DEC_ABS].Cycles)
 }
 
 func TestDecAbsoluteXPositiveValue(t *testing.T) {
@@ -1609,14 +1609,14 @@ func TestDecAbsoluteXPositiveValue(t *testing.T) {
 	}
 
 	memory[0xEA84] = 0x15
-	cpu := Cpu{}
+	cpu := Cpu{StopWhen: StopOnOpcode(BRK_IMP)}
 	cpu.XIndex = 0x10
 	cpu.Run(BusEx{&memory})
 
 	asrt.Equal(t, memory[0xEA84], uint8(0x14))
 	asrt.False(t, cpu.Status.Has(Negative))
 	asrt.False(t, cpu.Status.Has(Zero))
-	asrt.Equal(t, cpu.Cycle, Opcodes[DEC_ABX].Cycles+Opcodes[BRK_IMP].Cycles)
+	asrt.Equal(t, cpu.Cycle, Opcodes[DEC_ABX].Cycles)
 }
 
 func TestDecAbsoluteXNegativeValue(t *testing.T) {
@@ -1625,14 +1625,14 @@ func TestDecAbsoluteXNegativeValue(t *testing.T) {
 	}
 
 	memory[0xEA84] = 0xDE
-	cpu := Cpu{}
+	cpu := Cpu{StopWhen: StopOnOpcode(BRK_IMP)}
 	cpu.XIndex = 0x10
 	cpu.Run(BusEx{&memory})
 
 	asrt.Equal(t, memory[0xEA84], uint8(0xDD))
 	asrt.True(t, cpu.Status.Has(Negative))
 	asrt.False(t, cpu.Status.Has(Zero))
-	asrt.Equal(t, cpu.Cycle, Opcodes[DEC_ABX].Cycles+Opcodes[BRK_IMP].Cycles)
+	asrt.Equal(t, cpu.Cycle, Opcodes[DEC_ABX].Cycles)
 }
 
 func TestDecAbsoluteXZeroValue(t *testing.T) {
@@ -1641,14 +1641,14 @@ func TestDecAbsoluteXZeroValue(t *testing.T) {
 	}
 
 	memory[0xEA84] = 0x01
-	cpu := Cpu{}
+	cpu := Cpu{StopWhen: StopOnOpcode(BRK_IMP)}
 	cpu.XIndex = 0x10
 	cpu.Run(BusEx{&memory})
 
 	asrt.Equal(t, memory[0xEA84], uint8(0x00))
 	asrt.False(t, cpu.Status.Has(Negative))
 	asrt.True(t, cpu.Status.Has(Zero))
-	asrt.Equal(t, cpu.Cycle, Opcodes[DEC_ABX].Cycles+Opcodes[BRK_IMP].Cycles)
+	asrt.Equal(t, cpu.Cycle, Opcodes[DEC_ABX].Cycles)
 }
 
 func TestDexImpliedPositiveValue(t *testing.T) {
@@ -1656,14 +1656,14 @@ func TestDexImpliedPositiveValue(t *testing.T) {
 		DEX_IMP, BRK_IMP,
 	}
 
-	cpu := Cpu{}
+	cpu := Cpu{StopWhen: StopOnOpcode(BRK_IMP)}
 	cpu.XIndex = 0x10
 	cpu.Run(BusEx{&memory})
 
 	asrt.Equal(t, cpu.XIndex, Register8(0x0F))
 	asrt.False(t, cpu.Status.Has(Negative))
 	asrt.False(t, cpu.Status.Has(Zero))
-	asrt.Equal(t, cpu.Cycle, Opcodes[DEX_IMP].Cycles+Opcodes[BRK_IMP].Cycles)
+	asrt.Equal(t, cpu.Cycle, Opcodes[DEX_IMP].Cycles)
 }
 
 func TestDexImpliedNegativeValue(t *testing.T) {
@@ -1671,14 +1671,14 @@ func TestDexImpliedNegativeValue(t *testing.T) {
 		DEX_IMP, BRK_IMP,
 	}
 
-	cpu := Cpu{}
+	cpu := Cpu{StopWhen: StopOnOpcode(BRK_IMP)}
 	cpu.XIndex = 0xFE
 	cpu.Run(BusEx{&memory})
 
 	asrt.Equal(t, cpu.XIndex, Register8(0xFD))
 	asrt.True(t, cpu.Status.Has(Negative))
 	asrt.False(t, cpu.Status.Has(Zero))
-	asrt.Equal(t, cpu.Cycle, Opcodes[DEX_IMP].Cycles+Opcodes[BRK_IMP].Cycles)
+	asrt.Equal(t, cpu.Cycle, Opcodes[DEX_IMP].Cycles)
 }
 
 func TestDexImpliedZeroValue(t *testing.T) {
@@ -1686,14 +1686,14 @@ func TestDexImpliedZeroValue(t *testing.T) {
 		DEX_IMP, BRK_IMP,
 	}
 
-	cpu := Cpu{}
+	cpu := Cpu{StopWhen: StopOnOpcode(BRK_IMP)}
 	cpu.XIndex = 0x01
 	cpu.Run(BusEx{&memory})
 
 	asrt.Equal(t, cpu.XIndex, Register8(0x00))
 	asrt.False(t, cpu.Status.Has(Negative))
 	asrt.True(t, cpu.Status.Has(Zero))
-	asrt.Equal(t, cpu.Cycle, Opcodes[DEX_IMP].Cycles+Opcodes[BRK_IMP].Cycles)
+	asrt.Equal(t, cpu.Cycle, Opcodes[DEX_IMP].Cycles)
 }
 
 func TestDeyImpliedPositiveValue(t *testing.T) {
@@ -1701,14 +1701,14 @@ func TestDeyImpliedPositiveValue(t *testing.T) {
 		DEY_IMP, BRK_IMP,
 	}
 
-	cpu := Cpu{}
+	cpu := Cpu{StopWhen: StopOnOpcode(BRK_IMP)}
 	cpu.YIndex = 0x10
 	cpu.Run(BusEx{&memory})
 
 	asrt.Equal(t, cpu.YIndex, Register8(0x0F))
 	asrt.False(t, cpu.Status.Has(Negative))
 	asrt.False(t, cpu.Status.Has(Zero))
-	asrt.Equal(t, cpu.Cycle, Opcodes[DEY_IMP].Cycles+Opcodes[BRK_IMP].Cycles)
+	asrt.Equal(t, cpu.Cycle, Opcodes[DEY_IMP].Cycles)
 }
 
 func TestDeyImpliedNegativeValue(t *testing.T) {
@@ -1716,14 +1716,14 @@ func TestDeyImpliedNegativeValue(t *testing.T) {
 		DEY_IMP, BRK_IMP,
 	}
 
-	cpu := Cpu{}
+	cpu := Cpu{StopWhen: StopOnOpcode(BRK_IMP)}
 	cpu.YIndex = 0xFE
 	cpu.Run(BusEx{&memory})
 
 	asrt.Equal(t, cpu.YIndex, Register8(0xFD))
 	asrt.True(t, cpu.Status.Has(Negative))
 	asrt.False(t, cpu.Status.Has(Zero))
-	asrt.Equal(t, cpu.Cycle, Opcodes[DEY_IMP].Cycles+Opcodes[BRK_IMP].Cycles)
+	asrt.Equal(t, cpu.Cycle, Opcodes[DEY_IMP].Cycles)
 }
 
 func TestDeyImpliedZeroValue(t *testing.T) {
@@ -1731,14 +1731,14 @@ func TestDeyImpliedZeroValue(t *testing.T) {
 		DEY_IMP, BRK_IMP,
 	}
 
-	cpu := Cpu{}
+	cpu := Cpu{StopWhen: StopOnOpcode(BRK_IMP)}
 	cpu.YIndex = 0x01
 	cpu.Run(BusEx{&memory})
 
 	asrt.Equal(t, cpu.YIndex, Register8(0x00))
 	asrt.False(t, cpu.Status.Has(Negative))
 	asrt.True(t, cpu.Status.Has(Zero))
-	asrt.Equal(t, cpu.Cycle, Opcodes[DEY_IMP].Cycles+Opcodes[BRK_IMP].Cycles)
+	asrt.Equal(t, cpu.Cycle, Opcodes[DEY_IMP].Cycles)
 }
 
 func TestClcImplied(t *testing.T) {
@@ -1746,13 +1746,13 @@ func TestClcImplied(t *testing.T) {
 		CLC_IMP, BRK_IMP,
 	}
 
-	cpu := Cpu{}
+	cpu := Cpu{StopWhen: StopOnOpcode(BRK_IMP)}
 	cpu.Status.Add(Carry)
 	asrt.True(t, cpu.Status.Has(Carry))
 	cpu.Run(BusEx{&memory})
 	asrt.False(t, cpu.Status.Has(Carry))
 
-	asrt.Equal(t, cpu.Cycle, Opcodes[CLC_IMP].Cycles+Opcodes[BRK_IMP].Cycles)
+	asrt.Equal(t, cpu.Cycle, Opcodes[CLC_IMP].Cycles)
 }
 
 func TestClcImpliedWithOtherFlagSet(t *testing.T) {
@@ -1760,7 +1760,7 @@ func TestClcImpliedWithOtherFlagSet(t *testing.T) {
 		CLC_IMP, BRK_IMP,
 	}
 
-	cpu := Cpu{}
+	cpu := Cpu{StopWhen: StopOnOpcode(BRK_IMP)}
 	cpu.Status.Add(Carry)
 	cpu.Status.Add(Negative)
 	cpu.Status.Add(Zero)
@@ -1768,7 +1768,7 @@ func TestClcImpliedWithOtherFlagSet(t *testing.T) {
 	cpu.Run(BusEx{&memory})
 	asrt.False(t, cpu.Status.Has(Carry))
 
-	asrt.Equal(t, cpu.Cycle, Opcodes[CLC_IMP].Cycles+Opcodes[BRK_IMP].Cycles)
+	asrt.Equal(t, cpu.Cycle, Opcodes[CLC_IMP].Cycles)
 }
 
 func TestCldImplied(t *testing.T) {
@@ -1776,13 +1776,13 @@ func TestCldImplied(t *testing.T) {
 		CLD_IMP, BRK_IMP,
 	}
 
-	cpu := Cpu{}
+	cpu := Cpu{StopWhen: StopOnOpcode(BRK_IMP)}
 	cpu.Status.Add(Decimal)
 	asrt.True(t, cpu.Status.Has(Decimal))
 	cpu.Run(BusEx{&memory})
 	asrt.False(t, cpu.Status.Has(Decimal))
 
-	asrt.Equal(t, cpu.Cycle, Opcodes[CLD_IMP].Cycles+Opcodes[BRK_IMP].Cycles)
+	asrt.Equal(t, cpu.Cycle, Opcodes[CLD_IMP].Cycles)
 }
 
 func TestCldImpliedWithOtherFlagSet(t *testing.T) {
@@ -1790,7 +1790,7 @@ func TestCldImpliedWithOtherFlagSet(t *testing.T) {
 		CLD_IMP, BRK_IMP,
 	}
 
-	cpu := Cpu{}
+	cpu := Cpu{StopWhen: StopOnOpcode(BRK_IMP)}
 	cpu.Status.Add(Decimal)
 	cpu.Status.Add(Negative)
 	cpu.Status.Add(Zero)
@@ -1798,7 +1798,7 @@ func TestCldImpliedWithOtherFlagSet(t *testing.T) {
 	cpu.Run(BusEx{&memory})
 	asrt.False(t, cpu.Status.Has(Decimal))
 
-	asrt.Equal(t, cpu.Cycle, Opcodes[CLD_IMP].Cycles+Opcodes[BRK_IMP].Cycles)
+	asrt.Equal(t, cpu.Cycle, Opcodes[CLD_IMP].Cycles)
 }
 
 func TestCliImplied(t *testing.T) {
@@ -1806,13 +1806,13 @@ func TestCliImplied(t *testing.T) {
 		CLI_IMP, BRK_IMP,
 	}
 
-	cpu := Cpu{}
+	cpu := Cpu{StopWhen: StopOnOpcode(BRK_IMP)}
 	cpu.Status.Add(Interrupt)
 	asrt.True(t, cpu.Status.Has(Interrupt))
 	cpu.Run(BusEx{&memory})
 	asrt.False(t, cpu.Status.Has(Interrupt))
 
-	asrt.Equal(t, cpu.Cycle, Opcodes[CLI_IMP].Cycles+Opcodes[BRK_IMP].Cycles)
+	asrt.Equal(t, cpu.Cycle, Opcodes[CLI_IMP].Cycles)
 }
 
 func TestClvImplied(t *testing.T) {
@@ -1820,13 +1820,13 @@ func TestClvImplied(t *testing.T) {
 		CLV_IMP, BRK_IMP,
 	}
 
-	cpu := Cpu{}
+	cpu := Cpu{StopWhen: StopOnOpcode(BRK_IMP)}
 	cpu.Status.Add(Verflow)
 	asrt.True(t, cpu.Status.Has(Verflow))
 	cpu.Run(BusEx{&memory})
 	asrt.False(t, cpu.Status.Has(Verflow))
 
-	asrt.Equal(t, cpu.Cycle, Opcodes[CLV_IMP].Cycles+Opcodes[BRK_IMP].Cycles)
+	asrt.Equal(t, cpu.Cycle, Opcodes[CLV_IMP].Cycles)
 }
 
 func TestSecImplied(t *testing.T) {
@@ -1834,11 +1834,11 @@ func TestSecImplied(t *testing.T) {
 		SEC_IMP, BRK_IMP,
 	}
 
-	cpu := Cpu{}
+	cpu := Cpu{StopWhen: StopOnOpcode(BRK_IMP)}
 	cpu.Run(BusEx{&memory})
 	asrt.True(t, cpu.Status.Has(Carry))
 
-	asrt.Equal(t, cpu.Cycle, Opcodes[SEC_IMP].Cycles+Opcodes[BRK_IMP].Cycles)
+	asrt.Equal(t, cpu.Cycle, Opcodes[SEC_IMP].Cycles)
 }
 
 func TestSedImplied(t *testing.T) {
@@ -1846,11 +1846,11 @@ func TestSedImplied(t *testing.T) {
 		SED_IMP, BRK_IMP,
 	}
 
-	cpu := Cpu{}
+	cpu := Cpu{StopWhen: StopOnOpcode(BRK_IMP)}
 	cpu.Run(BusEx{&memory})
 	asrt.True(t, cpu.Status.Has(Decimal))
 
-	asrt.Equal(t, cpu.Cycle, Opcodes[SED_IMP].Cycles+Opcodes[BRK_IMP].Cycles)
+	asrt.Equal(t, cpu.Cycle, Opcodes[SED_IMP].Cycles)
 }
 
 func TestSeiImplied(t *testing.T) {
@@ -1858,11 +1858,11 @@ func TestSeiImplied(t *testing.T) {
 		SEI_IMP, BRK_IMP,
 	}
 
-	cpu := Cpu{}
+	cpu := Cpu{StopWhen: StopOnOpcode(BRK_IMP)}
 	cpu.Run(BusEx{&memory})
 	asrt.True(t, cpu.Status.Has(Interrupt))
 
-	asrt.Equal(t, cpu.Cycle, Opcodes[SEI_IMP].Cycles+Opcodes[BRK_IMP].Cycles)
+	asrt.Equal(t, cpu.Cycle, Opcodes[SEI_IMP].Cycles)
 }
 
 func TestAndImmediate(t *testing.T) {
@@ -1870,12 +1870,12 @@ func TestAndImmediate(t *testing.T) {
 		AND_IMM, 0xF0, BRK_IMP,
 	}
 
-	cpu := Cpu{}
+	cpu := Cpu{StopWhen: StopOnOpcode(BRK_IMP)}
 	cpu.Accumulator = 0xF5
 	cpu.Run(BusEx{&memory})
 
 	asrt.Equal(t, cpu.Accumulator, Register8(0xF0))
-	asrt.Equal(t, cpu.Cycle, Opcodes[AND_IMM].Cycles+Opcodes[BRK_IMP].Cycles)
+	asrt.Equal(t, cpu.Cycle, Opcodes[AND_IMM].Cycles)
 }
 
 func TestAndImmediateZero(t *testing.T) {
@@ -1883,12 +1883,12 @@ func TestAndImmediateZero(t *testing.T) {
 		AND_IMM, 0x08, BRK_IMP,
 	}
 
-	cpu := Cpu{}
+	cpu := Cpu{StopWhen: StopOnOpcode(BRK_IMP)}
 	cpu.Accumulator = 0xD1
 	cpu.Run(BusEx{&memory})
 
 	asrt.Equal(t, cpu.Accumulator, Register8(0x00))
-	asrt.Equal(t, cpu.Cycle, Opcodes[AND_IMM].Cycles+Opcodes[BRK_IMP].Cycles)
+	asrt.Equal(t, cpu.Cycle, Opcodes[AND_IMM].Cycles)
 	asrt.True(t, cpu.Status.Has(Zero))
 	asrt.False(t, cpu.Status.Has(Negative))
 }
@@ -1898,12 +1898,12 @@ func TestAndImmediateNegative(t *testing.T) {
 		AND_IMM, 0xD9, BRK_IMP,
 	}
 
-	cpu := Cpu{}
+	cpu := Cpu{StopWhen: StopOnOpcode(BRK_IMP)}
 	cpu.Accumulator = 0xD1
 	cpu.Run(BusEx{&memory})
 
 	asrt.Equal(t, cpu.Accumulator, Register8(0xD1))
-	asrt.Equal(t, cpu.Cycle, Opcodes[AND_IMM].Cycles+Opcodes[BRK_IMP].Cycles)
+	asrt.Equal(t, cpu.Cycle, Opcodes[AND_IMM].Cycles)
 	asrt.False(t, cpu.Status.Has(Zero))
 	asrt.True(t, cpu.Status.Has(Negative))
 }
@@ -1913,12 +1913,12 @@ func TestEorImmediate(t *testing.T) {
 		EOR_IMM, 0xF0, BRK_IMP,
 	}
 
-	cpu := Cpu{}
+	cpu := Cpu{StopWhen: StopOnOpcode(BRK_IMP)}
 	cpu.Accumulator = 0xF5
 	cpu.Run(BusEx{&memory})
 
 	asrt.Equal(t, cpu.Accumulator, Register8(0x05))
-	asrt.Equal(t, cpu.Cycle, Opcodes[EOR_IMM].Cycles+Opcodes[BRK_IMP].Cycles)
+	asrt.Equal(t, cpu.Cycle, Opcodes[EOR_IMM].Cycles)
 }
 
 func TestEorImmediateZero(t *testing.T) {
@@ -1926,12 +1926,12 @@ func TestEorImmediateZero(t *testing.T) {
 		EOR_IMM, 0x45, BRK_IMP,
 	}
 
-	cpu := Cpu{}
+	cpu := Cpu{StopWhen: StopOnOpcode(BRK_IMP)}
 	cpu.Accumulator = 0x45
 	cpu.Run(BusEx{&memory})
 
 	asrt.Equal(t, cpu.Accumulator, Register8(0x00))
-	asrt.Equal(t, cpu.Cycle, Opcodes[EOR_IMM].Cycles+Opcodes[BRK_IMP].Cycles)
+	asrt.Equal(t, cpu.Cycle, Opcodes[EOR_IMM].Cycles)
 	asrt.True(t, cpu.Status.Has(Zero))
 }
 
@@ -1940,13 +1940,13 @@ func TestAorImmediate(t *testing.T) {
 		ORA_IMM, 0x10, BRK_IMP,
 	}
 
-	cpu := Cpu{}
+	cpu := Cpu{StopWhen: StopOnOpcode(BRK_IMP)}
 	cpu.Accumulator = 0x05
 	cpu.Run(BusEx{&memory})
 
 	asrt.Equal(t, cpu.Accumulator, Register8(0x15))
 	asrt.False(t, cpu.Status.Has(Negative))
-	asrt.Equal(t, cpu.Cycle, Opcodes[ORA_IMM].Cycles+Opcodes[BRK_IMP].Cycles)
+	asrt.Equal(t, cpu.Cycle, Opcodes[ORA_IMM].Cycles)
 }
 
 func TestAorImmediateNegative(t *testing.T) {
@@ -1954,12 +1954,12 @@ func TestAorImmediateNegative(t *testing.T) {
 		ORA_IMM, 0xF0, BRK_IMP,
 	}
 
-	cpu := Cpu{}
+	cpu := Cpu{StopWhen: StopOnOpcode(BRK_IMP)}
 	cpu.Accumulator = 0x05
 	cpu.Run(BusEx{&memory})
 
 	asrt.Equal(t, cpu.Accumulator, Register8(0xF5))
-	asrt.Equal(t, cpu.Cycle, Opcodes[ORA_IMM].Cycles+Opcodes[BRK_IMP].Cycles)
+	asrt.Equal(t, cpu.Cycle, Opcodes[ORA_IMM].Cycles)
 	asrt.True(t, cpu.Status.Has(Negative))
 }
 
@@ -1968,7 +1968,7 @@ func TestBitZeroPageTwoLastBitSet(t *testing.T) {
 		BIT_ZER, 0x30, BRK_IMP,
 	}
 
-	cpu := Cpu{}
+	cpu := Cpu{StopWhen: StopOnOpcode(BRK_IMP)}
 	memory[0x30] = 0xF0
 	cpu.Accumulator = 0x06
 	cpu.Run(BusEx{&memory})
@@ -1979,7 +1979,7 @@ func TestBitZeroPageTwoLastBitSet(t *testing.T) {
 	asrt.True(t, cpu.Status.Has(Negative))
 	asrt.True(t, cpu.Status.Has(Zero))
 	asrt.True(t, cpu.Status.Has(Verflow))
-	asrt.Equal(t, cpu.Cycle, Opcodes[BIT_ZER].Cycles+Opcodes[BRK_IMP].Cycles)
+	asrt.Equal(t, cpu.Cycle, Opcodes[BIT_ZER].Cycles)
 }
 
 func TestBitZeroPage(t *testing.T) {
@@ -1987,7 +1987,7 @@ func TestBitZeroPage(t *testing.T) {
 		BIT_ZER, 0x50, BRK_IMP,
 	}
 
-	cpu := Cpu{}
+	cpu := Cpu{StopWhen: StopOnOpcode(BRK_IMP)}
 	memory[0x50] = 0x07
 	cpu.Accumulator = 0x06
 	cpu.Run(BusEx{&memory})
@@ -1998,7 +1998,7 @@ func TestBitZeroPage(t *testing.T) {
 	asrt.False(t, cpu.Status.Has(Negative))
 	asrt.False(t, cpu.Status.Has(Zero))
 	asrt.False(t, cpu.Status.Has(Verflow))
-	asrt.Equal(t, cpu.Cycle, Opcodes[BIT_ZER].Cycles+Opcodes[BRK_IMP].Cycles)
+	asrt.Equal(t, cpu.Cycle, Opcodes[BIT_ZER].Cycles)
 }
 
 func TestTsxImplied(t *testing.T) {
@@ -2006,7 +2006,7 @@ func TestTsxImplied(t *testing.T) {
 		TSX_IMP, BRK_IMP,
 	}
 
-	cpu := Cpu{}
+	cpu := Cpu{StopWhen: StopOnOpcode(BRK_IMP)}
 	cpu.XIndex = 0x10
 	cpu.StackPointer = 0x45
 	cpu.Run(BusEx{&memory})
@@ -2015,7 +2015,7 @@ func TestTsxImplied(t *testing.T) {
 
 	asrt.False(t, cpu.Status.Has(Negative))
 	asrt.False(t, cpu.Status.Has(Zero))
-	asrt.Equal(t, cpu.Cycle, Opcodes[TSX_IMP].Cycles+Opcodes[BRK_IMP].Cycles)
+	asrt.Equal(t, cpu.Cycle, Opcodes[TSX_IMP].Cycles)
 }
 
 func TestTsxImpliedZeroValue(t *testing.T) {
@@ -2023,7 +2023,7 @@ func TestTsxImpliedZeroValue(t *testing.T) {
 		TSX_IMP, BRK_IMP,
 	}
 
-	cpu := Cpu{}
+	cpu := Cpu{StopWhen: StopOnOpcode(BRK_IMP)}
 	cpu.XIndex = 0x10
 	cpu.StackPointer = 0x00
 	cpu.Run(BusEx{&memory})
@@ -2032,7 +2032,7 @@ func TestTsxImpliedZeroValue(t *testing.T) {
 
 	asrt.False(t, cpu.Status.Has(Negative))
 	asrt.True(t, cpu.Status.Has(Zero))
-	asrt.Equal(t, cpu.Cycle, Opcodes[TSX_IMP].Cycles+Opcodes[BRK_IMP].Cycles)
+	asrt.Equal(t, cpu.Cycle, Opcodes[TSX_IMP].Cycles)
 }
 
 func TestTxsImplied(t *testing.T) {
@@ -2040,13 +2040,13 @@ func TestTxsImplied(t *testing.T) {
 		TXS_IMP, BRK_IMP,
 	}
 
-	cpu := Cpu{}
+	cpu := Cpu{StopWhen: StopOnOpcode(BRK_IMP)}
 	cpu.XIndex = 0x45
 	cpu.StackPointer = 0x10
 	cpu.Run(BusEx{&memory})
 
 	asrt.Equal(t, cpu.StackPointer, Register8(0x45))
-	asrt.Equal(t, cpu.Cycle, Opcodes[TXS_IMP].Cycles+Opcodes[BRK_IMP].Cycles)
+	asrt.Equal(t, cpu.Cycle, Opcodes[TXS_IMP].Cycles)
 }
 
 func TestPhaImplied(t *testing.T) {
@@ -2054,14 +2054,14 @@ func TestPhaImplied(t *testing.T) {
 		PHA_IMP, BRK_IMP,
 	}
 
-	cpu := Cpu{}
+	cpu := Cpu{StopWhen: StopOnOpcode(BRK_IMP)}
 	cpu.Accumulator = 0x33
 	cpu.StackPointer = 0xFF
 	cpu.Run(BusEx{&memory})
 
 	asrt.Equal(t, cpu.StackPointer, Register8(0xFE))
 	asrt.Equal(t, memory[0x01FF], uint8(0x33))
-	asrt.Equal(t, cpu.Cycle, Opcodes[PHA_IMP].Cycles+Opcodes[BRK_IMP].Cycles)
+	asrt.Equal(t, cpu.Cycle, Opcodes[PHA_IMP].Cycles)
 }
 
 func TestPhaImpliedMultiplePush(t *testing.T) {
@@ -2069,7 +2069,7 @@ func TestPhaImpliedMultiplePush(t *testing.T) {
 		PHA_IMP, PHA_IMP, PHA_IMP, BRK_IMP,
 	}
 
-	cpu := Cpu{}
+	cpu := Cpu{StopWhen: StopOnOpcode(BRK_IMP)}
 	cpu.Accumulator = 0x33
 	cpu.StackPointer = 0xFF
 	cpu.Run(BusEx{&memory})
@@ -2086,16 +2086,17 @@ func TestPhpImplied(t *testing.T) {
 		PHP_IMP, BRK_IMP,
 	}
 
-	cpu := Cpu{}
+	cpu := Cpu{StopWhen: StopOnOpcode(BRK_IMP)}
 	cpu.StackPointer = 0xFF
 	cpu.Status.Add(Decimal)
 	cpu.Status.Add(Carry)
 	cpu.Run(BusEx{&memory})
 
-	// TODO: create the checks
+	// the pushed copy has the break and unused bits set, not the register
 	asrt.Equal(t, cpu.StackPointer, Register8(0xFE))
-	// asrt.Equal(t, memory[0xFF], uint8(0x09))
-	asrt.Equal(t, cpu.Cycle, Opcodes[PHP_IMP].Cycles+Opcodes[BRK_IMP].Cycles)
+	asrt.Equal(t, memory[0x01FF], uint8(Decimal|Carry|Break|Break2))
+	asrt.False(t, cpu.Status.Has(Break))
+	asrt.Equal(t, cpu.Cycle, Opcodes[PHP_IMP].Cycles)
 }
 
 func TestPhpImpliedMultiplePush(t *testing.T) {
@@ -2103,18 +2104,17 @@ func TestPhpImpliedMultiplePush(t *testing.T) {
 		PHP_IMP, PHP_IMP, PHP_IMP, BRK_IMP,
 	}
 
-	cpu := Cpu{}
+	cpu := Cpu{StopWhen: StopOnOpcode(BRK_IMP)}
 	cpu.StackPointer = 0xFF
 	cpu.Status.Add(Decimal)
 	cpu.Status.Add(Carry)
 	cpu.Run(BusEx{&memory})
 
-	// TODO: create the checks
 	asrt.Equal(t, cpu.StackPointer, Register8(0xFC))
-	// asrt.Equal(t, memory[0xFF], uint8(0x09))
-	// asrt.Equal(t, memory[0xFE], uint8(0x09))
-	// asrt.Equal(t, memory[0xFD], uint8(0x09))
-	// asrt.Equal(t, memory[0xFC], uint8(0x00))
+	asrt.Equal(t, memory[0x01FF], uint8(Decimal|Carry|Break|Break2))
+	asrt.Equal(t, memory[0x01FE], uint8(Decimal|Carry|Break|Break2))
+	asrt.Equal(t, memory[0x01FD], uint8(Decimal|Carry|Break|Break2))
+	asrt.Equal(t, memory[0x01FC], uint8(0x00))
 }
 
 func TestPlaImplied(t *testing.T) {
@@ -2122,7 +2122,7 @@ func TestPlaImplied(t *testing.T) {
 		PLA_IMP, BRK_IMP,
 	}
 
-	cpu := Cpu{}
+	cpu := Cpu{StopWhen: StopOnOpcode(BRK_IMP)}
 	cpu.StackPointer = 0xFE
 	memory[0x01FF] = 0x15
 	cpu.Run(BusEx{&memory})
@@ -2131,7 +2131,7 @@ func TestPlaImplied(t *testing.T) {
 	asrt.Equal(t, memory[0x01FE], uint8(0x00))
 	asrt.Equal(t, memory[0x01FF], uint8(0x15))
 	asrt.Equal(t, cpu.Accumulator, Register8(0x15))
-	asrt.Equal(t, cpu.Cycle, Opcodes[PLA_IMP].Cycles+Opcodes[BRK_IMP].Cycles)
+	asrt.Equal(t, cpu.Cycle, Opcodes[PLA_IMP].Cycles)
 }
 
 func TestPlaImpliedNegative(t *testing.T) {
@@ -2139,7 +2139,7 @@ func TestPlaImpliedNegative(t *testing.T) {
 		PLA_IMP, BRK_IMP,
 	}
 
-	cpu := Cpu{}
+	cpu := Cpu{StopWhen: StopOnOpcode(BRK_IMP)}
 	cpu.StackPointer = 0xFE
 	memory[0x01FF] = 0xAE
 	cpu.Run(BusEx{&memory})
@@ -2150,7 +2150,7 @@ func TestPlaImpliedNegative(t *testing.T) {
 	asrt.Equal(t, cpu.Accumulator, Register8(0xAE))
 	asrt.True(t, cpu.Status.Has(Negative))
 	asrt.False(t, cpu.Status.Has(Zero))
-	asrt.Equal(t, cpu.Cycle, Opcodes[PLA_IMP].Cycles+Opcodes[BRK_IMP].Cycles)
+	asrt.Equal(t, cpu.Cycle, Opcodes[PLA_IMP].Cycles)
 }
 
 func TestPlpImplied(t *testing.T) {
@@ -2158,7 +2158,7 @@ func TestPlpImplied(t *testing.T) {
 		PLP_IMP, BRK_IMP,
 	}
 
-	cpu := Cpu{}
+	cpu := Cpu{StopWhen: StopOnOpcode(BRK_IMP)}
 	cpu.StackPointer = 0xFE
 	memory[0x01FF] = 0b0101_0101
 	cpu.Run(BusEx{&memory})
@@ -2172,7 +2172,7 @@ func TestPlpImpliedMultipleTime(t *testing.T) {
 		PLP_IMP, PLP_IMP, BRK_IMP,
 	}
 
-	cpu := Cpu{}
+	cpu := Cpu{StopWhen: StopOnOpcode(BRK_IMP)}
 	cpu.StackPointer = 0xFD
 	memory[0x01FE] = 0b0101_0101
 	memory[0x01FF] = 0b1111_0000
@@ -2188,7 +2188,7 @@ func TestAslAccumulatorWithCarryAndZero(t *testing.T) {
 		ASL_ACC, BRK_IMP,
 	}
 
-	cpu := Cpu{}
+	cpu := Cpu{StopWhen: StopOnOpcode(BRK_IMP)}
 	cpu.Accumulator = 0x80
 	cpu.Run(BusEx{&memory})
 
@@ -2203,7 +2203,7 @@ func TestAslAccumulatorWithCarryAndPositiveValue(t *testing.T) {
 		ASL_ACC, BRK_IMP,
 	}
 
-	cpu := Cpu{}
+	cpu := Cpu{StopWhen: StopOnOpcode(BRK_IMP)}
 	cpu.Accumulator = 0x85
 	cpu.Run(BusEx{&memory})
 
@@ -2218,7 +2218,7 @@ func TestAslAccumulatorWithNegativeResult(t *testing.T) {
 		ASL_ACC, BRK_IMP,
 	}
 
-	cpu := Cpu{}
+	cpu := Cpu{StopWhen: StopOnOpcode(BRK_IMP)}
 	cpu.Accumulator = 0x50
 	cpu.Run(BusEx{&memory})
 
@@ -2233,7 +2233,7 @@ func TestAslZeroPageCarryAndZero(t *testing.T) {
 		ASL_ZER, 0x25, BRK_IMP,
 	}
 
-	cpu := Cpu{}
+	cpu := Cpu{StopWhen: StopOnOpcode(BRK_IMP)}
 	memory[0x25] = 0x80
 	cpu.Run(BusEx{&memory})
 
@@ -2248,7 +2248,7 @@ func TestAslZeroPageNegative(t *testing.T) {
 		ASL_ZER, 0x25, BRK_IMP,
 	}
 
-	cpu := Cpu{}
+	cpu := Cpu{StopWhen: StopOnOpcode(BRK_IMP)}
 	memory[0x25] = 0x50
 	cpu.Run(BusEx{&memory})
 
@@ -2263,7 +2263,7 @@ func TestAslZeroPageCarry(t *testing.T) {
 		ASL_ZER, 0x25, BRK_IMP,
 	}
 
-	cpu := Cpu{}
+	cpu := Cpu{StopWhen: StopOnOpcode(BRK_IMP)}
 	memory[0x25] = 0x82
 	cpu.Run(BusEx{&memory})
 
@@ -2278,7 +2278,7 @@ func TestLsrAccumulatorPositiveValue(t *testing.T) {
 		LSR_ACC, BRK_IMP,
 	}
 
-	cpu := Cpu{}
+	cpu := Cpu{StopWhen: StopOnOpcode(BRK_IMP)}
 	cpu.Accumulator = 0x04
 	cpu.Run(BusEx{&memory})
 
@@ -2293,7 +2293,7 @@ func TestLsrAccumulatorZeroAndCarry(t *testing.T) {
 		LSR_ACC, BRK_IMP,
 	}
 
-	cpu := Cpu{}
+	cpu := Cpu{StopWhen: StopOnOpcode(BRK_IMP)}
 	cpu.Accumulator = 0x01
 	cpu.Run(BusEx{&memory})
 
@@ -2308,7 +2308,7 @@ func TestLsrAccumulatorCarry(t *testing.T) {
 		LSR_ACC, BRK_IMP,
 	}
 
-	cpu := Cpu{}
+	cpu := Cpu{StopWhen: StopOnOpcode(BRK_IMP)}
 	cpu.Accumulator = 0x05
 	cpu.Run(BusEx{&memory})
 
@@ -2323,7 +2323,7 @@ func TestLsrZeroPageCarry(t *testing.T) {
 		LSR_ZER, 0x33, BRK_IMP,
 	}
 
-	cpu := Cpu{}
+	cpu := Cpu{StopWhen: StopOnOpcode(BRK_IMP)}
 	memory[0x33] = 0xDF
 	cpu.Run(BusEx{&memory})
 
@@ -2338,7 +2338,7 @@ func TestRolAccumulatorPositive(t *testing.T) {
 		ROL_ACC, BRK_IMP,
 	}
 
-	cpu := Cpu{}
+	cpu := Cpu{StopWhen: StopOnOpcode(BRK_IMP)}
 	cpu.Accumulator = 0x10
 	cpu.Run(BusEx{&memory})
 
@@ -2353,7 +2353,7 @@ func TestRolAccumulatorNegativeAndCarry(t *testing.T) {
 		ROL_ACC, BRK_IMP,
 	}
 
-	cpu := Cpu{}
+	cpu := Cpu{StopWhen: StopOnOpcode(BRK_IMP)}
 	cpu.Accumulator = 0xDE
 	cpu.Run(BusEx{&memory})
 
@@ -2368,7 +2368,7 @@ func TestRolAccumulatorZero(t *testing.T) {
 		ROL_ACC, BRK_IMP,
 	}
 
-	cpu := Cpu{}
+	cpu := Cpu{StopWhen: StopOnOpcode(BRK_IMP)}
 	cpu.Accumulator = 0x80
 	cpu.Run(BusEx{&memory})
 
@@ -2383,7 +2383,7 @@ func TestRolAccumulatorWithPreviousCarry(t *testing.T) {
 		ROL_ACC, BRK_IMP,
 	}
 
-	cpu := Cpu{}
+	cpu := Cpu{StopWhen: StopOnOpcode(BRK_IMP)}
 	cpu.Accumulator = 0x20
 	cpu.Status.Add(Carry)
 	cpu.Run(BusEx{&memory})
@@ -2400,7 +2400,7 @@ func TestRolZeroPageCarryAndZero(t *testing.T) {
 		ROL_ZER, 0x33, BRK_IMP,
 	}
 
-	cpu := Cpu{}
+	cpu := Cpu{StopWhen: StopOnOpcode(BRK_IMP)}
 	memory[0x33] = 0x80
 	cpu.Run(BusEx{&memory})
 
@@ -2415,7 +2415,7 @@ func TestRolZeroPageCarryAndZeroWithPreviousCarry(t *testing.T) {
 		ROL_ZER, 0x33, BRK_IMP,
 	}
 
-	cpu := Cpu{}
+	cpu := Cpu{StopWhen: StopOnOpcode(BRK_IMP)}
 	memory[0x33] = 0x80
 	cpu.Status.Add(Carry)
 	cpu.Run(BusEx{&memory})
@@ -2431,7 +2431,7 @@ func TestRolZeroPageCarryPositiveValue(t *testing.T) {
 		ROL_ZER, 0x33, BRK_IMP,
 	}
 
-	cpu := Cpu{}
+	cpu := Cpu{StopWhen: StopOnOpcode(BRK_IMP)}
 	memory[0x33] = 0x10
 	cpu.Run(BusEx{&memory})
 
@@ -2446,7 +2446,7 @@ func TestRorAccumulatorPositiveValue(t *testing.T) {
 		ROR_ACC, BRK_IMP,
 	}
 
-	cpu := Cpu{}
+	cpu := Cpu{StopWhen: StopOnOpcode(BRK_IMP)}
 	cpu.Accumulator = 0x08
 	cpu.Run(BusEx{&memory})
 
@@ -2461,7 +2461,7 @@ func TestRorAccumulatorPositiveWithPreviousCarry(t *testing.T) {
 		ROR_ACC, BRK_IMP,
 	}
 
-	cpu := Cpu{}
+	cpu := Cpu{StopWhen: StopOnOpcode(BRK_IMP)}
 	cpu.Accumulator = 0x08
 	cpu.Status.Add(Carry)
 	cpu.Run(BusEx{&memory})
@@ -2477,7 +2477,7 @@ func TestRorZeroPageCarry(t *testing.T) {
 		ROR_ZER, 0x33, BRK_IMP,
 	}
 
-	cpu := Cpu{}
+	cpu := Cpu{StopWhen: StopOnOpcode(BRK_IMP)}
 	memory[0x33] = 0xB5
 	cpu.Run(BusEx{&memory})
 
@@ -2531,7 +2531,7 @@ func TestJsrAbsolute(t *testing.T) {
 	asrt.Equal(t, cpu.ProgramCounter, Register16(0x060f))
 	asrt.Equal(t, cpu.StackPointer, Register8(0xfd))
 	asrt.Equal(t, memory[0x01ff], uint8(0x00))
-	asrt.Equal(t, memory[0x01fe], uint8(0x03))
+	asrt.Equal(t, memory[0x01fe], uint8(0x02))
 	asrt.False(t, cpu.Status.Has(Carry))
 	asrt.False(t, cpu.Status.Has(Zero))
 	asrt.False(t, cpu.Status.Has(Negative))
@@ -2545,7 +2545,7 @@ func TestRtsImplied(t *testing.T) {
 	cpu := Cpu{}
 	cpu.StackPointer = 0xFD
 	memory[0x01FF] = 0x00
-	memory[0x01FE] = 0x03
+	memory[0x01FE] = 0x02
 	cpu.Step(BusEx{&memory})
 
 	asrt.Equal(t, cpu.ProgramCounter, Register16(0x0003))
@@ -2586,7 +2586,7 @@ func TestAdcImmediate(t *testing.T) {
 		ADC_IMM, 0x04, BRK_IMP,
 	}
 
-	cpu := Cpu{}
+	cpu := Cpu{StopWhen: StopOnOpcode(BRK_IMP)}
 	cpu.Accumulator = 0x05
 	cpu.Run(BusEx{&memory})
 
@@ -2600,7 +2600,7 @@ func TestAdcImmediateWithPreviousCarry(t *testing.T) {
 		ADC_IMM, 0x04, BRK_IMP,
 	}
 
-	cpu := Cpu{}
+	cpu := Cpu{StopWhen: StopOnOpcode(BRK_IMP)}
 	cpu.Accumulator = 0x05
 	cpu.Status.Add(Carry)
 	cpu.Run(BusEx{&memory})
@@ -2615,7 +2615,7 @@ func TestAdcImmediateWithCarryOut(t *testing.T) {
 		ADC_IMM, 0xF4, BRK_IMP,
 	}
 
-	cpu := Cpu{}
+	cpu := Cpu{StopWhen: StopOnOpcode(BRK_IMP)}
 	cpu.Accumulator = 0xC5
 	cpu.Run(BusEx{&memory})
 
@@ -2628,10 +2628,237 @@ func TestAdcImmediateWithOverflow(t *testing.T) {
 		ADC_IMM, 0x50, BRK_IMP,
 	}
 
-	cpu := Cpu{}
+	cpu := Cpu{StopWhen: StopOnOpcode(BRK_IMP)}
 	cpu.Accumulator = 0x50
 	cpu.Run(BusEx{&memory})
 
 	asrt.Equal(t, cpu.Accumulator, Register8(0xA0))
 	asrt.True(t, cpu.Status.Has(Verflow))
 }
+
+func TestBrkImpliedPushesStateAndJumpsToVector(t *testing.T) {
+	bus := Mem{}
+	bus[0x0600] = BRK_IMP
+	bus[IrqVector] = 0x00
+	bus[IrqVector+1] = 0x80
+
+	cpu := Cpu{}
+	cpu.ProgramCounter = 0x0600
+	cpu.StackPointer = 0xFF
+	cpu.Status.Add(Carry)
+	cpu.Step(&bus)
+
+	asrt.Equal(t, cpu.ProgramCounter, Register16(0x8000))
+	asrt.Equal(t, cpu.StackPointer, Register8(0xFC))
+	asrt.Equal(t, bus[0x01FF], uint8(0x06))
+	asrt.Equal(t, bus[0x01FE], uint8(0x02))
+	asrt.Equal(t, bus[0x01FD], uint8(Carry|Break|Break2))
+	asrt.True(t, cpu.Status.Has(Interrupt))
+	asrt.Equal(t, cpu.Cycle, Opcodes[BRK_IMP].Cycles)
+}
+
+func TestBrkImpliedReturnsWithRti(t *testing.T) {
+	bus := Mem{}
+	bus[0x0600] = BRK_IMP
+	bus[0x0602] = NOP_IMP
+	bus[0x8000] = RTI_IMP
+	bus[IrqVector+1] = 0x80
+
+	cpu := Cpu{StopWhen: StopOnOpcode(NOP_IMP)}
+	cpu.ProgramCounter = 0x0600
+	cpu.StackPointer = 0xFF
+	cpu.Run(&bus)
+
+	asrt.Equal(t, cpu.ProgramCounter, Register16(0x0602))
+	asrt.Equal(t, cpu.StackPointer, Register8(0xFF))
+	asrt.False(t, cpu.Status.Has(Break))
+	asrt.False(t, cpu.Status.Has(Interrupt))
+	asrt.Equal(t, cpu.Cycle, Opcodes[BRK_IMP].Cycles+Opcodes[RTI_IMP].Cycles)
+}
+
+func TestRunStopsAtAddress(t *testing.T) {
+	memory := Mem{
+		INX_IMP, INX_IMP, INX_IMP, INX_IMP,
+	}
+
+	cpu := Cpu{StopWhen: StopAtAddress(0x0003)}
+	cpu.Run(BusEx{&memory})
+
+	asrt.Equal(t, cpu.ProgramCounter, Register16(0x0003))
+	asrt.Equal(t, cpu.XIndex, Register8(0x03))
+}
+
+func TestRunStopsOnCallback(t *testing.T) {
+	memory := Mem{
+		INX_IMP, INX_IMP, INX_IMP, INX_IMP,
+	}
+
+	cpu := Cpu{}
+	cpu.StopWhen = func(c *Cpu, bus Bus) bool {
+		return c.XIndex == 2
+	}
+	cpu.Run(BusEx{&memory})
+
+	asrt.Equal(t, cpu.XIndex, Register8(0x02))
+	asrt.Equal(t, cpu.Cycle, 2*Opcodes[INX_IMP].Cycles)
+}
+
+func TestAdcImmediateDecimal(t *testing.T) {
+	memory := Mem{
+		SED_IMP, ADC_IMM, 0x27, BRK_IMP,
+	}
+
+	cpu := Cpu{StopWhen: StopOnOpcode(BRK_IMP)}
+	cpu.Accumulator = 0x15
+	cpu.Run(BusEx{&memory})
+
+	asrt.Equal(t, cpu.Accumulator, Register8(0x42))
+	asrt.False(t, cpu.Status.Has(Carry))
+	asrt.False(t, cpu.Status.Has(Zero))
+}
+
+func TestAdcImmediateDecimalWithCarryOut(t *testing.T) {
+	memory := Mem{
+		SED_IMP, ADC_IMM, 0x01, BRK_IMP,
+	}
+
+	cpu := Cpu{StopWhen: StopOnOpcode(BRK_IMP)}
+	cpu.Accumulator = 0x99
+	cpu.Run(BusEx{&memory})
+
+	asrt.Equal(t, cpu.Accumulator, Register8(0x00))
+	asrt.True(t, cpu.Status.Has(Carry))
+	// NMOS flags come from the binary sum and the intermediate result
+	asrt.False(t, cpu.Status.Has(Zero))
+	asrt.True(t, cpu.Status.Has(Negative))
+}
+
+func TestAdcImmediateDecimalWithPreviousCarry(t *testing.T) {
+	memory := Mem{
+		SED_IMP, ADC_IMM, 0x58, BRK_IMP,
+	}
+
+	cpu := Cpu{StopWhen: StopOnOpcode(BRK_IMP)}
+	cpu.Accumulator = 0x46
+	cpu.Status.Add(Carry)
+	cpu.Run(BusEx{&memory})
+
+	asrt.Equal(t, cpu.Accumulator, Register8(0x05))
+	asrt.True(t, cpu.Status.Has(Carry))
+}
+
+func TestAdcImmediateDecimalDisabled(t *testing.T) {
+	memory := Mem{
+		SED_IMP, ADC_IMM, 0x27, BRK_IMP,
+	}
+
+	cpu := Cpu{StopWhen: StopOnOpcode(BRK_IMP), Variant: Ricoh2A03}
+	cpu.Accumulator = 0x15
+	cpu.Run(BusEx{&memory})
+
+	asrt.Equal(t, cpu.Accumulator, Register8(0x3C))
+	asrt.True(t, cpu.Status.Has(Decimal))
+}
+
+func TestSbcImmediate(t *testing.T) {
+	memory := Mem{
+		SBC_IMM, 0x04, BRK_IMP,
+	}
+
+	cpu := Cpu{StopWhen: StopOnOpcode(BRK_IMP)}
+	cpu.Accumulator = 0x09
+	cpu.Status.Add(Carry)
+	cpu.Run(BusEx{&memory})
+
+	asrt.Equal(t, cpu.Accumulator, Register8(0x05))
+	asrt.True(t, cpu.Status.Has(Carry))
+	asrt.False(t, cpu.Status.Has(Verflow))
+}
+
+func TestSbcImmediateWithBorrow(t *testing.T) {
+	memory := Mem{
+		SBC_IMM, 0x04, BRK_IMP,
+	}
+
+	cpu := Cpu{StopWhen: StopOnOpcode(BRK_IMP)}
+	cpu.Accumulator = 0x02
+	cpu.Run(BusEx{&memory})
+
+	asrt.Equal(t, cpu.Accumulator, Register8(0xFD))
+	asrt.False(t, cpu.Status.Has(Carry))
+	asrt.True(t, cpu.Status.Has(Negative))
+}
+
+func TestSbcImmediateDecimal(t *testing.T) {
+	memory := Mem{
+		SED_IMP, SBC_IMM, 0x19, BRK_IMP,
+	}
+
+	cpu := Cpu{StopWhen: StopOnOpcode(BRK_IMP)}
+	cpu.Accumulator = 0x42
+	cpu.Status.Add(Carry)
+	cpu.Run(BusEx{&memory})
+
+	asrt.Equal(t, cpu.Accumulator, Register8(0x23))
+	asrt.True(t, cpu.Status.Has(Carry))
+}
+
+func TestSbcImmediateDecimalWithBorrow(t *testing.T) {
+	memory := Mem{
+		SED_IMP, SBC_IMM, 0x01, BRK_IMP,
+	}
+
+	cpu := Cpu{StopWhen: StopOnOpcode(BRK_IMP)}
+	cpu.Accumulator = 0x00
+	cpu.Status.Add(Carry)
+	cpu.Run(BusEx{&memory})
+
+	asrt.Equal(t, cpu.Accumulator, Register8(0x99))
+	asrt.False(t, cpu.Status.Has(Carry))
+	asrt.True(t, cpu.Status.Has(Negative))
+}
+
+func TestCmpImmediate(t *testing.T) {
+	memory := Mem{
+		CMP_IMM, 0x10, BRK_IMP,
+	}
+
+	cpu := Cpu{StopWhen: StopOnOpcode(BRK_IMP)}
+	cpu.Accumulator = 0x10
+	cpu.Run(BusEx{&memory})
+
+	asrt.True(t, cpu.Status.Has(Carry))
+	asrt.True(t, cpu.Status.Has(Zero))
+	asrt.False(t, cpu.Status.Has(Negative))
+}
+
+// the carry of CPX and CPY comes from their own register, not from A
+func TestCpxImmediate(t *testing.T) {
+	memory := Mem{
+		CPX_IMM, 0x20, BRK_IMP,
+	}
+
+	cpu := Cpu{StopWhen: StopOnOpcode(BRK_IMP)}
+	cpu.Accumulator = 0x00
+	cpu.XIndex = 0x30
+	cpu.Run(BusEx{&memory})
+
+	asrt.True(t, cpu.Status.Has(Carry))
+	asrt.False(t, cpu.Status.Has(Zero))
+	asrt.False(t, cpu.Status.Has(Negative))
+}
+
+func TestCpyImmediate(t *testing.T) {
+	memory := Mem{
+		CPY_IMM, 0x3A, BRK_IMP,
+	}
+
+	cpu := Cpu{StopWhen: StopOnOpcode(BRK_IMP)}
+	cpu.Accumulator = 0xFF
+	cpu.YIndex = 0x10
+	cpu.Run(BusEx{&memory})
+
+	asrt.False(t, cpu.Status.Has(Carry))
+	asrt.False(t, cpu.Status.Has(Zero))
+	asrt.True(t, cpu.Status.Has(Negative))
+}
@@ -0,0 +1,229 @@
+package debug
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/zehlt/go6502"
+)
+
+// Conditions compare registers, flags, memory and numbers:
+//
+//	A == $40
+//	X >= 3 && [$0200] != 0
+//	C || Z
+//
+// The registers are A, X, Y, SP, PC, P and CYC, the cycle count. The flags
+// N, V, D, I, Z and C are 0 or 1. [addr] is the byte at addr, read with
+// go6502.Peek without triggering the watchpoints. Numbers are $hex,
+// %binary or decimal. The comparisons are == != < <= > >=, they combine
+// with && and ||, a lone operand is true when it is not zero.
+
+type value func(c *go6502.Cpu, bus go6502.Bus) int
+
+type conditionParser struct {
+	text string
+	pos  int
+}
+
+// ParseCondition compiles a condition, to be used as the condition of a
+// breakpoint.
+func ParseCondition(text string) (go6502.StopCondition, error) {
+	p := conditionParser{text: text}
+	v, err := p.or()
+	if err != nil {
+		return nil, err
+	}
+	p.skipSpaces()
+	if p.pos < len(p.text) {
+		return nil, fmt.Errorf("unexpected %q in condition", p.text[p.pos:])
+	}
+	return func(c *go6502.Cpu, bus go6502.Bus) bool {
+		return v(c, bus) != 0
+	}, nil
+}
+
+func boolValue(b bool) int {
+	if b {
+		return 1
+	}
+	return 0
+}
+
+func (p *conditionParser) or() (value, error) {
+	left, err := p.and()
+	if err != nil {
+		return nil, err
+	}
+	for p.accept("||") {
+		right, err := p.and()
+		if err != nil {
+			return nil, err
+		}
+		l := left
+		left = func(c *go6502.Cpu, bus go6502.Bus) int {
+			return boolValue(l(c, bus) != 0 || right(c, bus) != 0)
+		}
+	}
+	return left, nil
+}
+
+func (p *conditionParser) and() (value, error) {
+	left, err := p.comparison()
+	if err != nil {
+		return nil, err
+	}
+	for p.accept("&&") {
+		right, err := p.comparison()
+		if err != nil {
+			return nil, err
+		}
+		l := left
+		left = func(c *go6502.Cpu, bus go6502.Bus) int {
+			return boolValue(l(c, bus) != 0 && right(c, bus) != 0)
+		}
+	}
+	return left, nil
+}
+
+var comparisons = []struct {
+	op      string
+	compare func(a, b int) bool
+}{
+	// the two character operators first, so that <= is not read as <
+	{"==", func(a, b int) bool { return a == b }},
+	{"!=", func(a, b int) bool { return a != b }},
+	{"<=", func(a, b int) bool { return a <= b }},
+	{">=", func(a, b int) bool { return a >= b }},
+	{"<", func(a, b int) bool { return a < b }},
+	{">", func(a, b int) bool { return a > b }},
+}
+
+func (p *conditionParser) comparison() (value, error) {
+	left, err := p.operand()
+	if err != nil {
+		return nil, err
+	}
+	for _, cmp := range comparisons {
+		if !p.accept(cmp.op) {
+			continue
+		}
+		right, err := p.operand()
+		if err != nil {
+			return nil, err
+		}
+		compare := cmp.compare
+		return func(c *go6502.Cpu, bus go6502.Bus) int {
+			return boolValue(compare(left(c, bus), right(c, bus)))
+		}, nil
+	}
+	return left, nil
+}
+
+var registers = map[string]value{
+	"A":   func(c *go6502.Cpu, bus go6502.Bus) int { return int(c.Accumulator) },
+	"X":   func(c *go6502.Cpu, bus go6502.Bus) int { return int(c.XIndex) },
+	"Y":   func(c *go6502.Cpu, bus go6502.Bus) int { return int(c.YIndex) },
+	"SP":  func(c *go6502.Cpu, bus go6502.Bus) int { return int(c.StackPointer) },
+	"PC":  func(c *go6502.Cpu, bus go6502.Bus) int { return int(c.ProgramCounter) },
+	"P":   func(c *go6502.Cpu, bus go6502.Bus) int { return int(c.Status) },
+	"CYC": func(c *go6502.Cpu, bus go6502.Bus) int { return c.Cycle },
+	"N":   flag(go6502.Negative),
+	"V":   flag(go6502.Verflow),
+	"D":   flag(go6502.Decimal),
+	"I":   flag(go6502.Interrupt),
+	"Z":   flag(go6502.Zero),
+	"C":   flag(go6502.Carry),
+}
+
+func flag(bit uint8) value {
+	return func(c *go6502.Cpu, bus go6502.Bus) int {
+		return boolValue(c.Status.Has(bit))
+	}
+}
+
+func (p *conditionParser) operand() (value, error) {
+	p.skipSpaces()
+	if p.pos >= len(p.text) {
+		return nil, fmt.Errorf("missing operand in condition")
+	}
+
+	switch ch := p.text[p.pos]; {
+	case ch == '(':
+		p.pos++
+		v, err := p.or()
+		if err != nil {
+			return nil, err
+		}
+		if !p.accept(")") {
+			return nil, fmt.Errorf("missing ) in condition")
+		}
+		return v, nil
+	case ch == '[':
+		p.pos++
+		addr, err := p.operand()
+		if err != nil {
+			return nil, err
+		}
+		if !p.accept("]") {
+			return nil, fmt.Errorf("missing ] in condition")
+		}
+		return func(c *go6502.Cpu, bus go6502.Bus) int {
+			return int(go6502.Peek(bus, uint16(addr(c, bus))))
+		}, nil
+	case ch == '$':
+		p.pos++
+		return p.number(16)
+	case ch == '%':
+		p.pos++
+		return p.number(2)
+	case ch >= '0' && ch <= '9':
+		return p.number(10)
+	}
+
+	start := p.pos
+	for p.pos < len(p.text) && isLetter(p.text[p.pos]) {
+		p.pos++
+	}
+	name := strings.ToUpper(p.text[start:p.pos])
+	if v, ok := registers[name]; ok {
+		return v, nil
+	}
+	return nil, fmt.Errorf("unexpected %q in condition", p.text[start:])
+}
+
+func (p *conditionParser) number(base int) (value, error) {
+	start := p.pos
+	for p.pos < len(p.text) && isLetterOrDigit(p.text[p.pos]) {
+		p.pos++
+	}
+	n, err := strconv.ParseInt(p.text[start:p.pos], base, 32)
+	if err != nil {
+		return nil, fmt.Errorf("bad number %q in condition", p.text[start:p.pos])
+	}
+	return func(c *go6502.Cpu, bus go6502.Bus) int { return int(n) }, nil
+}
+
+func (p *conditionParser) accept(token string) bool {
+	p.skipSpaces()
+	if strings.HasPrefix(p.text[p.pos:], token) {
+		p.pos += len(token)
+		return true
+	}
+	return false
+}
+
+func (p *conditionParser) skipSpaces() {
+	for p.pos < len(p.text) && (p.text[p.pos] == ' ' || p.text[p.pos] == '\t') {
+		p.pos++
+	}
+}
+
+func isLetter(c byte) bool {
+	return c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z'
+}
+
+func isLetterOrDigit(c byte) bool {
+	return isLetter(c) || c >= '0' && c <= '9'
+}
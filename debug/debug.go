@@ -0,0 +1,434 @@
+// Package debug drives a cpu under the control of breakpoints and
+// watchpoints, one instruction at a time. All the execution goes through
+// the Debugger, which stops between instructions and leaves the cpu and
+// the bus free to inspect:
+//
+//	d := debug.New(&cpu, &memory)
+//	d.AddBreakpoint(0x0600, "A == $40")
+//	d.AddWatchpoint(0x0200, 0x02FF, debug.Write)
+//	stop := d.Continue()
+package debug
+
+import (
+	"fmt"
+	"sync/atomic"
+
+	"github.com/zehlt/go6502"
+	"github.com/zehlt/go6502/disasm"
+	"github.com/zehlt/go6502/rewind"
+)
+
+// Access selects the bus accesses a watchpoint stops on.
+type Access int
+
+const (
+	Read Access = 1 << iota
+	Write
+	ReadWrite = Read | Write
+)
+
+// Breakpoint stops before the instruction at Addr when Condition holds, a
+// nil Condition always holds.
+type Breakpoint struct {
+	ID        int
+	Addr      uint16
+	Condition go6502.StopCondition
+	// Text is the source of the condition, empty without one
+	Text string
+	Hits int
+}
+
+// Watchpoint stops after the instruction that accessed an address between
+// Start and End, both included. Opcode and operand fetches are reads.
+type Watchpoint struct {
+	ID         int
+	Start, End uint16
+	Access     Access
+	Hits       int
+}
+
+// Reason tells why the execution stopped.
+type Reason int
+
+const (
+	// Done means the requested step, step over or step out completed
+	Done Reason = iota
+	BreakpointHit
+	WatchpointHit
+	CycleReached
+	Paused
+	Halted
+	Faulted
+)
+
+func (r Reason) String() string {
+	switch r {
+	case Done:
+		return "done"
+	case BreakpointHit:
+		return "breakpoint"
+	case WatchpointHit:
+		return "watchpoint"
+	case CycleReached:
+		return "cycle reached"
+	case Paused:
+		return "paused"
+	case Halted:
+		return "halted"
+	case Faulted:
+		return "faulted"
+	default:
+		return "unknown"
+	}
+}
+
+// Stop describes where and why the execution stopped. Addr, Data and
+// IsWrite describe the access of a watchpoint hit, Err the failure of a
+// faulted instruction.
+type Stop struct {
+	Reason     Reason
+	PC         uint16
+	Breakpoint *Breakpoint
+	Watchpoint *Watchpoint
+	Addr       uint16
+	Data       uint8
+	IsWrite    bool
+	Err        error
+}
+
+func (s Stop) String() string {
+	switch s.Reason {
+	case BreakpointHit:
+		return fmt.Sprintf("breakpoint %d at $%04X", s.Breakpoint.ID, s.PC)
+	case WatchpointHit:
+		kind := "read"
+		if s.IsWrite {
+			kind = "write"
+		}
+		return fmt.Sprintf("watchpoint %d, %s $%02X at $%04X, stopped at $%04X", s.Watchpoint.ID, kind, s.Data, s.Addr, s.PC)
+	case Faulted:
+		return fmt.Sprintf("faulted at $%04X: %v", s.PC, s.Err)
+	}
+	return fmt.Sprintf("%v at $%04X", s.Reason, s.PC)
+}
+
+// Debugger executes Cpu on Bus. The cpu must not be stepped directly while
+// watchpoints are set, the accesses would go unnoticed.
+type Debugger struct {
+	Cpu *go6502.Cpu
+	Bus go6502.Bus
+	// History records the executed instructions when set, it must be for
+	// Cpu and the memory behind Bus
+	History *rewind.Buffer
+
+	breakpoints []*Breakpoint
+	watchpoints []*Watchpoint
+	lastID      int
+
+	// the first watchpoint hit of the instruction in progress
+	watchHit *Stop
+	paused   int32
+}
+
+// New returns a debugger for cpu on bus, without breakpoints.
+func New(cpu *go6502.Cpu, bus go6502.Bus) *Debugger {
+	return &Debugger{Cpu: cpu, Bus: bus}
+}
+
+// AddBreakpoint sets a breakpoint at addr, condition is parsed by
+// ParseCondition and may be empty.
+func (d *Debugger) AddBreakpoint(addr uint16, condition string) (*Breakpoint, error) {
+	b := &Breakpoint{Addr: addr, Text: condition}
+	if condition != "" {
+		compiled, err := ParseCondition(condition)
+		if err != nil {
+			return nil, err
+		}
+		b.Condition = compiled
+	}
+	d.lastID++
+	b.ID = d.lastID
+	d.breakpoints = append(d.breakpoints, b)
+	return b, nil
+}
+
+// AddWatchpoint watches the accesses between start and end, both included.
+func (d *Debugger) AddWatchpoint(start, end uint16, access Access) *Watchpoint {
+	d.lastID++
+	w := &Watchpoint{ID: d.lastID, Start: start, End: end, Access: access}
+	d.watchpoints = append(d.watchpoints, w)
+	return w
+}
+
+// Remove deletes the breakpoint or the watchpoint with the id, it reports
+// whether there was one.
+func (d *Debugger) Remove(id int) bool {
+	for i, b := range d.breakpoints {
+		if b.ID == id {
+			d.breakpoints = append(d.breakpoints[:i], d.breakpoints[i+1:]...)
+			return true
+		}
+	}
+	for i, w := range d.watchpoints {
+		if w.ID == id {
+			d.watchpoints = append(d.watchpoints[:i], d.watchpoints[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+// Breakpoints returns the breakpoints in the order they were added.
+func (d *Debugger) Breakpoints() []*Breakpoint {
+	return append([]*Breakpoint(nil), d.breakpoints...)
+}
+
+// Watchpoints returns the watchpoints in the order they were added.
+func (d *Debugger) Watchpoints() []*Watchpoint {
+	return append([]*Watchpoint(nil), d.watchpoints...)
+}
+
+// Pause stops a running Continue, StepOver, StepOut or RunToCycle before
+// its next instruction. It is safe to call from another goroutine. The
+// commands clear it when they return. A Pause while nothing runs stops
+// the next command before its first instruction, unless ClearPause is
+// called first.
+func (d *Debugger) Pause() {
+	atomic.StoreInt32(&d.paused, 1)
+}
+
+// ClearPause forgets a Pause made while nothing ran. Call it before
+// starting a command, in the goroutine that starts it, so that a Pause
+// that comes as the command starts is not lost.
+func (d *Debugger) ClearPause() {
+	atomic.StoreInt32(&d.paused, 0)
+}
+
+// Modified tells that the cpu or the memory were changed outside of the
+// execution, the history no longer applies and is cleared.
+func (d *Debugger) Modified() {
+	if d.History != nil {
+		d.History.Clear()
+	}
+}
+
+// Registers returns a copy of the registers of the cpu.
+func (d *Debugger) Registers() go6502.Registers {
+	return d.Cpu.Registers
+}
+
+// Peek reads a byte without triggering the watchpoints, nor the side
+// effects of the bus when it is a go6502.Inspector.
+func (d *Debugger) Peek(addr uint16) uint8 {
+	return go6502.Peek(d.Bus, addr)
+}
+
+// Poke writes a byte like Peek reads it. The history no longer applies
+// and is cleared.
+func (d *Debugger) Poke(addr uint16, data uint8) {
+	go6502.Poke(d.Bus, addr, data)
+	d.Modified()
+}
+
+// Memory reads length bytes from addr like Peek.
+func (d *Debugger) Memory(addr uint16, length int) []uint8 {
+	data := make([]uint8, length)
+	for i := range data {
+		data[i] = d.Peek(addr + uint16(i))
+	}
+	return data
+}
+
+// Disassemble decodes count instructions from addr with the opcodes of the
+// cpu variant.
+func (d *Debugger) Disassemble(addr uint16, count int) []disasm.Line {
+	dis := disasm.New(d.Cpu.Variant)
+	lines := make([]disasm.Line, 0, count)
+	for i := 0; i < count; i++ {
+		line := dis.Instruction(d.Bus, addr)
+		lines = append(lines, line)
+		addr += uint16(len(line.Bytes))
+	}
+	return lines
+}
+
+// Step executes one instruction, breakpoints excluded.
+func (d *Debugger) Step() Stop {
+	return d.run(func(executed) bool { return true })
+}
+
+// StepOver executes one instruction, a JSR runs until the subroutine
+// returns.
+func (d *Debugger) StepOver() Stop {
+	pc := uint16(d.Cpu.ProgramCounter)
+	if d.Peek(pc) != go6502.JSR_ABS {
+		return d.Step()
+	}
+	sp := d.Cpu.StackPointer
+	next := pc + 3
+	return d.run(func(executed) bool {
+		return uint16(d.Cpu.ProgramCounter) == next && d.Cpu.StackPointer == sp
+	})
+}
+
+// StepOut runs until the RTS or RTI that leaves the current subroutine or
+// interrupt handler.
+func (d *Debugger) StepOut() Stop {
+	sp := d.Cpu.StackPointer
+	return d.run(func(e executed) bool {
+		return e.returned && e.sp >= sp
+	})
+}
+
+// Continue runs until a breakpoint, a watchpoint, Pause or a halt.
+func (d *Debugger) Continue() Stop {
+	return d.run(func(executed) bool { return false })
+}
+
+// RunToCycle runs until the cycle count reaches cycle. It stops at the end
+// of the instruction that reaches it, so the count may exceed cycle by a
+// few cycles.
+func (d *Debugger) RunToCycle(cycle int) Stop {
+	if d.Cpu.Cycle >= cycle {
+		return d.stop(CycleReached)
+	}
+	stop := d.run(func(executed) bool { return d.Cpu.Cycle >= cycle })
+	if stop.Reason == Done {
+		stop.Reason = CycleReached
+	}
+	return stop
+}
+
+// executed tells whether the last step returned with RTS or RTI, with the
+// stack pointer before it
+type executed struct {
+	returned bool
+	sp       go6502.Register8
+}
+
+// returned reports whether the step that ran from sp, with opcode at the
+// program counter, executed a RTS or a RTI. The step may have serviced an
+// interrupt instead, it pushed 3 bytes where RTS and RTI pull 2 and 3.
+func (d *Debugger) returned(opcode uint8, sp go6502.Register8) bool {
+	switch opcode {
+	case go6502.RTS_IMP:
+		return d.Cpu.StackPointer == sp+2
+	case go6502.RTI_IMP:
+		return d.Cpu.StackPointer == sp+3
+	}
+	return false
+}
+
+// run steps until done reports true. The breakpoints are checked before
+// every instruction but the first one, so that a stopped program resumes.
+func (d *Debugger) run(done func(executed) bool) Stop {
+	defer d.ClearPause()
+	for first := true; ; first = false {
+		if atomic.SwapInt32(&d.paused, 0) != 0 {
+			return d.stop(Paused)
+		}
+		if !first {
+			if b := d.breakpointHit(); b != nil {
+				stop := d.stop(BreakpointHit)
+				stop.Breakpoint = b
+				return stop
+			}
+		}
+		if d.Cpu.Halted() {
+			return d.stop(Halted)
+		}
+
+		opcode, sp := d.Peek(uint16(d.Cpu.ProgramCounter)), d.Cpu.StackPointer
+		d.watchHit = nil
+		if err := d.execute(); err != nil {
+			stop := d.stop(Faulted)
+			stop.Err = err
+			return stop
+		}
+		if d.watchHit != nil {
+			stop := *d.watchHit
+			stop.PC = uint16(d.Cpu.ProgramCounter)
+			return stop
+		}
+		if done(executed{returned: d.returned(opcode, sp), sp: sp}) {
+			return d.stop(Done)
+		}
+	}
+}
+
+func (d *Debugger) execute() error {
+	if d.History != nil {
+		return d.History.Step(watchBus{d})
+	}
+	return d.Cpu.Step(watchBus{d})
+}
+
+func (d *Debugger) stop(reason Reason) Stop {
+	return Stop{Reason: reason, PC: uint16(d.Cpu.ProgramCounter)}
+}
+
+func (d *Debugger) breakpointHit() *Breakpoint {
+	pc := uint16(d.Cpu.ProgramCounter)
+	for _, b := range d.breakpoints {
+		if b.Addr != pc {
+			continue
+		}
+		if b.Condition == nil || b.Condition(d.Cpu, d.Bus) {
+			b.Hits++
+			return b
+		}
+	}
+	return nil
+}
+
+func (d *Debugger) access(addr uint16, data uint8, write bool) {
+	if d.watchHit != nil {
+		return
+	}
+	kind := Read
+	if write {
+		kind = Write
+	}
+	for _, w := range d.watchpoints {
+		if w.Access&kind == 0 || addr < w.Start || addr > w.End {
+			continue
+		}
+		w.Hits++
+		d.watchHit = &Stop{Reason: WatchpointHit, Watchpoint: w, Addr: addr, Data: data, IsWrite: write}
+		return
+	}
+}
+
+// watchBus reports the accesses of the cpu to the watchpoints
+type watchBus struct {
+	d *Debugger
+}
+
+func (b watchBus) Read(addr uint16) uint8 {
+	data := b.d.Bus.Read(addr)
+	b.d.access(addr, data, false)
+	return data
+}
+
+func (b watchBus) Write(addr uint16, data uint8) {
+	b.d.Bus.Write(addr, data)
+	b.d.access(addr, data, true)
+}
+
+// the hooks of the cpu, like its Trace, look through the watchpoints
+func (b watchBus) Peek(addr uint16) uint8 {
+	return go6502.Peek(b.d.Bus, addr)
+}
+
+func (b watchBus) Poke(addr uint16, data uint8) {
+	go6502.Poke(b.d.Bus, addr, data)
+}
+
+func (b watchBus) ReadWord(addr uint16) uint16 {
+	return uint16(b.Read(addr)) | uint16(b.Read(addr+1))<<8
+}
+
+func (b watchBus) WriteWord(addr uint16, data uint16) {
+	b.Write(addr, uint8(data))
+	b.Write(addr+1, uint8(data>>8))
+}
@@ -0,0 +1,235 @@
+package debug
+
+import (
+	"testing"
+
+	"github.com/zehlt/go6502"
+	"github.com/zehlt/go6502/asm"
+	"github.com/zehlt/go6502/asrt"
+)
+
+const program = `
+	.org $0600
+main:	ldx #0
+	lda #$40
+	jsr sub
+	sta $0200
+loop:	inx
+	cpx #5
+	bne loop
+	stp
+sub:	lda #$41
+	jsr inner
+	rts
+inner:	nop
+	rts
+`
+
+func newDebugger(t *testing.T) (*Debugger, map[string]uint16) {
+	t.Helper()
+	p, err := asm.New(go6502.WDC65C02).Assemble("program.s", program)
+	if err != nil {
+		t.Fatal(err)
+	}
+	memory := &go6502.Mem{}
+	p.Load(memory)
+
+	cpu := &go6502.Cpu{Variant: go6502.WDC65C02}
+	cpu.ProgramCounter = go6502.Register16(0x0600)
+	cpu.StackPointer = 0xFF
+	return New(cpu, memory), p.Symbols
+}
+
+func TestConditionalBreakpoint(t *testing.T) {
+	d, symbols := newDebugger(t)
+	b, err := d.AddBreakpoint(symbols["loop"], "X == 3 && A != 0")
+	asrt.Equal(t, err, nil)
+
+	stop := d.Continue()
+	asrt.Equal(t, stop.Reason, BreakpointHit)
+	asrt.Equal(t, stop.Breakpoint, b)
+	asrt.Equal(t, stop.PC, symbols["loop"])
+	asrt.Equal(t, d.Registers().XIndex, go6502.Register8(3))
+	asrt.Equal(t, b.Hits, 1)
+
+	// resuming from the breakpoint does not hit it again right away
+	asrt.True(t, d.Remove(b.ID))
+	stop = d.Continue()
+	asrt.Equal(t, stop.Reason, Halted)
+	asrt.Equal(t, d.Peek(0x0200), uint8(0x41))
+}
+
+func TestWatchpoints(t *testing.T) {
+	d, symbols := newDebugger(t)
+	w := d.AddWatchpoint(0x0200, 0x02FF, Write)
+
+	stop := d.Continue()
+	asrt.Equal(t, stop.Reason, WatchpointHit)
+	asrt.Equal(t, stop.Watchpoint, w)
+	asrt.Equal(t, stop.Addr, uint16(0x0200))
+	asrt.Equal(t, stop.Data, uint8(0x41))
+	asrt.True(t, stop.IsWrite)
+	asrt.Equal(t, stop.PC, symbols["loop"])
+
+	d.Remove(w.ID)
+	r := d.AddWatchpoint(symbols["inner"], symbols["inner"], Read)
+	d.Cpu.ProgramCounter = go6502.Register16(symbols["main"])
+	stop = d.Continue()
+	asrt.Equal(t, stop.Reason, WatchpointHit)
+	asrt.Equal(t, stop.Watchpoint, r)
+	asrt.False(t, stop.IsWrite)
+}
+
+func TestStepOverAndOut(t *testing.T) {
+	d, symbols := newDebugger(t)
+
+	d.Step()
+	d.Step()
+	stop := d.StepOver()
+	asrt.Equal(t, stop.Reason, Done)
+	asrt.Equal(t, stop.PC, symbols["main"]+7)
+	asrt.Equal(t, d.Registers().Accumulator, go6502.Register8(0x41))
+	asrt.Equal(t, d.Registers().StackPointer, go6502.Register8(0xFF))
+
+	// step out of inner, then out of sub
+	d.Cpu.ProgramCounter = go6502.Register16(symbols["main"] + 4)
+	d.Step()
+	d.Step()
+	d.Step()
+	asrt.Equal(t, uint16(d.Cpu.ProgramCounter), symbols["inner"])
+	stop = d.StepOut()
+	asrt.Equal(t, stop.PC, symbols["sub"]+5)
+	stop = d.StepOut()
+	asrt.Equal(t, stop.PC, symbols["main"]+7)
+
+	// breakpoints inside the subroutine still stop a step over
+	d.Cpu.ProgramCounter = go6502.Register16(symbols["main"] + 4)
+	d.AddBreakpoint(symbols["inner"], "")
+	stop = d.StepOver()
+	asrt.Equal(t, stop.Reason, BreakpointHit)
+}
+
+func TestRunToCycle(t *testing.T) {
+	d, _ := newDebugger(t)
+
+	stop := d.RunToCycle(10)
+	asrt.Equal(t, stop.Reason, CycleReached)
+	asrt.True(t, d.Cpu.Cycle >= 10 && d.Cpu.Cycle < 16)
+
+	stop = d.RunToCycle(5)
+	asrt.Equal(t, stop.Reason, CycleReached)
+}
+
+func TestPause(t *testing.T) {
+	memory := &go6502.Mem{go6502.JMP_ABS, 0x00, 0x00}
+	d := New(&go6502.Cpu{}, memory)
+	d.AddBreakpoint(0x0000, "CYC > 300")
+
+	stop := d.Continue()
+	asrt.Equal(t, stop.Reason, BreakpointHit)
+
+	done := make(chan Stop)
+	d.Remove(1)
+	go func() { done <- d.Continue() }()
+	for {
+		d.Pause()
+		select {
+		case stop := <-done:
+			asrt.Equal(t, stop.Reason, Paused)
+			return
+		default:
+		}
+	}
+}
+
+func TestFault(t *testing.T) {
+	memory := &go6502.Mem{0x02}
+	d := New(&go6502.Cpu{}, memory)
+
+	stop := d.Step()
+	asrt.Equal(t, stop.Reason, Faulted)
+	asrt.True(t, stop.Err != nil)
+}
+
+func TestDisassemble(t *testing.T) {
+	d, _ := newDebugger(t)
+	lines := d.Disassemble(0x0600, 3)
+	asrt.Equal(t, lines[2].Text(), "JSR $0610")
+	asrt.Equal(t, d.Memory(0x0600, 2)[1], uint8(0x00))
+}
+
+func TestConditions(t *testing.T) {
+	cpu := &go6502.Cpu{}
+	cpu.Accumulator = 0x40
+	cpu.Status = go6502.Register8(go6502.Carry)
+	memory := &go6502.Mem{}
+	memory[0x0200] = 7
+
+	conditions := []struct {
+		text     string
+		expected bool
+	}{
+		{"A == $40", true},
+		{"a == 64", true},
+		{"A != %01000000", false},
+		{"C", true},
+		{"Z || [$0200] >= 7", true},
+		{"(A < $40 || C) && Z", false},
+		{"[$0200] <= 6", false},
+		{"PC == 0 && SP == 0", true},
+	}
+
+	for _, c := range conditions {
+		condition, err := ParseCondition(c.text)
+		if err != nil {
+			t.Errorf("%q: %v", c.text, err)
+			continue
+		}
+		if condition(cpu, memory) != c.expected {
+			t.Errorf("%q: expected %v", c.text, c.expected)
+		}
+	}
+
+	for _, text := range []string{"A ==", "B == 1", "(A == 1", "[$10", "$XY"} {
+		_, err := ParseCondition(text)
+		if err == nil {
+			t.Errorf("%q: expected an error", text)
+		}
+	}
+}
+
+func TestClearPause(t *testing.T) {
+	memory := &go6502.Mem{go6502.NOP_IMP}
+	d := New(&go6502.Cpu{}, memory)
+
+	d.Pause()
+	d.ClearPause()
+	stop := d.Step()
+	asrt.Equal(t, stop.Reason, Done)
+	asrt.Equal(t, stop.PC, uint16(0x0001))
+
+	// a command clears the pause that stopped it
+	d.Pause()
+	asrt.Equal(t, d.Step().Reason, Paused)
+	asrt.Equal(t, d.Step().Reason, Done)
+}
+
+// an interrupt serviced in place of the RTS does not end StepOut
+func TestStepOutAcrossAnInterrupt(t *testing.T) {
+	memory := &go6502.Mem{}
+	memory.WriteBytes(0x0600, []uint8{go6502.JSR_ABS, 0x10, 0x06})
+	memory.WriteBytes(0x0610, []uint8{go6502.RTS_IMP})
+	memory.WriteBytes(0x0700, []uint8{go6502.NOP_IMP, go6502.RTI_IMP})
+	memory.WriteWord(go6502.NmiVector, 0x0700)
+	cpu := &go6502.Cpu{}
+	cpu.ProgramCounter = 0x0600
+	cpu.StackPointer = 0xFF
+	d := New(cpu, memory)
+
+	d.Step()
+	cpu.SetNMI(true)
+	stop := d.StepOut()
+	asrt.Equal(t, stop.Reason, Done)
+	asrt.Equal(t, stop.PC, uint16(0x0603))
+	asrt.Equal(t, cpu.StackPointer, go6502.Register8(0xFF))
+}
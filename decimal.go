@@ -0,0 +1,82 @@
+package go6502
+
+// BCD arithmetic follows the NMOS 6502. Only the carry and the accumulator
+// are decimal results, Z always reflects the binary operation and N/V come
+// from an intermediate value after the low nibble was adjusted, which is
+// what the documented-undefined flags of the real chip look like.
+// The 65C02 fixes N and Z to match the accumulator and pays one more cycle.
+// See http://www.6502.org/tutorials/decimal_mode.html appendix A.
+
+func (c *Cpu) decimalMode() bool {
+	return c.Status.Has(Decimal) && c.Variant.HasDecimal()
+}
+
+func (c *Cpu) addDecimalToRegisterA(value uint8) {
+	a := int(c.Accumulator)
+	b := int(value)
+	carry := 0
+	if c.Status.Has(Carry) {
+		carry = 1
+	}
+
+	lo := (a & 0x0F) + (b & 0x0F) + carry
+	if lo >= 0x0A {
+		lo = ((lo + 0x06) & 0x0F) + 0x10
+	}
+
+	signed := int(int8(a&0xF0)) + int(int8(b&0xF0)) + lo
+	c.Status.Set(7, signed&0x80 != 0)
+	c.Status.Set(6, signed < -128 || signed > 127)
+
+	sum := (a & 0xF0) + (b & 0xF0) + lo
+	if sum >= 0xA0 {
+		sum += 0x60
+	}
+	c.Status.Set(0, sum >= 0x100)
+	c.Status.Set(1, uint8(a+b+carry) == 0)
+
+	c.Accumulator = Register8(sum)
+	if c.Variant.IsCmos() {
+		c.updateZeroAndNegativeFlags(c.Accumulator)
+		c.extraCycle = true
+	}
+}
+
+// flags of a decimal subtraction are the ones of the binary subtraction
+func (c *Cpu) subDecimalFromRegisterA(value uint8) {
+	a := int(c.Accumulator)
+	b := int(value)
+	borrow := 1
+	if c.Status.Has(Carry) {
+		borrow = 0
+	}
+
+	lo := (a & 0x0F) - (b & 0x0F) - borrow
+	if lo < 0 {
+		lo = ((lo - 0x06) & 0x0F) - 0x10
+	}
+
+	diff := (a & 0xF0) - (b & 0xF0) + lo
+	if diff < 0 {
+		diff -= 0x60
+	}
+
+	if c.Variant.IsCmos() {
+		diff = a - b - borrow
+		if diff < 0 {
+			diff -= 0x60
+		}
+		if lo < 0 {
+			diff -= 0x06
+		}
+	}
+
+	c.addToRegisterA(^value)
+	c.updateZeroAndNegativeFlags(c.Accumulator)
+	c.Accumulator = Register8(diff)
+
+	if c.Variant.IsCmos() {
+		c.updateZeroAndNegativeFlags(c.Accumulator)
+		c.extraCycle = true
+	}
+}
@@ -0,0 +1,205 @@
+// Package disasm turns 6502 machine code into assembly text, decoding it
+// with the opcode tables of go6502.
+package disasm
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/zehlt/go6502"
+)
+
+// Line is one decoded instruction. Target is the address a branch or a
+// jump goes to, HasTarget tells whether there is one.
+type Line struct {
+	Addr      uint16
+	Bytes     []uint8
+	Mnemonic  string
+	Operand   string
+	Label     string
+	Target    uint16
+	HasTarget bool
+}
+
+// String renders the line as address, raw bytes and instruction,
+// like "0600  A9 23     LDA #$23".
+func (l Line) String() string {
+	hex := make([]string, len(l.Bytes))
+	for i, b := range l.Bytes {
+		hex[i] = fmt.Sprintf("%02X", b)
+	}
+	return fmt.Sprintf("%04X  %-8s  %s", l.Addr, strings.Join(hex, " "), l.Text())
+}
+
+// Text is the instruction alone, like "LDA #$23".
+func (l Line) Text() string {
+	if l.Operand == "" {
+		return l.Mnemonic
+	}
+	return l.Mnemonic + " " + l.Operand
+}
+
+// Disassembler decodes with Opcodes, go6502.Opcodes when nil. Addresses
+// found in Symbols are rendered with their name instead, and the line at
+// such an address gets it as Label.
+type Disassembler struct {
+	Opcodes map[uint8]go6502.Opcode
+	Symbols map[uint16]string
+}
+
+// New returns a disassembler for the opcodes of the variant.
+func New(variant go6502.Variant) *Disassembler {
+	return &Disassembler{Opcodes: variant.Opcodes()}
+}
+
+func (d *Disassembler) opcodes() map[uint8]go6502.Opcode {
+	if d.Opcodes == nil {
+		return go6502.Opcodes
+	}
+	return d.Opcodes
+}
+
+// Instruction decodes the instruction at addr, read with go6502.Peek.
+// Opcodes missing from the table are rendered as a .byte directive.
+func (d *Disassembler) Instruction(bus go6502.Bus, addr uint16) Line {
+	code := go6502.Peek(bus, addr)
+	line := Line{Addr: addr, Label: d.Symbols[addr]}
+
+	opc, ok := d.opcodes()[code]
+	if !ok {
+		line.Bytes = []uint8{code}
+		line.Mnemonic = ".byte"
+		line.Operand = fmt.Sprintf("$%02X", code)
+		return line
+	}
+
+	line.Bytes = make([]uint8, opc.ByteSize)
+	for i := range line.Bytes {
+		line.Bytes[i] = go6502.Peek(bus, addr+uint16(i))
+	}
+	line.Mnemonic = opc.Mnemonic
+	d.decodeOperand(&line, opc.Mode)
+	return line
+}
+
+func (d *Disassembler) decodeOperand(line *Line, mode int) {
+	var byte1, word uint16
+	if len(line.Bytes) > 1 {
+		byte1 = uint16(line.Bytes[1])
+		word = byte1
+	}
+	if len(line.Bytes) > 2 {
+		word |= uint16(line.Bytes[2]) << 8
+	}
+	next := line.Addr + uint16(len(line.Bytes))
+
+	switch mode {
+	case go6502.Implied:
+	case go6502.Accumulator:
+		line.Operand = "A"
+	case go6502.Immediate:
+		line.Operand = fmt.Sprintf("#$%02X", byte1)
+	case go6502.ZeroPage:
+		line.Operand = d.zeroPage(byte1)
+	case go6502.ZeroPageX:
+		line.Operand = d.zeroPage(byte1) + ",X"
+	case go6502.ZeroPageY:
+		line.Operand = d.zeroPage(byte1) + ",Y"
+	case go6502.Absolute:
+		line.Operand = d.absolute(word)
+		if line.Mnemonic == "JMP" || line.Mnemonic == "JSR" {
+			line.Target, line.HasTarget = word, true
+		}
+	case go6502.AbsoluteX, go6502.AbsoluteX1:
+		line.Operand = d.absolute(word) + ",X"
+	case go6502.AbsoluteY, go6502.AbsoluteY1:
+		line.Operand = d.absolute(word) + ",Y"
+	case go6502.Indirect:
+		line.Operand = "(" + d.absolute(word) + ")"
+	case go6502.AbsoluteIndirectX:
+		line.Operand = "(" + d.absolute(word) + ",X)"
+	case go6502.IndirectX:
+		line.Operand = "(" + d.zeroPage(byte1) + ",X)"
+	case go6502.IndirectY, go6502.IndirectY1:
+		line.Operand = "(" + d.zeroPage(byte1) + "),Y"
+	case go6502.IndirectZeroPage:
+		line.Operand = "(" + d.zeroPage(byte1) + ")"
+	case go6502.Relative:
+		line.Target, line.HasTarget = next+uint16(int8(byte1)), true
+		line.Operand = d.absolute(line.Target)
+	case go6502.ZeroPageRelative:
+		line.Target, line.HasTarget = next+uint16(int8(word>>8)), true
+		line.Operand = d.zeroPage(byte1) + "," + d.absolute(line.Target)
+	}
+}
+
+func (d *Disassembler) zeroPage(addr uint16) string {
+	if name, ok := d.Symbols[addr]; ok {
+		return name
+	}
+	return fmt.Sprintf("$%02X", addr)
+}
+
+func (d *Disassembler) absolute(addr uint16) string {
+	if name, ok := d.Symbols[addr]; ok {
+		return name
+	}
+	return fmt.Sprintf("$%04X", addr)
+}
+
+// Range decodes the instructions starting between start and end, end
+// excluded. The last instruction may extend past end.
+func (d *Disassembler) Range(bus go6502.Bus, start, end uint16) []Line {
+	var lines []Line
+	for addr := int(start); addr < int(end); {
+		line := d.Instruction(bus, uint16(addr))
+		lines = append(lines, line)
+		addr += len(line.Bytes)
+	}
+	return lines
+}
+
+// Bytes decodes code loaded at origin. An instruction cut by the end of
+// the slice is rendered as .byte directives.
+func (d *Disassembler) Bytes(code []uint8, origin uint16) []Line {
+	bus := sliceBus{code: code, origin: origin}
+	var lines []Line
+	for offset := 0; offset < len(code); {
+		line := d.Instruction(bus, origin+uint16(offset))
+		if offset+len(line.Bytes) > len(code) {
+			line = Line{
+				Addr:     line.Addr,
+				Bytes:    []uint8{code[offset]},
+				Mnemonic: ".byte",
+				Operand:  fmt.Sprintf("$%02X", code[offset]),
+				Label:    line.Label,
+			}
+		}
+		lines = append(lines, line)
+		offset += len(line.Bytes)
+	}
+	return lines
+}
+
+// sliceBus exposes a byte slice as a read only bus, out of range reads
+// return zero
+type sliceBus struct {
+	code   []uint8
+	origin uint16
+}
+
+func (b sliceBus) Read(addr uint16) uint8 {
+	offset := int(addr - b.origin)
+	if offset >= len(b.code) {
+		return 0
+	}
+	return b.code[offset]
+}
+
+func (b sliceBus) Write(addr uint16, data uint8) {}
+
+func (b sliceBus) ReadWord(addr uint16) uint16 {
+	return uint16(b.Read(addr+1))<<8 | uint16(b.Read(addr))
+}
+
+func (b sliceBus) WriteWord(addr uint16, data uint16) {}
@@ -0,0 +1,107 @@
+package disasm
+
+import (
+	"testing"
+
+	"github.com/zehlt/go6502"
+	"github.com/zehlt/go6502/asrt"
+)
+
+func TestAddressingModes(t *testing.T) {
+	code := []uint8{
+		go6502.LDA_IMM, 0x23,
+		go6502.STA_ZER, 0x10,
+		go6502.LDX_ZRY, 0x10,
+		go6502.LDA_ABX, 0x00, 0x20,
+		go6502.JMP_IND, 0xFC, 0xFF,
+		go6502.LDA_IDX, 0x40,
+		go6502.STA_IDY, 0x40,
+		go6502.ASL_ACC,
+		go6502.BRK_IMP,
+	}
+
+	var d Disassembler
+	lines := d.Bytes(code, 0x0600)
+
+	expected := []string{
+		"LDA #$23",
+		"STA $10",
+		"LDX $10,Y",
+		"LDA $2000,X",
+		"JMP ($FFFC)",
+		"LDA ($40,X)",
+		"STA ($40),Y",
+		"ASL A",
+		"BRK",
+	}
+	asrt.Equal(t, len(lines), len(expected))
+	for i, text := range expected {
+		asrt.Equal(t, lines[i].Text(), text)
+	}
+	asrt.Equal(t, lines[3].String(), "0606  BD 00 20  LDA $2000,X")
+}
+
+func TestBranchTargets(t *testing.T) {
+	code := []uint8{
+		go6502.BNE_REL, 0xFE,
+		go6502.BEQ_REL, 0x10,
+		go6502.JSR_ABS, 0x00, 0x80,
+	}
+
+	var d Disassembler
+	lines := d.Bytes(code, 0x0600)
+
+	asrt.Equal(t, lines[0].Text(), "BNE $0600")
+	asrt.Equal(t, lines[0].Target, uint16(0x0600))
+	asrt.Equal(t, lines[1].Text(), "BEQ $0614")
+	asrt.True(t, lines[1].HasTarget)
+	asrt.Equal(t, lines[2].Target, uint16(0x8000))
+}
+
+func TestSymbols(t *testing.T) {
+	code := []uint8{
+		go6502.LDA_ZER, 0x10,
+		go6502.BNE_REL, 0xFC,
+		go6502.JSR_ABS, 0xEE, 0xFF,
+	}
+
+	d := Disassembler{Symbols: map[uint16]string{
+		0x0010: "counter",
+		0x0600: "loop",
+		0xFFEE: "CHROUT",
+	}}
+	lines := d.Bytes(code, 0x0600)
+
+	asrt.Equal(t, lines[0].Label, "loop")
+	asrt.Equal(t, lines[0].Text(), "LDA counter")
+	asrt.Equal(t, lines[1].Text(), "BNE loop")
+	asrt.Equal(t, lines[2].Text(), "JSR CHROUT")
+}
+
+func TestTruncatedInstruction(t *testing.T) {
+	var d Disassembler
+	lines := d.Bytes([]uint8{go6502.NOP_IMP, go6502.LDA_ABS, 0x00}, 0x0000)
+
+	asrt.Equal(t, len(lines), 3)
+	asrt.Equal(t, lines[1].Text(), ".byte $AD")
+	asrt.Equal(t, lines[2].Text(), "BRK")
+}
+
+func TestRangeOverBus(t *testing.T) {
+	memory := go6502.Mem{go6502.INX_IMP, go6502.STA_ABS, 0x00, 0x02, go6502.RTS_IMP}
+
+	var d Disassembler
+	lines := d.Range(&memory, 0x0000, 0x0005)
+	asrt.Equal(t, len(lines), 3)
+	asrt.Equal(t, lines[1].Text(), "STA $0200")
+	asrt.Equal(t, lines[2].Addr, uint16(0x0004))
+}
+
+func TestCmosVariant(t *testing.T) {
+	d := New(go6502.WDC65C02)
+	lines := d.Bytes([]uint8{go6502.LDA_IZP, 0x20, go6502.BBR3_ZRL, 0x10, 0xFD, go6502.STP_IMP}, 0x0200)
+
+	asrt.Equal(t, lines[0].Text(), "LDA ($20)")
+	asrt.Equal(t, lines[1].Text(), "BBR3 $10,$0202")
+	asrt.Equal(t, lines[2].Text(), "STP")
+}
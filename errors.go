@@ -0,0 +1,30 @@
+package go6502
+
+import (
+	"errors"
+	"fmt"
+)
+
+var (
+	ErrUnknownOpcode   = errors.New("unknown opcode")
+	ErrUnsupportedMode = errors.New("unsupported addressing mode")
+	ErrJammed          = errors.New("cpu jammed")
+)
+
+// OpcodeError is returned by Step and Run when an instruction cannot be
+// executed. PC is the address of the opcode and Cycle the cycle count when
+// the fault happened. Err is one of the Err* values above.
+type OpcodeError struct {
+	PC     uint16
+	Opcode uint8
+	Cycle  int
+	Err    error
+}
+
+func (e *OpcodeError) Error() string {
+	return fmt.Sprintf("go6502: %v $%02X at $%04X (cycle %d)", e.Err, e.Opcode, e.PC, e.Cycle)
+}
+
+func (e *OpcodeError) Unwrap() error {
+	return e.Err
+}
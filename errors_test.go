@@ -0,0 +1,52 @@
+package go6502
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/zehlt/go6502/asrt"
+)
+
+func TestJamReturnsOpcodeError(t *testing.T) {
+	memory := Mem{
+		NOP_IMP, JAM_IMP_02, NOP_IMP,
+	}
+
+	cpu := Cpu{}
+	err := cpu.Run(BusEx{&memory})
+
+	var opcErr *OpcodeError
+	asrt.True(t, errors.As(err, &opcErr))
+	asrt.True(t, errors.Is(err, ErrJammed))
+	asrt.Equal(t, opcErr.PC, uint16(0x0001))
+	asrt.Equal(t, opcErr.Opcode, uint8(JAM_IMP_02))
+	asrt.Equal(t, opcErr.Cycle, Opcodes[NOP_IMP].Cycles+Opcodes[JAM_IMP_02].Cycles)
+	asrt.Equal(t, cpu.ProgramCounter, Register16(0x0001))
+	asrt.False(t, cpu.Halted())
+}
+
+func TestJamHaltsWhenHaltOnJam(t *testing.T) {
+	memory := Mem{
+		JAM_IMP_12, NOP_IMP,
+	}
+
+	cpu := Cpu{HaltOnJam: true}
+	asrt.Equal(t, cpu.Step(BusEx{&memory}), nil)
+	asrt.Equal(t, cpu.Step(BusEx{&memory}), nil)
+
+	asrt.True(t, cpu.Halted())
+	asrt.Equal(t, cpu.ProgramCounter, Register16(0x0000))
+	asrt.Equal(t, cpu.Cycle, Opcodes[JAM_IMP_12].Cycles)
+
+	memory[0x0000] = NOP_IMP
+	cpu.Reset(BusEx{&memory})
+	asrt.False(t, cpu.Halted())
+	asrt.Equal(t, cpu.Step(BusEx{&memory}), nil)
+	asrt.Equal(t, cpu.ProgramCounter, Register16(0x0001))
+}
+
+func TestOpcodeErrorMessage(t *testing.T) {
+	err := &OpcodeError{PC: 0x1234, Opcode: 0x02, Cycle: 42, Err: ErrJammed}
+
+	asrt.Equal(t, err.Error(), "go6502: cpu jammed $02 at $1234 (cycle 42)")
+}
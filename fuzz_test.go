@@ -0,0 +1,69 @@
+package go6502
+
+import (
+	"fmt"
+	"testing"
+)
+
+// FuzzStep executes one official opcode from a random state, registers,
+// program counter and memory, on the cpu and on reference, then compares
+// the registers, the flags and the whole memory. The memory repeats fill.
+// The fuzzer minimises a divergence before writing it in
+// testdata/fuzz/FuzzStep, where go test replays it:
+//
+//	go test -run XXX -fuzz FuzzStep
+func FuzzStep(f *testing.F) {
+	f.Add(uint8(ADC_IMM), uint8(0x79), uint8(0x00), uint8(0x01), uint8(0), uint8(0), uint8(Decimal), uint8(0xFF), uint16(0x0600), []byte{0})
+	f.Add(uint8(SBC_IMM), uint8(0x01), uint8(0x00), uint8(0x80), uint8(0), uint8(0), uint8(Carry), uint8(0xFF), uint16(0x0600), []byte{0})
+	f.Add(uint8(JMP_IND), uint8(0xFF), uint8(0x02), uint8(0), uint8(0), uint8(0), uint8(0), uint8(0xFF), uint16(0x0600), []byte{0x12, 0x34, 0x56})
+	f.Add(uint8(JSR_ABS), uint8(0x00), uint8(0x80), uint8(0), uint8(0), uint8(0), uint8(0), uint8(0x00), uint16(0xFFFE), []byte{0})
+	f.Add(uint8(LDA_IDY), uint8(0xFF), uint8(0x00), uint8(0), uint8(0), uint8(0x80), uint8(0), uint8(0xFF), uint16(0x0600), []byte{0xFF, 0x01})
+
+	f.Fuzz(func(t *testing.T, opcode, lo, hi, a, x, y, p, s uint8, pc uint16, fill []byte) {
+		if _, ok := referenceOpcodes[opcode]; !ok {
+			return
+		}
+		memory := &Mem{}
+		for i := 0; len(fill) > 0 && i < len(memory); i++ {
+			memory[i] = fill[i%len(fill)]
+		}
+		memory[pc], memory[pc+1], memory[pc+2] = opcode, lo, hi
+
+		expected := *memory
+		ref := reference{a: a, x: x, y: y, s: s, p: p, pc: pc, mem: &expected}
+		ref.step()
+
+		cpu := Cpu{}
+		cpu.Accumulator = Register8(a)
+		cpu.XIndex = Register8(x)
+		cpu.YIndex = Register8(y)
+		cpu.StackPointer = Register8(s)
+		cpu.Status = Register8(p)
+		cpu.ProgramCounter = Register16(pc)
+		if err := cpu.Step(memory); err != nil {
+			t.Fatal(err)
+		}
+
+		var diffs []string
+		compare := func(name string, got, want int) {
+			if got != want {
+				diffs = append(diffs, fmt.Sprintf("%s %02X, expected %02X", name, got, want))
+			}
+		}
+		compare("PC", int(cpu.ProgramCounter), int(ref.pc))
+		compare("A", int(cpu.Accumulator), int(ref.a))
+		compare("X", int(cpu.XIndex), int(ref.x))
+		compare("Y", int(cpu.YIndex), int(ref.y))
+		compare("SP", int(cpu.StackPointer), int(ref.s))
+		compare("P", int(uint8(cpu.Status)&statusMask), int(ref.p&statusMask))
+		if *memory != expected {
+			for addr := range memory {
+				compare(fmt.Sprintf("$%04X", addr), int(memory[addr]), int(expected[addr]))
+			}
+		}
+		if len(diffs) > 0 {
+			t.Errorf("%s $%02X %02X at $%04X, A:%02X X:%02X Y:%02X P:%02X SP:%02X: %v",
+				referenceOpcodes[opcode].name, lo, hi, pc, a, x, y, p, s, diffs)
+		}
+	})
+}
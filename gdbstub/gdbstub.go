@@ -0,0 +1,581 @@
+// Package gdbstub serves a cpu over the GDB remote serial protocol, so that
+// gdb-multiarch or an IDE frontend can debug the guest code:
+//
+//	d := debug.New(&cpu, &memory)
+//	log.Fatal(gdbstub.NewServer(d).ListenAndServe("localhost:1234"))
+//
+// and in gdb, "target remote localhost:1234". The registers are a, x, y,
+// p and sp, 8 bits each, then the 16 bits pc, described to the client by
+// target.xml. Memory accesses go to the bus of the debugger without
+// triggering its watchpoints. Software and hardware breakpoints, write,
+// read and access watchpoints, step, continue and the interrupt of a
+// running program are supported.
+package gdbstub
+
+import (
+	"bufio"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+
+	"github.com/zehlt/go6502"
+	"github.com/zehlt/go6502/debug"
+)
+
+const targetXML = `<?xml version="1.0"?>
+<!DOCTYPE target SYSTEM "gdb-target.dtd">
+<target version="1.0">
+  <feature name="org.gnu.gdb.m6502.core">
+    <reg name="a" bitsize="8" type="uint8"/>
+    <reg name="x" bitsize="8" type="uint8"/>
+    <reg name="y" bitsize="8" type="uint8"/>
+    <reg name="p" bitsize="8" type="uint8"/>
+    <reg name="sp" bitsize="8" type="uint8"/>
+    <reg name="pc" bitsize="16" type="code_ptr"/>
+  </feature>
+</target>
+`
+
+// the signals of the stop replies
+const (
+	sigint  = 2
+	sigill  = 4
+	sigtrap = 5
+)
+
+// Server serves the debugger to one client at a time.
+type Server struct {
+	Debugger *debug.Debugger
+}
+
+// NewServer returns a server for d.
+func NewServer(d *debug.Debugger) *Server {
+	return &Server{Debugger: d}
+}
+
+// ListenAndServe listens on the TCP address addr and serves the clients
+// one after the other.
+func (s *Server) ListenAndServe(addr string) error {
+	l, err := net.Listen("tcp", addr)
+	if err != nil {
+		return err
+	}
+	defer l.Close()
+	return s.Serve(l)
+}
+
+// Serve accepts the connections of l and serves them one after the other,
+// it returns when l fails.
+func (s *Server) Serve(l net.Listener) error {
+	for {
+		conn, err := l.Accept()
+		if err != nil {
+			return err
+		}
+		s.ServeConn(conn)
+		conn.Close()
+	}
+}
+
+// ServeConn runs a session until the client detaches, kills the target or
+// closes the connection.
+func (s *Server) ServeConn(conn io.ReadWriter) error {
+	sess := &session{
+		d:           s.Debugger,
+		w:           bufio.NewWriter(conn),
+		events:      make(chan event),
+		done:        make(chan struct{}),
+		breakpoints: map[string]int{},
+	}
+	defer close(sess.done)
+	defer sess.clearBreakpoints()
+	go sess.read(bufio.NewReader(conn))
+	return sess.serve()
+}
+
+// event is a packet of the client, or its interrupt request
+type event struct {
+	packet    string
+	interrupt bool
+	err       error
+}
+
+type session struct {
+	d      *debug.Debugger
+	w      *bufio.Writer
+	events chan event
+	// closed when the session ends, the reader stops with it
+	done  chan struct{}
+	noAck bool
+	// the packets received while the target ran, handled once it stops
+	pending []event
+	// the ids of the debugger breakpoints and watchpoints, by Z packet
+	breakpoints map[string]int
+}
+
+var (
+	errDetached = errors.New("detached")
+	errKilled   = errors.New("killed")
+)
+
+// read turns the input stream into events until it fails
+func (s *session) read(r *bufio.Reader) {
+	defer close(s.events)
+	for {
+		c, err := r.ReadByte()
+		if err != nil {
+			s.emit(event{err: err})
+			return
+		}
+		switch c {
+		case 0x03:
+			if !s.emit(event{interrupt: true}) {
+				return
+			}
+		case '$':
+			ev, err := readPacket(r)
+			if err != nil {
+				s.emit(event{err: err})
+				return
+			}
+			if !s.emit(ev) {
+				return
+			}
+		}
+		// the acknowledgments of the client are not checked
+	}
+}
+
+// emit reports false once the session is over
+func (s *session) emit(ev event) bool {
+	select {
+	case s.events <- ev:
+		return true
+	case <-s.done:
+		return false
+	}
+}
+
+// readPacket reads the data and the checksum following a $
+func readPacket(r *bufio.Reader) (event, error) {
+	data, err := r.ReadString('#')
+	if err != nil {
+		return event{}, err
+	}
+	sum := make([]byte, 2)
+	if _, err := io.ReadFull(r, sum); err != nil {
+		return event{}, err
+	}
+	data = data[:len(data)-1]
+	expected, err := strconv.ParseUint(string(sum), 16, 8)
+	if err != nil || uint8(expected) != checksum(data) {
+		return event{err: errBadChecksum}, nil
+	}
+	return event{packet: data}, nil
+}
+
+var errBadChecksum = errors.New("bad checksum")
+
+func checksum(data string) uint8 {
+	var sum uint8
+	for i := 0; i < len(data); i++ {
+		sum += data[i]
+	}
+	return sum
+}
+
+// next returns the next event, the pending ones first
+func (s *session) next() (event, bool) {
+	if len(s.pending) > 0 {
+		ev := s.pending[0]
+		s.pending = s.pending[1:]
+		return ev, true
+	}
+	ev, ok := <-s.events
+	return ev, ok
+}
+
+func (s *session) serve() error {
+	for {
+		ev, ok := s.next()
+		if !ok {
+			return nil
+		}
+		if ev.err == errBadChecksum {
+			if err := s.ack('-'); err != nil {
+				return err
+			}
+			continue
+		}
+		if ev.err != nil {
+			if ev.err == io.EOF {
+				return nil
+			}
+			return ev.err
+		}
+		if ev.interrupt {
+			continue
+		}
+		if err := s.ack('+'); err != nil {
+			return err
+		}
+
+		reply, err := s.handle(ev.packet)
+		switch err {
+		case nil:
+		case errDetached:
+			return s.send(reply)
+		case errKilled, io.EOF:
+			return nil
+		default:
+			return err
+		}
+		if err := s.send(reply); err != nil {
+			return err
+		}
+	}
+}
+
+func (s *session) ack(c byte) error {
+	if s.noAck {
+		return nil
+	}
+	if err := s.w.WriteByte(c); err != nil {
+		return err
+	}
+	return s.w.Flush()
+}
+
+func (s *session) send(data string) error {
+	fmt.Fprintf(s.w, "$%s#%02x", data, checksum(data))
+	return s.w.Flush()
+}
+
+// handle returns the reply to a packet
+func (s *session) handle(packet string) (string, error) {
+	if packet == "" {
+		return "", nil
+	}
+	args := packet[1:]
+
+	switch packet[0] {
+	case '?':
+		return fmt.Sprintf("S%02x", sigtrap), nil
+	case 'g':
+		return s.readRegisters(), nil
+	case 'G':
+		return s.writeRegisters(args), nil
+	case 'p':
+		return s.readRegister(args), nil
+	case 'P':
+		return s.writeRegister(args), nil
+	case 'm':
+		return s.readMemory(args), nil
+	case 'M':
+		return s.writeMemory(args), nil
+	case 'Z', 'z':
+		return s.breakpoint(packet[0] == 'Z', args), nil
+	case 's':
+		return s.resume(args, s.d.Step)
+	case 'c':
+		return s.resume(args, s.d.Continue)
+	case 'v':
+		return s.vPacket(args)
+	case 'q':
+		return s.query(args), nil
+	case 'Q':
+		if args == "StartNoAckMode" {
+			s.noAck = true
+			return "OK", nil
+		}
+		return "", nil
+	case 'H':
+		return "OK", nil
+	case 'D':
+		return "OK", errDetached
+	case 'k':
+		return "", errKilled
+	}
+	return "", nil
+}
+
+func (s *session) query(args string) string {
+	switch {
+	case strings.HasPrefix(args, "Supported"):
+		return "PacketSize=4000;qXfer:features:read+;QStartNoAckMode+;swbreak+;hwbreak+;vContSupported+"
+	case args == "Attached":
+		return "1"
+	case args == "C":
+		return "QC1"
+	case args == "fThreadInfo":
+		return "m1"
+	case args == "sThreadInfo":
+		return "l"
+	case strings.HasPrefix(args, "Xfer:features:read:target.xml:"):
+		var offset, length int
+		if _, err := fmt.Sscanf(strings.TrimPrefix(args, "Xfer:features:read:target.xml:"), "%x,%x", &offset, &length); err != nil {
+			return "E01"
+		}
+		if offset >= len(targetXML) {
+			return "l"
+		}
+		end := offset + length
+		if end >= len(targetXML) {
+			return "l" + targetXML[offset:]
+		}
+		return "m" + targetXML[offset:end]
+	}
+	return ""
+}
+
+func (s *session) vPacket(args string) (string, error) {
+	switch {
+	case args == "Cont?":
+		return "vCont;c;C;s;S", nil
+	case strings.HasPrefix(args, "Cont;"):
+		// a single thread, the first action applies
+		action := strings.SplitN(strings.TrimPrefix(args, "Cont;"), ";", 2)[0]
+		if action == "" {
+			return "E01", nil
+		}
+		switch action[0] {
+		case 's', 'S':
+			return s.resume("", s.d.Step)
+		case 'c', 'C':
+			return s.resume("", s.d.Continue)
+		}
+		return "E01", nil
+	case args == "MustReplyEmpty":
+		return "", nil
+	}
+	return "", nil
+}
+
+// registers in the order of target.xml
+func (s *session) registerBytes() []byte {
+	c := s.d.Cpu
+	pc := uint16(c.ProgramCounter)
+	return []byte{
+		uint8(c.Accumulator), uint8(c.XIndex), uint8(c.YIndex),
+		uint8(c.Status), uint8(c.StackPointer), uint8(pc), uint8(pc >> 8),
+	}
+}
+
+func (s *session) setRegisterBytes(b []byte) {
+	s.d.Modified()
+	c := s.d.Cpu
+	c.Accumulator = go6502.Register8(b[0])
+	c.XIndex = go6502.Register8(b[1])
+	c.YIndex = go6502.Register8(b[2])
+	c.Status = go6502.Register8(b[3])
+	c.StackPointer = go6502.Register8(b[4])
+	c.ProgramCounter = go6502.Register16(uint16(b[5]) | uint16(b[6])<<8)
+}
+
+// the offset and the size of each register in registerBytes
+var registerLayout = [][2]int{{0, 1}, {1, 1}, {2, 1}, {3, 1}, {4, 1}, {5, 2}}
+
+func (s *session) readRegisters() string {
+	return hex.EncodeToString(s.registerBytes())
+}
+
+func (s *session) writeRegisters(args string) string {
+	b, err := hex.DecodeString(args)
+	if err != nil || len(b) != 7 {
+		return "E01"
+	}
+	s.setRegisterBytes(b)
+	return "OK"
+}
+
+func (s *session) readRegister(args string) string {
+	n, err := strconv.ParseUint(args, 16, 8)
+	if err != nil || int(n) >= len(registerLayout) {
+		return "E01"
+	}
+	r := registerLayout[n]
+	return hex.EncodeToString(s.registerBytes()[r[0] : r[0]+r[1]])
+}
+
+func (s *session) writeRegister(args string) string {
+	parts := strings.SplitN(args, "=", 2)
+	if len(parts) != 2 {
+		return "E01"
+	}
+	n, err := strconv.ParseUint(parts[0], 16, 8)
+	if err != nil || int(n) >= len(registerLayout) {
+		return "E01"
+	}
+	value, err := hex.DecodeString(parts[1])
+	r := registerLayout[n]
+	if err != nil || len(value) != r[1] {
+		return "E01"
+	}
+	b := s.registerBytes()
+	copy(b[r[0]:], value)
+	s.setRegisterBytes(b)
+	return "OK"
+}
+
+func parseAddrLength(text string) (uint16, int, error) {
+	parts := strings.SplitN(text, ",", 2)
+	if len(parts) != 2 {
+		return 0, 0, fmt.Errorf("bad range %q", text)
+	}
+	addr, err := strconv.ParseUint(parts[0], 16, 16)
+	if err != nil {
+		return 0, 0, err
+	}
+	length, err := strconv.ParseUint(parts[1], 16, 32)
+	if err != nil || length > 0x10000 {
+		return 0, 0, fmt.Errorf("bad length %q", parts[1])
+	}
+	return uint16(addr), int(length), nil
+}
+
+func (s *session) readMemory(args string) string {
+	addr, length, err := parseAddrLength(args)
+	if err != nil {
+		return "E01"
+	}
+	return hex.EncodeToString(s.d.Memory(addr, length))
+}
+
+func (s *session) writeMemory(args string) string {
+	parts := strings.SplitN(args, ":", 2)
+	if len(parts) != 2 {
+		return "E01"
+	}
+	addr, length, err := parseAddrLength(parts[0])
+	if err != nil {
+		return "E01"
+	}
+	data, err := hex.DecodeString(parts[1])
+	if err != nil || len(data) != length {
+		return "E01"
+	}
+	for i, b := range data {
+		s.d.Poke(addr+uint16(i), b)
+	}
+	return "OK"
+}
+
+// the access watched by each kind of Z packet
+var watchKinds = map[byte]debug.Access{'2': debug.Write, '3': debug.Read, '4': debug.ReadWrite}
+
+func (s *session) breakpoint(insert bool, args string) string {
+	parts := strings.Split(args, ",")
+	if len(parts) != 3 || len(parts[0]) != 1 {
+		return "E01"
+	}
+	addr, err := strconv.ParseUint(parts[1], 16, 16)
+	if err != nil {
+		return "E01"
+	}
+	kind := parts[0][0]
+
+	key := args
+	if !insert {
+		id, ok := s.breakpoints[key]
+		if !ok {
+			return "E01"
+		}
+		s.d.Remove(id)
+		delete(s.breakpoints, key)
+		return "OK"
+	}
+	if _, ok := s.breakpoints[key]; ok {
+		return "OK"
+	}
+
+	switch kind {
+	case '0', '1':
+		b, err := s.d.AddBreakpoint(uint16(addr), "")
+		if err != nil {
+			return "E01"
+		}
+		s.breakpoints[key] = b.ID
+	case '2', '3', '4':
+		length, err := strconv.ParseUint(parts[2], 16, 16)
+		if err != nil || length == 0 {
+			return "E01"
+		}
+		w := s.d.AddWatchpoint(uint16(addr), uint16(addr)+uint16(length-1), watchKinds[kind])
+		s.breakpoints[key] = w.ID
+	default:
+		return ""
+	}
+	return "OK"
+}
+
+// the breakpoints of a client do not outlive its session
+func (s *session) clearBreakpoints() {
+	for _, id := range s.breakpoints {
+		s.d.Remove(id)
+	}
+}
+
+// resume runs the debugger until it stops, an interrupt of the client
+// pauses it. The other packets wait for the stop reply. An interrupt that
+// comes as the run ends on its own leaves the stop as it is, SIGINT is
+// only reported when the pause stopped the run.
+func (s *session) resume(args string, run func() debug.Stop) (string, error) {
+	if args != "" {
+		addr, err := strconv.ParseUint(args, 16, 16)
+		if err != nil {
+			return "E01", nil
+		}
+		s.d.Cpu.ProgramCounter = go6502.Register16(addr)
+		s.d.Modified()
+	}
+
+	// an interrupt that came as the previous run ended does not count
+	s.d.ClearPause()
+	stops := make(chan debug.Stop, 1)
+	go func() { stops <- run() }()
+	for {
+		select {
+		case stop := <-stops:
+			return stopReply(stop), nil
+		case ev, ok := <-s.events:
+			switch {
+			case !ok || ev.err != nil && ev.err != errBadChecksum:
+				// the client is gone
+				s.d.Pause()
+				<-stops
+				return "", io.EOF
+			case ev.interrupt:
+				s.d.Pause()
+			default:
+				s.pending = append(s.pending, ev)
+			}
+		}
+	}
+}
+
+func stopReply(stop debug.Stop) string {
+	switch stop.Reason {
+	case debug.WatchpointHit:
+		kind := "awatch"
+		switch stop.Watchpoint.Access {
+		case debug.Write:
+			kind = "watch"
+		case debug.Read:
+			kind = "rwatch"
+		}
+		return fmt.Sprintf("T%02x%s:%04x;", sigtrap, kind, stop.Addr)
+	case debug.BreakpointHit:
+		return fmt.Sprintf("T%02xswbreak:;", sigtrap)
+	case debug.Paused:
+		return fmt.Sprintf("S%02x", sigint)
+	case debug.Faulted:
+		return fmt.Sprintf("S%02x", sigill)
+	case debug.Halted:
+		return "W00"
+	}
+	return fmt.Sprintf("S%02x", sigtrap)
+}
@@ -0,0 +1,188 @@
+package gdbstub
+
+import (
+	"bufio"
+	"fmt"
+	"net"
+	"strings"
+	"testing"
+
+	"github.com/zehlt/go6502"
+	"github.com/zehlt/go6502/asrt"
+	"github.com/zehlt/go6502/debug"
+)
+
+// client speaks the protocol over one end of a pipe
+type client struct {
+	t    *testing.T
+	conn net.Conn
+	r    *bufio.Reader
+}
+
+func (c *client) write(raw string) {
+	c.t.Helper()
+	if _, err := c.conn.Write([]byte(raw)); err != nil {
+		c.t.Fatal(err)
+	}
+}
+
+func (c *client) readByte() byte {
+	c.t.Helper()
+	b, err := c.r.ReadByte()
+	if err != nil {
+		c.t.Fatal(err)
+	}
+	return b
+}
+
+func (c *client) reply() string {
+	c.t.Helper()
+	asrt.Equal(c.t, c.readByte(), byte('$'))
+	data, err := c.r.ReadString('#')
+	if err != nil {
+		c.t.Fatal(err)
+	}
+	data = data[:len(data)-1]
+	sum := string([]byte{c.readByte(), c.readByte()})
+	asrt.Equal(c.t, sum, fmt.Sprintf("%02x", checksum(data)))
+	return data
+}
+
+// request sends a packet and returns the reply, acknowledged
+func (c *client) request(packet string) string {
+	c.t.Helper()
+	c.write(fmt.Sprintf("$%s#%02x", packet, checksum(packet)))
+	asrt.Equal(c.t, c.readByte(), byte('+'))
+	reply := c.reply()
+	c.write("+")
+	return reply
+}
+
+func newSession(t *testing.T, code ...uint8) (*client, *debug.Debugger, chan error) {
+	memory := &go6502.Mem{}
+	copy(memory[0x0600:], code)
+	cpu := &go6502.Cpu{Variant: go6502.WDC65C02}
+	cpu.ProgramCounter = 0x0600
+	cpu.StackPointer = 0xFF
+	d := debug.New(cpu, memory)
+
+	server, conn := net.Pipe()
+	done := make(chan error, 1)
+	go func() {
+		done <- NewServer(d).ServeConn(server)
+		server.Close()
+	}()
+	t.Cleanup(func() { conn.Close() })
+	return &client{t: t, conn: conn, r: bufio.NewReader(conn)}, d, done
+}
+
+func TestRegistersAndMemory(t *testing.T) {
+	c, d, _ := newSession(t, go6502.LDA_IMM, 0x40)
+
+	asrt.Equal(t, c.request("?"), "S05")
+	asrt.Equal(t, c.request("g"), "000000"+"00"+"ff"+"0006")
+	asrt.Equal(t, c.request("G01020324fe0007"), "OK")
+	asrt.Equal(t, d.Cpu.Accumulator, go6502.Register8(0x01))
+	asrt.Equal(t, d.Cpu.Status, go6502.Register8(0x24))
+	asrt.Equal(t, uint16(d.Cpu.ProgramCounter), uint16(0x0700))
+	asrt.Equal(t, c.request("p5"), "0007")
+	asrt.Equal(t, c.request("P5=0006"), "OK")
+	asrt.Equal(t, c.request("p0"), "01")
+
+	asrt.Equal(t, c.request("m600,2"), "a940")
+	asrt.Equal(t, c.request("M200,3:010203"), "OK")
+	asrt.Equal(t, c.request("m1ff,5"), "0001020300")
+	asrt.Equal(t, c.request("m600"), "E01")
+
+	xml := c.request("qXfer:features:read:target.xml:0,20")
+	asrt.Equal(t, xml, "m"+targetXML[:0x20])
+	xml = c.request("qXfer:features:read:target.xml:20,1000")
+	asrt.Equal(t, xml, "l"+targetXML[0x20:])
+	asrt.True(t, strings.Contains(c.request("qSupported:swbreak+"), "qXfer:features:read+"))
+	asrt.Equal(t, c.request("qUnknown"), "")
+}
+
+func TestStepContinueAndBreakpoints(t *testing.T) {
+	c, d, done := newSession(t,
+		go6502.LDX_IMM, 0x00,
+		go6502.INX_IMP,
+		go6502.STX_ABS, 0x00, 0x02,
+		go6502.CPX_IMM, 0x03,
+		go6502.BNE_REL, 0xF8,
+		go6502.STP_IMP,
+	)
+
+	asrt.Equal(t, c.request("s"), "S05")
+	asrt.Equal(t, uint16(d.Cpu.ProgramCounter), uint16(0x0602))
+
+	asrt.Equal(t, c.request("Z0,606,1"), "OK")
+	asrt.Equal(t, c.request("c"), "T05swbreak:;")
+	asrt.Equal(t, uint16(d.Cpu.ProgramCounter), uint16(0x0606))
+	asrt.Equal(t, c.request("z0,606,1"), "OK")
+	asrt.Equal(t, c.request("z0,606,1"), "E01")
+
+	asrt.Equal(t, c.request("Z2,200,1"), "OK")
+	asrt.Equal(t, c.request("vCont;c"), "T05watch:0200;")
+	asrt.Equal(t, d.Peek(0x0200), uint8(2))
+	asrt.Equal(t, c.request("z2,200,1"), "OK")
+
+	asrt.Equal(t, c.request("vCont;s:1"), "S05")
+	asrt.Equal(t, c.request("c"), "W00")
+
+	// the session ends right after the reply, it is not acknowledged
+	c.write("$D#44")
+	asrt.Equal(t, c.readByte(), byte('+'))
+	asrt.Equal(t, c.reply(), "OK")
+	asrt.Equal(t, <-done, nil)
+	asrt.Equal(t, len(d.Breakpoints()), 0)
+}
+
+func TestInterruptAndNoAck(t *testing.T) {
+	c, d, done := newSession(t, go6502.JMP_ABS, 0x00, 0x06)
+	d.AddBreakpoint(0x0600, "CYC > 1000000000")
+
+	asrt.Equal(t, c.request("QStartNoAckMode"), "OK")
+	c.write("$c#63")
+	c.write("\x03")
+	asrt.Equal(t, c.reply(), "S02")
+
+	// no acknowledgment any more
+	c.write("$?#3f")
+	asrt.Equal(t, c.reply(), "S05")
+
+	c.write("$k#6b")
+	asrt.Equal(t, <-done, nil)
+}
+
+func TestBadChecksum(t *testing.T) {
+	c, _, _ := newSession(t)
+	c.write("$?#00")
+	asrt.Equal(t, c.readByte(), byte('-'))
+	asrt.Equal(t, c.request("?"), "S05")
+}
+
+func TestPauseAfterTheRunIsForgotten(t *testing.T) {
+	c, d, _ := newSession(t, go6502.NOP_IMP, go6502.NOP_IMP)
+
+	// an interrupt that came as the previous run ended
+	d.Pause()
+	asrt.Equal(t, c.request("s"), "S05")
+	asrt.Equal(t, uint16(d.Cpu.ProgramCounter), uint16(0x0601))
+}
+
+func TestPacketsWaitForTheStop(t *testing.T) {
+	c, d, _ := newSession(t, go6502.JMP_ABS, 0x00, 0x06)
+	d.AddBreakpoint(0x0600, "CYC > 1000000000")
+
+	c.write("$c#63")
+	asrt.Equal(t, c.readByte(), byte('+'))
+	c.write("$?#3f")
+	c.write("\x03")
+	asrt.Equal(t, c.reply(), "S02")
+	c.write("+")
+
+	// the packet sent while running is answered after the stop
+	asrt.Equal(t, c.readByte(), byte('+'))
+	asrt.Equal(t, c.reply(), "S05")
+	c.write("+")
+}
@@ -1,3 +1,3 @@
 module github.com/zehlt/go6502
 
-go 1.17
+go 1.18
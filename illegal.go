@@ -0,0 +1,178 @@
+package go6502
+
+// Undocumented NMOS operations. Most of them are a read-modify-write
+// instruction glued to an ALU instruction sharing the same operand.
+
+func (c *Cpu) shiftLeft(value uint8, carryIn bool) uint8 {
+	c.Status.Set(0, value&0b1000_0000 != 0)
+	value <<= 1
+	if carryIn {
+		value |= 0b0000_0001
+	}
+	return value
+}
+
+func (c *Cpu) shiftRight(value uint8, carryIn bool) uint8 {
+	c.Status.Set(0, value&0b0000_0001 != 0)
+	value >>= 1
+	if carryIn {
+		value |= 0b1000_0000
+	}
+	return value
+}
+
+// skb is the multi-byte NOP, the microcode still reads its operand
+func skb(c *Cpu, value uint8) uint8 {
+	return 0
+}
+
+func slo(c *Cpu, value uint8) uint8 {
+	value = c.shiftLeft(value, false)
+	c.Accumulator |= Register8(value)
+	c.updateZeroAndNegativeFlags(c.Accumulator)
+	return value
+}
+
+func rla(c *Cpu, value uint8) uint8 {
+	value = c.shiftLeft(value, c.Status.Has(Carry))
+	c.Accumulator &= Register8(value)
+	c.updateZeroAndNegativeFlags(c.Accumulator)
+	return value
+}
+
+func sre(c *Cpu, value uint8) uint8 {
+	value = c.shiftRight(value, false)
+	c.Accumulator ^= Register8(value)
+	c.updateZeroAndNegativeFlags(c.Accumulator)
+	return value
+}
+
+func rra(c *Cpu, value uint8) uint8 {
+	value = c.shiftRight(value, c.Status.Has(Carry))
+	adc(c, value)
+	return value
+}
+
+func dcp(c *Cpu, value uint8) uint8 {
+	value--
+	c.compare(c.Accumulator, value)
+	return value
+}
+
+func isc(c *Cpu, value uint8) uint8 {
+	value++
+	sbc(c, value)
+	return value
+}
+
+func sax(c *Cpu, value uint8) uint8 {
+	return uint8(c.Accumulator & c.XIndex)
+}
+
+func lax(c *Cpu, value uint8) uint8 {
+	c.Accumulator = Register8(value)
+	c.XIndex = Register8(value)
+	c.updateZeroAndNegativeFlags(c.Accumulator)
+	return 0
+}
+
+func anc(c *Cpu, value uint8) uint8 {
+	c.Accumulator &= Register8(value)
+	c.updateZeroAndNegativeFlags(c.Accumulator)
+	c.Status.Set(0, c.Accumulator.IsNegative())
+	return 0
+}
+
+func alr(c *Cpu, value uint8) uint8 {
+	value = c.shiftRight(uint8(c.Accumulator)&value, false)
+	c.Accumulator = Register8(value)
+	c.updateZeroAndNegativeFlags(c.Accumulator)
+	return 0
+}
+
+// arr ands and rotates right, in decimal mode the result gets a BCD fixup
+// and the flags follow the NMOS oddities
+func arr(c *Cpu, operand uint8) uint8 {
+	and := uint8(c.Accumulator) & operand
+	value := and >> 1
+	if c.Status.Has(Carry) {
+		value |= 0b1000_0000
+	}
+
+	if !c.decimalMode() {
+		c.Accumulator = Register8(value)
+		c.updateZeroAndNegativeFlags(c.Accumulator)
+		c.Status.Set(0, value&0b0100_0000 != 0)
+		c.Status.Set(6, (value>>6^value>>5)&1 != 0)
+		return 0
+	}
+
+	c.updateZeroAndNegativeFlags(Register8(value))
+	c.Status.Set(6, (and^value)&0b0100_0000 != 0)
+	if (and&0x0F)+(and&0x01) > 0x05 {
+		value = (value & 0xF0) | ((value + 0x06) & 0x0F)
+	}
+	if uint16(and&0xF0)+uint16(and&0x10) > 0x50 {
+		value += 0x60
+		c.Status.Add(Carry)
+	} else {
+		c.Status.Remove(Carry)
+	}
+	c.Accumulator = Register8(value)
+	return 0
+}
+
+func sbx(c *Cpu, value uint8) uint8 {
+	and := uint8(c.Accumulator & c.XIndex)
+	c.Status.Set(0, and >= value)
+	c.XIndex = Register8(and - value)
+	c.updateZeroAndNegativeFlags(c.XIndex)
+	return 0
+}
+
+// unstableMagic stands for the analog bits the unstable immediates or into
+// the accumulator. Real chips vary between $00, $EE, $FF and temperature,
+// $EE is the value most test suites expect.
+const unstableMagic = 0xEE
+
+func xaa(c *Cpu, value uint8) uint8 {
+	c.Accumulator = (c.Accumulator | unstableMagic) & c.XIndex & Register8(value)
+	c.updateZeroAndNegativeFlags(c.Accumulator)
+	return 0
+}
+
+func lxa(c *Cpu, value uint8) uint8 {
+	c.Accumulator = (c.Accumulator | unstableMagic) & Register8(value)
+	c.XIndex = c.Accumulator
+	c.updateZeroAndNegativeFlags(c.Accumulator)
+	return 0
+}
+
+// The unstable stores return the register value, the microcode ands it
+// with the high byte of the address, see unstableFix.
+
+func ahx(c *Cpu, value uint8) uint8 {
+	return uint8(c.Accumulator & c.XIndex)
+}
+
+func tas(c *Cpu, value uint8) uint8 {
+	c.StackPointer = c.Accumulator & c.XIndex
+	return uint8(c.StackPointer)
+}
+
+func shy(c *Cpu, value uint8) uint8 {
+	return uint8(c.YIndex)
+}
+
+func shx(c *Cpu, value uint8) uint8 {
+	return uint8(c.XIndex)
+}
+
+func las(c *Cpu, operand uint8) uint8 {
+	value := Register8(operand) & c.StackPointer
+	c.Accumulator = value
+	c.XIndex = value
+	c.StackPointer = value
+	c.updateZeroAndNegativeFlags(value)
+	return 0
+}
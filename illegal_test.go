@@ -0,0 +1,191 @@
+package go6502
+
+import (
+	"testing"
+
+	"github.com/zehlt/go6502/asrt"
+)
+
+func TestOpcodesCoverEveryByte(t *testing.T) {
+	for code := 0; code < 0x100; code++ {
+		opc, ok := Opcodes[uint8(code)]
+		asrt.True(t, ok)
+		asrt.Equal(t, opc.Code, uint8(code))
+	}
+}
+
+func TestLaxZeroPage(t *testing.T) {
+	memory := Mem{
+		LAX_ZER, 0x33, BRK_IMP,
+	}
+	memory[0x33] = 0x80
+
+	cpu := Cpu{StopWhen: StopOnOpcode(BRK_IMP)}
+	cpu.Run(BusEx{&memory})
+
+	asrt.Equal(t, cpu.Accumulator, Register8(0x80))
+	asrt.Equal(t, cpu.XIndex, Register8(0x80))
+	asrt.True(t, cpu.Status.Has(Negative))
+	asrt.Equal(t, cpu.Cycle, Opcodes[LAX_ZER].Cycles)
+}
+
+func TestSaxZeroPage(t *testing.T) {
+	memory := Mem{
+		SAX_ZER, 0x33, BRK_IMP,
+	}
+
+	cpu := Cpu{StopWhen: StopOnOpcode(BRK_IMP)}
+	cpu.Accumulator = 0b1100_1100
+	cpu.XIndex = 0b1010_1010
+	cpu.Run(BusEx{&memory})
+
+	asrt.Equal(t, memory[0x33], uint8(0b1000_1000))
+	asrt.Equal(t, cpu.Cycle, Opcodes[SAX_ZER].Cycles)
+}
+
+func TestSloZeroPage(t *testing.T) {
+	memory := Mem{
+		SLO_ZER, 0x33, BRK_IMP,
+	}
+	memory[0x33] = 0x81
+
+	cpu := Cpu{StopWhen: StopOnOpcode(BRK_IMP)}
+	cpu.Accumulator = 0x10
+	cpu.Run(BusEx{&memory})
+
+	asrt.Equal(t, memory[0x33], uint8(0x02))
+	asrt.Equal(t, cpu.Accumulator, Register8(0x12))
+	asrt.True(t, cpu.Status.Has(Carry))
+	asrt.Equal(t, cpu.Cycle, Opcodes[SLO_ZER].Cycles)
+}
+
+func TestRraZeroPage(t *testing.T) {
+	memory := Mem{
+		RRA_ZER, 0x33, BRK_IMP,
+	}
+	memory[0x33] = 0x03
+
+	cpu := Cpu{StopWhen: StopOnOpcode(BRK_IMP)}
+	cpu.Accumulator = 0x10
+	cpu.Run(BusEx{&memory})
+
+	// the carry shifted out of the operand goes into the addition
+	asrt.Equal(t, memory[0x33], uint8(0x01))
+	asrt.Equal(t, cpu.Accumulator, Register8(0x12))
+	asrt.False(t, cpu.Status.Has(Carry))
+}
+
+func TestDcpZeroPage(t *testing.T) {
+	memory := Mem{
+		DCP_ZER, 0x33, BRK_IMP,
+	}
+	memory[0x33] = 0x41
+
+	cpu := Cpu{StopWhen: StopOnOpcode(BRK_IMP)}
+	cpu.Accumulator = 0x40
+	cpu.Run(BusEx{&memory})
+
+	asrt.Equal(t, memory[0x33], uint8(0x40))
+	asrt.True(t, cpu.Status.Has(Zero))
+	asrt.True(t, cpu.Status.Has(Carry))
+}
+
+func TestIscZeroPage(t *testing.T) {
+	memory := Mem{
+		ISC_ZER, 0x33, BRK_IMP,
+	}
+	memory[0x33] = 0x0F
+
+	cpu := Cpu{StopWhen: StopOnOpcode(BRK_IMP)}
+	cpu.Accumulator = 0x20
+	cpu.Status.Add(Carry)
+	cpu.Run(BusEx{&memory})
+
+	asrt.Equal(t, memory[0x33], uint8(0x10))
+	asrt.Equal(t, cpu.Accumulator, Register8(0x10))
+	asrt.True(t, cpu.Status.Has(Carry))
+}
+
+func TestAncImmediate(t *testing.T) {
+	memory := Mem{
+		ANC_IMM_2B, 0xF0, BRK_IMP,
+	}
+
+	cpu := Cpu{StopWhen: StopOnOpcode(BRK_IMP)}
+	cpu.Accumulator = 0x81
+	cpu.Run(BusEx{&memory})
+
+	asrt.Equal(t, cpu.Accumulator, Register8(0x80))
+	asrt.True(t, cpu.Status.Has(Carry))
+	asrt.True(t, cpu.Status.Has(Negative))
+}
+
+func TestArrImmediate(t *testing.T) {
+	memory := Mem{
+		ARR_IMM, 0xFF, BRK_IMP,
+	}
+
+	cpu := Cpu{StopWhen: StopOnOpcode(BRK_IMP)}
+	cpu.Accumulator = 0xC0
+	cpu.Status.Add(Carry)
+	cpu.Run(BusEx{&memory})
+
+	asrt.Equal(t, cpu.Accumulator, Register8(0xE0))
+	asrt.True(t, cpu.Status.Has(Carry))
+	asrt.False(t, cpu.Status.Has(Verflow))
+	asrt.True(t, cpu.Status.Has(Negative))
+}
+
+func TestSbxImmediate(t *testing.T) {
+	memory := Mem{
+		SBX_IMM, 0x02, BRK_IMP,
+	}
+
+	cpu := Cpu{StopWhen: StopOnOpcode(BRK_IMP)}
+	cpu.Accumulator = 0x0F
+	cpu.XIndex = 0x3C
+	cpu.Run(BusEx{&memory})
+
+	asrt.Equal(t, cpu.XIndex, Register8(0x0A))
+	asrt.True(t, cpu.Status.Has(Carry))
+}
+
+func TestShxAbsoluteYCrossedPage(t *testing.T) {
+	memory := Mem{
+		SHX_ABY, 0xF0, 0x10, BRK_IMP,
+	}
+
+	cpu := Cpu{StopWhen: StopOnOpcode(BRK_IMP)}
+	cpu.XIndex = 0x05
+	cpu.YIndex = 0x20
+	cpu.Run(BusEx{&memory})
+
+	// X & ($10+1) = $01 also becomes the high byte of the target
+	asrt.Equal(t, memory[0x0110], uint8(0x01))
+	asrt.Equal(t, memory[0x1110], uint8(0x00))
+}
+
+func TestNopAbsoluteXSkipsOperand(t *testing.T) {
+	memory := Mem{
+		NOP_ABX_1C, 0xFF, 0x00, BRK_IMP,
+	}
+
+	cpu := Cpu{StopWhen: StopOnOpcode(BRK_IMP)}
+	cpu.XIndex = 0x01
+	cpu.Run(BusEx{&memory})
+
+	asrt.Equal(t, cpu.ProgramCounter, Register16(0x0003))
+	asrt.Equal(t, cpu.Cycle, Opcodes[NOP_ABX_1C].Cycles+1)
+}
+
+func TestJamLocksTheCpu(t *testing.T) {
+	memory := Mem{
+		JAM_IMP_02, NOP_IMP,
+	}
+
+	cpu := Cpu{}
+	cpu.Step(BusEx{&memory})
+	cpu.Step(BusEx{&memory})
+
+	asrt.Equal(t, cpu.ProgramCounter, Register16(0x0000))
+}
@@ -0,0 +1,66 @@
+// Command fetchsuites downloads the test images that are not vendored into
+// a testdata directory, it is run by go generate in the root package:
+//
+//	fetchsuites testdata
+//
+// The images already present are kept. Only the functional suite of Klaus
+// Dormann is published assembled, the decimal suite has to be assembled
+// with as65 from 6502_decimal_test.a65.
+package main
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+)
+
+const klausFiles = "https://github.com/Klaus2m5/6502_65C02_functional_tests/raw/master/bin_files/"
+
+type image struct {
+	name string
+	url  string
+	size int
+}
+
+var images = []image{
+	{"6502_functional_test.bin", klausFiles + "6502_functional_test.bin", 0x10000},
+}
+
+func fetch(dir string, img image) error {
+	path := filepath.Join(dir, img.name)
+	if _, err := os.Stat(path); err == nil {
+		return nil
+	}
+
+	resp, err := http.Get(img.url)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("%s: %s", img.url, resp.Status)
+	}
+	data, err := io.ReadAll(io.LimitReader(resp.Body, int64(img.size)+1))
+	if err != nil {
+		return err
+	}
+	if len(data) != img.size {
+		return fmt.Errorf("%s: %d bytes, expected %d", img.url, len(data), img.size)
+	}
+	return os.WriteFile(path, data, 0o644)
+}
+
+func main() {
+	if len(os.Args) != 2 {
+		fmt.Fprintln(os.Stderr, "usage: fetchsuites dir")
+		os.Exit(2)
+	}
+	for _, img := range images {
+		if err := fetch(os.Args[1], img); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+	}
+}
@@ -0,0 +1,94 @@
+package go6502
+
+const (
+	NmiVector   = 0xFFFA
+	ResetVector = 0xFFFC
+	IrqVector   = 0xFFFE
+)
+
+// SetIRQ drives the IRQ line. The line is level triggered, the interrupt
+// is serviced before every instruction for as long as it stays asserted
+// and the Interrupt flag is clear.
+func (c *Cpu) SetIRQ(asserted bool) {
+	c.irqLine = asserted
+}
+
+// SetNMI drives the NMI line. The line is edge triggered, only the
+// transition from released to asserted latches an interrupt.
+func (c *Cpu) SetNMI(asserted bool) {
+	if asserted && !c.nmiLine {
+		c.nmiPending = true
+	}
+	c.nmiLine = asserted
+}
+
+// interruptRequested picks the interrupt to service instead of the next
+// opcode. The IRQ mask is the one seen before the last cycle of the
+// previous instruction, which delays CLI, SEI and PLP by one instruction.
+func (c *Cpu) interruptRequested() bool {
+	masked := c.Status.Has(Interrupt)
+	if c.iDelayed {
+		masked = c.iBefore
+	}
+	c.iDelayed = false
+
+	if c.nmiPending {
+		c.nmiPending = false
+		c.vector = NmiVector
+		return true
+	}
+
+	if c.irqLine && !masked {
+		c.vector = IrqVector
+		return true
+	}
+
+	return false
+}
+
+// the byte following BRK is skipped, the handler returns to PC+2
+func fetchBreakPadding(c *Cpu, bus Bus) {
+	c.fetch(bus)
+	c.vector = IrqVector
+}
+
+// the pushed status always has the unused bit set, the break bit is only
+// set when the interrupt comes from a BRK instruction
+func pushStatus(c *Cpu, bus Bus) {
+	flags := c.Status
+	flags.Add(Break2)
+	flags.Remove(Break)
+	c.push(bus, uint8(flags))
+}
+
+func pushStatusBreak(c *Cpu, bus Bus) {
+	flags := c.Status
+	flags.Add(Break2)
+	flags.Add(Break)
+	c.push(bus, uint8(flags))
+}
+
+// an NMI latched before the vector is read hijacks BRK and IRQ
+func readVectorLo(c *Cpu, bus Bus) {
+	if c.vector == IrqVector && c.nmiPending {
+		c.nmiPending = false
+		c.vector = NmiVector
+	}
+	c.base = uint16(bus.Read(c.vector))
+
+	c.Status.Add(Interrupt)
+	if c.Variant.IsCmos() {
+		c.Status.Remove(Decimal)
+	}
+	c.waiting = false
+}
+
+func readVectorHi(c *Cpu, bus Bus) {
+	hi := uint16(bus.Read(c.vector + 1))
+	c.ProgramCounter = Register16(hi<<8 | c.base)
+}
+
+var (
+	interruptProgram = []microStep{dummyReadPC, pushPCHi, pushPCLo, pushStatus, readVectorLo, readVectorHi}
+	brkProgram       = []microStep{fetchBreakPadding, pushPCHi, pushPCLo, pushStatusBreak, readVectorLo, readVectorHi}
+)
@@ -0,0 +1,148 @@
+package go6502
+
+import (
+	"testing"
+
+	"github.com/zehlt/go6502/asrt"
+)
+
+func TestNmiPushesStateAndJumpsToVector(t *testing.T) {
+	memory := Mem{}
+	memory[0x0600] = NOP_IMP
+	memory[NmiVector] = 0x00
+	memory[NmiVector+1] = 0x80
+
+	cpu := Cpu{}
+	cpu.ProgramCounter = 0x0600
+	cpu.StackPointer = 0xFF
+	cpu.Status.Add(Carry)
+	cpu.Status.Add(Break)
+	cpu.SetNMI(true)
+	cpu.Step(BusEx{&memory})
+
+	asrt.Equal(t, cpu.ProgramCounter, Register16(0x8000))
+	asrt.Equal(t, cpu.StackPointer, Register8(0xFC))
+	asrt.Equal(t, memory[0x01FF], uint8(0x06))
+	asrt.Equal(t, memory[0x01FE], uint8(0x00))
+	asrt.Equal(t, memory[0x01FD], uint8(Carry|Break2))
+	asrt.True(t, cpu.Status.Has(Interrupt))
+	asrt.Equal(t, cpu.Cycle, 7)
+}
+
+func TestNmiIsEdgeTriggered(t *testing.T) {
+	memory := Mem{}
+	memory[0x8000] = NOP_IMP
+	memory[0x8001] = NOP_IMP
+	memory[NmiVector+1] = 0x80
+
+	cpu := Cpu{}
+	cpu.StackPointer = 0xFF
+	cpu.SetNMI(true)
+	cpu.Step(BusEx{&memory})
+	cpu.Step(BusEx{&memory})
+
+	asrt.Equal(t, cpu.ProgramCounter, Register16(0x8001))
+	asrt.Equal(t, cpu.StackPointer, Register8(0xFC))
+
+	cpu.SetNMI(false)
+	cpu.SetNMI(true)
+	cpu.Step(BusEx{&memory})
+
+	asrt.Equal(t, cpu.ProgramCounter, Register16(0x8000))
+	asrt.Equal(t, cpu.StackPointer, Register8(0xF9))
+}
+
+func TestIrqJumpsToVector(t *testing.T) {
+	bus := Mem{}
+	bus[0x0600] = NOP_IMP
+	bus[IrqVector] = 0x34
+	bus[IrqVector+1] = 0x12
+
+	cpu := Cpu{}
+	cpu.ProgramCounter = 0x0600
+	cpu.StackPointer = 0xFF
+	cpu.SetIRQ(true)
+	cpu.Step(&bus)
+
+	asrt.Equal(t, cpu.ProgramCounter, Register16(0x1234))
+	asrt.Equal(t, cpu.StackPointer, Register8(0xFC))
+	asrt.Equal(t, bus[0x01FD], uint8(Break2))
+	asrt.True(t, cpu.Status.Has(Interrupt))
+	asrt.Equal(t, cpu.Cycle, 7)
+}
+
+func TestIrqMaskedByInterruptFlag(t *testing.T) {
+	bus := Mem{}
+	bus[0x0600] = NOP_IMP
+	bus[IrqVector+1] = 0x12
+
+	cpu := Cpu{}
+	cpu.ProgramCounter = 0x0600
+	cpu.StackPointer = 0xFF
+	cpu.Status.Add(Interrupt)
+	cpu.SetIRQ(true)
+	cpu.Step(&bus)
+
+	asrt.Equal(t, cpu.ProgramCounter, Register16(0x0601))
+	asrt.Equal(t, cpu.StackPointer, Register8(0xFF))
+	asrt.Equal(t, cpu.Cycle, Opcodes[NOP_IMP].Cycles)
+}
+
+func TestIrqIsLevelTriggered(t *testing.T) {
+	bus := Mem{}
+	bus[0x1234] = CLI_IMP
+	bus[IrqVector] = 0x34
+	bus[IrqVector+1] = 0x12
+
+	cpu := Cpu{}
+	cpu.StackPointer = 0xFF
+	cpu.SetIRQ(true)
+	cpu.Step(&bus)
+	cpu.Step(&bus)
+	cpu.Step(&bus)
+
+	asrt.Equal(t, cpu.ProgramCounter, Register16(0x1234))
+	asrt.Equal(t, cpu.StackPointer, Register8(0xF9))
+}
+
+func TestRtiReturnsFromInterrupt(t *testing.T) {
+	memory := Mem{}
+	memory[0x0600] = NOP_IMP
+	memory[0x8000] = RTI_IMP
+	memory[NmiVector+1] = 0x80
+
+	cpu := Cpu{}
+	cpu.ProgramCounter = 0x0600
+	cpu.StackPointer = 0xFF
+	cpu.Status.Add(Negative)
+	cpu.SetNMI(true)
+	cpu.Step(BusEx{&memory})
+	cpu.Step(BusEx{&memory})
+
+	asrt.Equal(t, cpu.ProgramCounter, Register16(0x0600))
+	asrt.Equal(t, cpu.StackPointer, Register8(0xFF))
+	asrt.Equal(t, cpu.Status, Register8(Negative|Break2))
+	asrt.Equal(t, cpu.Cycle, 7+Opcodes[RTI_IMP].Cycles)
+}
+
+func TestCliDelaysIrqByOneInstruction(t *testing.T) {
+	bus := Mem{}
+	bus[0x0600] = CLI_IMP
+	bus[0x0601] = NOP_IMP
+	bus[0x0602] = NOP_IMP
+	bus[IrqVector] = 0x34
+	bus[IrqVector+1] = 0x12
+
+	cpu := Cpu{}
+	cpu.ProgramCounter = 0x0600
+	cpu.StackPointer = 0xFF
+	cpu.Status.Add(Interrupt)
+	cpu.SetIRQ(true)
+
+	cpu.Step(&bus)
+	cpu.Step(&bus)
+	asrt.Equal(t, cpu.ProgramCounter, Register16(0x0602))
+
+	cpu.Step(&bus)
+	asrt.Equal(t, cpu.ProgramCounter, Register16(0x1234))
+}
@@ -0,0 +1,127 @@
+package go6502
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// Klaus Dormann's 6502 test suites,
+// https://github.com/Klaus2m5/6502_65C02_functional_tests
+// The images are not vendored, go generate downloads the functional one in
+// testdata. The decimal one has to be assembled with the default
+// configuration and dropped there. The images cover the whole 64 KiB,
+// vectors included. With GO6502_REQUIRE_SUITES set, as in CI, a missing
+// functional image fails the test instead of skipping it. A plain go test
+// without the images skips both suites, TestDecimalAgainstReference still
+// covers decimal mode.
+
+//go:generate go run ./internal/fetchsuites testdata
+
+const (
+	functionalStart    = 0x0400
+	functionalSuccess  = 0x3469
+	functionalTestCase = 0x0200
+
+	decimalStart = 0x0200
+	decimalError = 0x000B
+
+	suiteCycleLimit = 200_000_000
+)
+
+func loadImage(t *testing.T, name string, addr uint16, required bool) *Mem {
+	t.Helper()
+	data, err := os.ReadFile(filepath.Join("testdata", name))
+	if errors.Is(err, fs.ErrNotExist) && !required {
+		t.Skipf("testdata/%s not found, see testdata/README.md", name)
+	}
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	bus := &Mem{}
+	copy(bus[addr:], data)
+	return bus
+}
+
+// runUntilTrap runs until an instruction jumps to itself, which is how the
+// suites report both success and failure, and returns the trap address.
+// The decimal suite ends on a 65C02 STP instead, the cpu stops before it.
+func runUntilTrap(t *testing.T, cpu *Cpu, bus Bus) uint16 {
+	t.Helper()
+	for cpu.Cycle < suiteCycleLimit {
+		pc := cpu.ProgramCounter
+		if bus.Read(uint16(pc)) == STP_IMP {
+			return uint16(pc)
+		}
+		if err := cpu.Step(bus); err != nil {
+			t.Fatal(err)
+		}
+		if cpu.ProgramCounter == pc {
+			return uint16(pc)
+		}
+	}
+	t.Fatalf("no trap after %d cycles, PC $%04X", suiteCycleLimit, cpu.ProgramCounter)
+	return 0
+}
+
+func TestKlausFunctional(t *testing.T) {
+	bus := loadImage(t, "6502_functional_test.bin", 0x0000, os.Getenv("GO6502_REQUIRE_SUITES") != "")
+
+	cpu := Cpu{}
+	cpu.ProgramCounter = functionalStart
+	cpu.StackPointer = 0xFF
+	pc := runUntilTrap(t, &cpu, bus)
+
+	if pc != functionalSuccess {
+		t.Fatalf("functional test $%02X failed, trapped at $%04X", bus[functionalTestCase], pc)
+	}
+}
+
+func TestKlausDecimal(t *testing.T) {
+	bus := loadImage(t, "6502_decimal_test.bin", decimalStart, false)
+
+	cpu := Cpu{}
+	cpu.ProgramCounter = decimalStart
+	cpu.StackPointer = 0xFF
+	pc := runUntilTrap(t, &cpu, bus)
+
+	if bus[decimalError] != 0 {
+		t.Fatalf("decimal test failed, trapped at $%04X with A=$%02X", pc, cpu.Accumulator)
+	}
+}
+
+// TestDecimalAgainstReference runs what the decimal suite checks without
+// its image, ADC and SBC in decimal mode for every accumulator, operand
+// and carry, compared with the reference model of FuzzStep.
+func TestDecimalAgainstReference(t *testing.T) {
+	// immediate operands write nothing, both memories are reused
+	memory, expected := &Mem{}, &Mem{}
+	for _, opcode := range []uint8{ADC_IMM, SBC_IMM} {
+		for i := 0; i < 0x20000; i++ {
+			a, value, carry := uint8(i), uint8(i>>8), uint8(i>>16)
+			memory[0x0600], memory[0x0601] = opcode, value
+			expected[0x0600], expected[0x0601] = opcode, value
+
+			ref := reference{a: a, p: refD | carry, s: 0xFF, pc: 0x0600, mem: expected}
+			ref.step()
+
+			cpu := Cpu{}
+			cpu.Accumulator = Register8(a)
+			cpu.Status = Register8(Decimal) | Register8(carry)
+			cpu.StackPointer = 0xFF
+			cpu.ProgramCounter = 0x0600
+			if err := cpu.Step(memory); err != nil {
+				t.Fatal(err)
+			}
+
+			if uint8(cpu.Accumulator) != ref.a || uint8(cpu.Status)&statusMask != ref.p&statusMask {
+				t.Fatalf("%s #$%02X with A:%02X C:%d: A:%02X P:%02X, expected A:%02X P:%02X",
+					referenceOpcodes[opcode].name, value, a, carry,
+					uint8(cpu.Accumulator), uint8(cpu.Status)&statusMask, ref.a, ref.p&statusMask)
+			}
+		}
+	}
+}
@@ -0,0 +1,231 @@
+package go6502
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+)
+
+// Chip is a device that answers the accesses to the addresses it is mapped
+// to on a MappedBus. It receives the offset of the address in its mapping,
+// not the address itself. A chip with side effects should be an Inspector
+// too, the offsets are given to Peek and Poke the same way.
+type Chip interface {
+	Read(offset uint16) uint8
+	Write(offset uint16, data uint8)
+}
+
+// Mapping attaches Chip to the addresses from Start to End, both included.
+//
+// The offset given to the chip is the address minus Start, masked by Mask
+// to mirror a small chip over a larger range: the 2 KB of RAM of the NES
+// repeat over $0000-$1FFF with the mask $07FF. A zero Mask does not mirror.
+//
+// Where mappings overlap, the one with the highest Priority answers, the
+// last mapped one between equal priorities. A ReadOnly mapping leaves the
+// writes to the mapping below it, like a ROM over RAM, and a WriteOnly one
+// leaves the reads.
+type Mapping struct {
+	Start, End uint16
+	Mask       uint16
+	Priority   int
+	ReadOnly   bool
+	WriteOnly  bool
+	Chip       Chip
+}
+
+func (m *Mapping) offset(addr uint16) uint16 {
+	offset := addr - m.Start
+	if m.Mask != 0 {
+		offset &= m.Mask
+	}
+	return offset
+}
+
+// maxMappings fits the indexes of the mappings in a byte, 0 is unmapped
+const maxMappings = 255
+
+// MappedBus is a Bus made of the chips mapped on it. The zero value is an
+// empty bus.
+//
+// Unmapped addresses are open bus: reads return the last value seen on the
+// data bus, read or written, and writes are lost.
+type MappedBus struct {
+	mappings []*Mapping
+	// the index plus one of the mapping answering each address
+	reads, writes [0x10000]uint8
+	data          uint8
+}
+
+// Map adds a mapping to the bus. The returned mapping removes it with
+// Unmap, changing its fields has no effect.
+func (b *MappedBus) Map(m Mapping) (*Mapping, error) {
+	switch {
+	case m.Chip == nil:
+		return nil, errors.New("go6502: mapping without a chip")
+	case emptyMemory(m.Chip):
+		return nil, errors.New("go6502: mapping of an empty RAM or ROM")
+	case m.End < m.Start:
+		return nil, fmt.Errorf("go6502: mapping end $%04X before start $%04X", m.End, m.Start)
+	case m.ReadOnly && m.WriteOnly:
+		return nil, errors.New("go6502: mapping both read only and write only")
+	case len(b.mappings) >= maxMappings:
+		return nil, fmt.Errorf("go6502: more than %d mappings", maxMappings)
+	}
+	b.mappings = append(b.mappings, &m)
+	b.update()
+	return &m, nil
+}
+
+// Unmap removes a mapping returned by Map, it reports whether it was on the
+// bus.
+func (b *MappedBus) Unmap(m *Mapping) bool {
+	for i, mapped := range b.mappings {
+		if mapped == m {
+			b.mappings = append(b.mappings[:i], b.mappings[i+1:]...)
+			b.update()
+			return true
+		}
+	}
+	return false
+}
+
+// Mappings returns the mappings in the order they were mapped.
+func (b *MappedBus) Mappings() []*Mapping {
+	return append([]*Mapping(nil), b.mappings...)
+}
+
+// emptyMemory reports a RAM or a ROM without bytes, there is no offset for
+// its accesses to wrap around to
+func emptyMemory(chip Chip) bool {
+	switch c := chip.(type) {
+	case RAM:
+		return len(c) == 0
+	case ROM:
+		return len(c) == 0
+	}
+	return false
+}
+
+// update fills the tables of the addresses, the mappings that win are
+// applied last
+func (b *MappedBus) update() {
+	order := make([]int, len(b.mappings))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(i, j int) bool {
+		return b.mappings[order[i]].Priority < b.mappings[order[j]].Priority
+	})
+
+	b.reads = [0x10000]uint8{}
+	b.writes = [0x10000]uint8{}
+	for _, i := range order {
+		m := b.mappings[i]
+		for addr := int(m.Start); addr <= int(m.End); addr++ {
+			if !m.WriteOnly {
+				b.reads[addr] = uint8(i + 1)
+			}
+			if !m.ReadOnly {
+				b.writes[addr] = uint8(i + 1)
+			}
+		}
+	}
+}
+
+func (b *MappedBus) Read(addr uint16) uint8 {
+	if i := b.reads[addr]; i != 0 {
+		m := b.mappings[i-1]
+		b.data = m.Chip.Read(m.offset(addr))
+	}
+	return b.data
+}
+
+func (b *MappedBus) Write(addr uint16, data uint8) {
+	b.data = data
+	if i := b.writes[addr]; i != 0 {
+		m := b.mappings[i-1]
+		m.Chip.Write(m.offset(addr), data)
+	}
+}
+
+// Peek reads addr through the chip that answers its reads, with its Peek
+// when it is an Inspector and its Read otherwise. Unmapped addresses read
+// as the open bus. The data bus is left as it is.
+func (b *MappedBus) Peek(addr uint16) uint8 {
+	i := b.reads[addr]
+	if i == 0 {
+		return b.data
+	}
+	m := b.mappings[i-1]
+	if inspector, ok := m.Chip.(Inspector); ok {
+		return inspector.Peek(m.offset(addr))
+	}
+	return m.Chip.Read(m.offset(addr))
+}
+
+// Poke writes addr to the chip that answers its reads, so that Peek
+// returns data, with its Poke when it is an Inspector and its Write
+// otherwise. The data bus is left as it is.
+func (b *MappedBus) Poke(addr uint16, data uint8) {
+	i := b.reads[addr]
+	if i == 0 {
+		return
+	}
+	m := b.mappings[i-1]
+	if inspector, ok := m.Chip.(Inspector); ok {
+		inspector.Poke(m.offset(addr), data)
+		return
+	}
+	m.Chip.Write(m.offset(addr), data)
+}
+
+func (b *MappedBus) ReadWord(addr uint16) uint16 {
+	lo := uint16(b.Read(addr))
+	hi := uint16(b.Read(addr + 1))
+	return hi<<8 | lo
+}
+
+func (b *MappedBus) WriteWord(addr uint16, data uint16) {
+	b.Write(addr, uint8(data))
+	b.Write(addr+1, uint8(data>>8))
+}
+
+// RAM is a chip of read write memory, the offsets past its size wrap
+// around. It must not be empty, Map rejects an empty one.
+type RAM []uint8
+
+func (r RAM) Read(offset uint16) uint8 {
+	return r[int(offset)%len(r)]
+}
+
+func (r RAM) Write(offset uint16, data uint8) {
+	r[int(offset)%len(r)] = data
+}
+
+func (r RAM) Peek(offset uint16) uint8 {
+	return r.Read(offset)
+}
+
+func (r RAM) Poke(offset uint16, data uint8) {
+	r.Write(offset, data)
+}
+
+// ROM is a chip of read only memory, the writes are ignored and the offsets
+// past its size wrap around. Like RAM, it must not be empty.
+type ROM []uint8
+
+func (r ROM) Read(offset uint16) uint8 {
+	return r[int(offset)%len(r)]
+}
+
+func (r ROM) Write(offset uint16, data uint8) {}
+
+func (r ROM) Peek(offset uint16) uint8 {
+	return r.Read(offset)
+}
+
+// Poke changes the ROM, to patch it.
+func (r ROM) Poke(offset uint16, data uint8) {
+	r[int(offset)%len(r)] = data
+}
@@ -0,0 +1,206 @@
+package go6502
+
+import (
+	"testing"
+
+	"github.com/zehlt/go6502/asrt"
+)
+
+// registers logs the offsets it is accessed with
+type registers struct {
+	values  [8]uint8
+	offsets []uint16
+}
+
+func (r *registers) Read(offset uint16) uint8 {
+	r.offsets = append(r.offsets, offset)
+	return r.values[offset]
+}
+
+func (r *registers) Write(offset uint16, data uint8) {
+	r.offsets = append(r.offsets, offset)
+	r.values[offset] = data
+}
+
+func TestMappedBusMirrors(t *testing.T) {
+	bus := &MappedBus{}
+	ram := make(RAM, 0x800)
+	ppu := &registers{}
+	bus.Map(Mapping{Start: 0x0000, End: 0x1FFF, Mask: 0x07FF, Chip: ram})
+	bus.Map(Mapping{Start: 0x2000, End: 0x3FFF, Mask: 0x0007, Chip: ppu})
+
+	bus.Write(0x0012, 0x34)
+	asrt.Equal(t, bus.Read(0x0812), uint8(0x34))
+	asrt.Equal(t, bus.Read(0x1812), uint8(0x34))
+	bus.Write(0x1FFF, 0x56)
+	asrt.Equal(t, ram[0x07FF], uint8(0x56))
+
+	bus.Write(0x3FF9, 0x78)
+	asrt.Equal(t, bus.Read(0x2001), uint8(0x78))
+	asrt.Equal(t, len(ppu.offsets), 2)
+	asrt.Equal(t, ppu.offsets[0], uint16(1))
+	asrt.Equal(t, ppu.offsets[1], uint16(1))
+}
+
+func TestMappedBusOpenBus(t *testing.T) {
+	bus := &MappedBus{}
+	bus.Map(Mapping{Start: 0x8000, End: 0xFFFF, Chip: ROM{0xA5, 0x5A}})
+
+	asrt.Equal(t, bus.Read(0x4000), uint8(0x00))
+	asrt.Equal(t, bus.Read(0x8001), uint8(0x5A))
+	asrt.Equal(t, bus.Read(0x4000), uint8(0x5A))
+	bus.Write(0x5000, 0x99)
+	asrt.Equal(t, bus.Read(0x4000), uint8(0x99))
+	asrt.Equal(t, bus.ReadWord(0xFFFE), uint16(0x5AA5))
+}
+
+func TestMappedBusOverlays(t *testing.T) {
+	bus := &MappedBus{}
+	ram := make(RAM, 0x10000)
+	basic := ROM{0xBA}
+	io := &registers{}
+	bus.Map(Mapping{Start: 0x0000, End: 0xFFFF, Chip: ram})
+	rom, _ := bus.Map(Mapping{Start: 0xA000, End: 0xBFFF, ReadOnly: true, Chip: basic})
+	// mapped first but above the others
+	bus.Map(Mapping{Start: 0xA000, End: 0xA007, Priority: 1, WriteOnly: true, Chip: io})
+
+	// the writes go through the ROM to the RAM under it
+	bus.Write(0xB000, 0x11)
+	asrt.Equal(t, ram[0xB000], uint8(0x11))
+	asrt.Equal(t, bus.Read(0xB000), uint8(0xBA))
+
+	// the write only registers leave the reads to the ROM
+	bus.Write(0xA003, 0x22)
+	asrt.Equal(t, io.values[3], uint8(0x22))
+	asrt.Equal(t, ram[0xA003], uint8(0x00))
+	asrt.Equal(t, bus.Read(0xA003), uint8(0xBA))
+
+	// a later mapping of equal priority wins
+	bus.Map(Mapping{Start: 0xB000, End: 0xB000, ReadOnly: true, Chip: ROM{0xCC}})
+	asrt.Equal(t, bus.Read(0xB000), uint8(0xCC))
+
+	asrt.True(t, bus.Unmap(rom))
+	asrt.False(t, bus.Unmap(rom))
+	asrt.Equal(t, bus.Read(0xB001), uint8(0x00))
+	asrt.Equal(t, len(bus.Mappings()), 3)
+}
+
+func TestMappedBusRejectsBadMappings(t *testing.T) {
+	bus := &MappedBus{}
+	_, err := bus.Map(Mapping{Start: 0x2000, End: 0x1000, Chip: ROM{0}})
+	asrt.Equal(t, err.Error(), "go6502: mapping end $1000 before start $2000")
+	_, err = bus.Map(Mapping{Start: 0x2000, End: 0x3000})
+	asrt.True(t, err != nil)
+	_, err = bus.Map(Mapping{ReadOnly: true, WriteOnly: true, Chip: ROM{0}})
+	asrt.True(t, err != nil)
+	_, err = bus.Map(Mapping{Chip: RAM(nil)})
+	asrt.Equal(t, err.Error(), "go6502: mapping of an empty RAM or ROM")
+	_, err = bus.Map(Mapping{Chip: ROM{}})
+	asrt.True(t, err != nil)
+	asrt.Equal(t, bus.Read(0x0000), uint8(0))
+	for i := 0; i < maxMappings; i++ {
+		_, err = bus.Map(Mapping{Start: uint16(i), End: uint16(i), Chip: ROM{0}})
+		asrt.Equal(t, err, nil)
+	}
+	_, err = bus.Map(Mapping{Chip: ROM{0}})
+	asrt.True(t, err != nil)
+}
+
+func TestCpuOnMappedBus(t *testing.T) {
+	bus := &MappedBus{}
+	ram := make(RAM, 0x800)
+	rom := make(ROM, 0x4000)
+	copy(rom, []uint8{
+		LDA_IMM, 0x42,
+		STA_ABS, 0x10, 0x08, // $0810 mirrors $0010
+		LDA_ABS, 0x00, 0x50, // open bus, the last value is the $50 of the operand
+		STP_IMP,
+	})
+	rom[0x3FFC] = 0x00
+	rom[0x3FFD] = 0xC0
+	bus.Map(Mapping{Start: 0x0000, End: 0x1FFF, Mask: 0x07FF, Chip: ram})
+	bus.Map(Mapping{Start: 0x8000, End: 0xFFFF, Chip: rom})
+
+	cpu := Cpu{Variant: WDC65C02}
+	cpu.Reset(bus)
+	asrt.Equal(t, uint16(cpu.ProgramCounter), uint16(0xC000))
+	for !cpu.Halted() {
+		asrt.Equal(t, cpu.Step(bus), nil)
+	}
+	asrt.Equal(t, ram[0x10], uint8(0x42))
+	asrt.Equal(t, cpu.Accumulator, Register8(0x50))
+}
+
+// latch is a status register acknowledged by its read
+type latch struct {
+	status uint8
+}
+
+func (l *latch) Read(offset uint16) uint8 {
+	status := l.status
+	l.status = 0
+	return status
+}
+
+func (l *latch) Write(offset uint16, data uint8) {
+	l.status = data
+}
+
+type inspectableLatch struct {
+	latch
+}
+
+func (l *inspectableLatch) Peek(offset uint16) uint8 {
+	return l.status
+}
+
+func (l *inspectableLatch) Poke(offset uint16, data uint8) {
+	l.status = data
+}
+
+func TestMappedBusPeekAndPoke(t *testing.T) {
+	bus := &MappedBus{}
+	ram := make(RAM, 0x10000)
+	irq := &inspectableLatch{latch{status: 0x80}}
+	plain := &latch{status: 0x40}
+	bus.Map(Mapping{Start: 0x0000, End: 0xFFFF, Chip: ram})
+	bus.Map(Mapping{Start: 0xD000, End: 0xD0FF, Mask: 0x0001, Chip: irq})
+	bus.Map(Mapping{Start: 0xD100, End: 0xD100, Chip: plain})
+	bus.Map(Mapping{Start: 0xE000, End: 0xFFFF, ReadOnly: true, Chip: ROM{0xEA}})
+	bus.Write(0x4000, 0x55)
+
+	asrt.Equal(t, Peek(bus, 0xD002), uint8(0x80))
+	asrt.Equal(t, irq.status, uint8(0x80))
+	asrt.Equal(t, bus.Read(0xD002), uint8(0x80))
+	asrt.Equal(t, irq.status, uint8(0x00))
+	Poke(bus, 0xD000, 0x81)
+	asrt.Equal(t, irq.status, uint8(0x81))
+
+	// without Peek the chip is read
+	asrt.Equal(t, Peek(bus, 0xD100), uint8(0x40))
+	asrt.Equal(t, plain.status, uint8(0x00))
+
+	// Poke patches what Peek sees, the ROM over the RAM
+	Poke(bus, 0xE000, 0x60)
+	asrt.Equal(t, Peek(bus, 0xE000), uint8(0x60))
+	asrt.Equal(t, ram[0xE000], uint8(0x00))
+	asrt.Equal(t, bus.Read(0xE001), uint8(0x60))
+}
+
+func TestPeekFallsBackToRead(t *testing.T) {
+	bus := &recordingBus{Mem: &Mem{}}
+	bus.Mem[0x10] = 0x42
+
+	// recordingBus is an Inspector through its Mem, hide it
+	var plain struct{ Bus }
+	plain.Bus = bus
+	asrt.Equal(t, Peek(plain, 0x10), uint8(0x42))
+	Poke(plain, 0x11, 0x43)
+	assertAccesses(t, bus.log, read(0x10, 0x42), write(0x11, 0x43))
+
+	bus.log = nil
+	asrt.Equal(t, Peek(bus, 0x11), uint8(0x43))
+	Poke(BusEx{bus.Mem}, 0x12, 0x44)
+	asrt.Equal(t, Peek(BusEx{bus.Mem}, 0x12), uint8(0x44))
+	asrt.Equal(t, len(bus.log), 0)
+}
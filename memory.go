@@ -19,7 +19,9 @@ package go6502
 	0x0000 | CPU RAM
 */
 
-type Mem [0xFFFF]uint8
+// Mem is a flat memory covering the whole address space. The accesses of
+// more than one byte wrap around from $FFFF to $0000.
+type Mem [0x10000]uint8
 
 func (m *Mem) Read(addr uint16) uint8 {
 	return m[addr]
@@ -29,6 +31,16 @@ func (m *Mem) Write(addr uint16, data uint8) {
 	m[addr] = data
 }
 
+func (m *Mem) Peek(addr uint16) uint8 {
+	return m[addr]
+}
+
+func (m *Mem) Poke(addr uint16, data uint8) {
+	m[addr] = data
+}
+
+// ReadWord reads the little endian word at addr, the high byte of $FFFF is
+// read from $0000.
 func (m *Mem) ReadWord(addr uint16) uint16 {
 	var lo uint16 = uint16(m.Read(addr))
 	var hi uint16 = uint16(m.Read(addr + 1))
@@ -36,6 +48,8 @@ func (m *Mem) ReadWord(addr uint16) uint16 {
 	return word
 }
 
+// WriteWord writes the little endian word at addr, the high byte of $FFFF
+// is written to $0000.
 func (m *Mem) WriteWord(addr uint16, data uint16) {
 	hi := uint8((data >> 8))
 	lo := uint8(data)
@@ -43,6 +57,14 @@ func (m *Mem) WriteWord(addr uint16, data uint16) {
 	m.Write(addr+1, hi)
 }
 
+// ReadZeroPageWord reads the pointer at addr in the zero page, the high
+// byte of $FF is read from $00 like the cpu does for (zp,X) and (zp),Y.
+func (m *Mem) ReadZeroPageWord(addr uint8) uint16 {
+	return ReadZeroPageWord(m, addr)
+}
+
+// WriteBytes writes data from addr on, the bytes past $FFFF are written
+// from $0000 on.
 func (m *Mem) WriteBytes(addr uint16, data []uint8) {
 	for index, value := range data {
 		m.Write(addr+uint16(index), value)
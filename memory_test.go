@@ -107,3 +107,46 @@ func TestWriteTwoWord(t *testing.T) {
 // 		asrt.Equal(t, mem[addr+(uint16(i))], want[i])
 // 	}
 // }
+
+func TestMemCoversTheLastAddress(t *testing.T) {
+	mem := Mem{}
+	mem.Write(0xFFFF, 0x12)
+	asrt.Equal(t, mem.Read(0xFFFF), uint8(0x12))
+	asrt.Equal(t, len(mem), 0x10000)
+}
+
+func TestWordsWrapAroundTheAddressSpace(t *testing.T) {
+	mem := Mem{}
+	mem.WriteWord(0xFFFF, 0xBEEF)
+	asrt.Equal(t, mem[0xFFFF], uint8(0xEF))
+	asrt.Equal(t, mem[0x0000], uint8(0xBE))
+	asrt.Equal(t, mem.ReadWord(0xFFFF), uint16(0xBEEF))
+
+	mem.WriteBytes(0xFFFE, []uint8{1, 2, 3, 4})
+	asrt.Equal(t, mem[0xFFFE], uint8(1))
+	asrt.Equal(t, mem[0xFFFF], uint8(2))
+	asrt.Equal(t, mem[0x0000], uint8(3))
+	asrt.Equal(t, mem[0x0001], uint8(4))
+}
+
+func TestZeroPageWordWrapsInsideTheZeroPage(t *testing.T) {
+	mem := Mem{}
+	mem[0x00FF] = 0x34
+	mem[0x0000] = 0x12
+	mem[0x0100] = 0x56
+	asrt.Equal(t, mem.ReadZeroPageWord(0xFF), uint16(0x1234))
+	asrt.Equal(t, mem.ReadWord(0x00FF), uint16(0x5634))
+	asrt.Equal(t, ReadZeroPageWord(&mem, 0xFF), uint16(0x1234))
+}
+
+func TestIrqVectorOnMem(t *testing.T) {
+	mem := Mem{}
+	mem[0x0600] = BRK_IMP
+	mem.WriteWord(IrqVector, 0x8000)
+
+	cpu := Cpu{}
+	cpu.ProgramCounter = 0x0600
+	cpu.StackPointer = 0xFF
+	asrt.Equal(t, cpu.Step(&mem), nil)
+	asrt.Equal(t, cpu.ProgramCounter, Register16(0x8000))
+}
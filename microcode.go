@@ -0,0 +1,411 @@
+package go6502
+
+// Instructions are executed as microcode, one step per clock cycle. Every
+// step issues exactly one bus access, dummy reads and the double write of
+// read-modify-write instructions included, so devices see the same bus
+// traffic as with the real chip. The first cycle of an instruction, the
+// opcode fetch, is done by Tick itself.
+//
+// The 65C02 replaces some dummy accesses, it never writes twice and its
+// indexing dummy reads hit the last operand byte. Those are modelled after
+// the WDC datasheet.
+
+type microStep func(c *Cpu, bus Bus)
+
+func (c *Cpu) fetch(bus Bus) uint8 {
+	value := bus.Read(uint16(c.ProgramCounter))
+	c.ProgramCounter++
+	return value
+}
+
+// skipStep drops the next step of the program
+func (c *Cpu) skipStep() {
+	c.step++
+}
+
+// endInstruction drops all the remaining steps of the program
+func (c *Cpu) endInstruction() {
+	c.step = len(c.program)
+}
+
+func (c *Cpu) indexAddr(index Register8) {
+	lo := c.addr&0x00FF + uint16(index)
+	c.pageCrossed = lo > 0x00FF
+	c.addr = c.addr&0xFF00 | lo&0x00FF
+}
+
+func (c *Cpu) push(bus Bus, value uint8) {
+	bus.Write(0x0100+uint16(c.StackPointer), value)
+	c.StackPointer--
+}
+
+// Operand fetches
+
+func dummyReadPC(c *Cpu, bus Bus) {
+	bus.Read(uint16(c.ProgramCounter))
+}
+
+func dummyReadAddr(c *Cpu, bus Bus) {
+	bus.Read(c.addr)
+}
+
+func fetchAddrLo(c *Cpu, bus Bus) {
+	c.addr = uint16(c.fetch(bus))
+}
+
+func fetchAddrHi(c *Cpu, bus Bus) {
+	c.addr |= uint16(c.fetch(bus)) << 8
+}
+
+func fetchAddrHiIndexX(c *Cpu, bus Bus) {
+	fetchAddrHi(c, bus)
+	c.indexAddr(c.XIndex)
+}
+
+func fetchAddrHiIndexY(c *Cpu, bus Bus) {
+	fetchAddrHi(c, bus)
+	c.indexAddr(c.YIndex)
+}
+
+// reads only pay the fix up cycle when the index crossed a page
+func fetchAddrHiIndexXSkip(c *Cpu, bus Bus) {
+	fetchAddrHiIndexX(c, bus)
+	if !c.pageCrossed {
+		c.skipStep()
+	}
+}
+
+func fetchAddrHiIndexYSkip(c *Cpu, bus Bus) {
+	fetchAddrHiIndexY(c, bus)
+	if !c.pageCrossed {
+		c.skipStep()
+	}
+}
+
+// the NMOS reads the address before the high byte carry is applied
+func fixAddr(c *Cpu, bus Bus) {
+	if c.Variant.IsCmos() {
+		bus.Read(uint16(c.ProgramCounter - 1))
+	} else {
+		bus.Read(c.addr)
+	}
+	if c.pageCrossed {
+		c.addr += 0x0100
+	}
+}
+
+func fetchZeroPage(c *Cpu, bus Bus) {
+	c.addr = uint16(c.fetch(bus))
+}
+
+func zeroPageIndexX(c *Cpu, bus Bus) {
+	bus.Read(c.addr)
+	c.addr = uint16(uint8(c.addr) + uint8(c.XIndex))
+}
+
+func zeroPageIndexY(c *Cpu, bus Bus) {
+	bus.Read(c.addr)
+	c.addr = uint16(uint8(c.addr) + uint8(c.YIndex))
+}
+
+func readPointerLo(c *Cpu, bus Bus) {
+	c.base = uint16(bus.Read(c.addr))
+}
+
+// zeroPageNext is the address after addr in the zero page, the pointers
+// wrap around inside it
+func zeroPageNext(addr uint16) uint16 {
+	return uint16(uint8(addr) + 1)
+}
+
+func readPointerHi(c *Cpu, bus Bus) {
+	c.addr = uint16(bus.Read(zeroPageNext(c.addr)))<<8 | c.base
+}
+
+func readPointerHiIndexY(c *Cpu, bus Bus) {
+	readPointerHi(c, bus)
+	c.indexAddr(c.YIndex)
+}
+
+func readPointerHiIndexYSkip(c *Cpu, bus Bus) {
+	readPointerHiIndexY(c, bus)
+	if !c.pageCrossed {
+		c.skipStep()
+	}
+}
+
+// Operations
+
+func implied(c *Cpu, bus Bus) {
+	bus.Read(uint16(c.ProgramCounter))
+	c.operation(c, 0)
+}
+
+func accumulator(c *Cpu, bus Bus) {
+	bus.Read(uint16(c.ProgramCounter))
+	c.Accumulator = Register8(c.operation(c, uint8(c.Accumulator)))
+}
+
+func immediate(c *Cpu, bus Bus) {
+	c.operation(c, c.fetch(bus))
+}
+
+func readOperate(c *Cpu, bus Bus) {
+	c.operation(c, bus.Read(c.addr))
+}
+
+func writeOperate(c *Cpu, bus Bus) {
+	bus.Write(c.addr, c.operation(c, 0))
+}
+
+func readData(c *Cpu, bus Bus) {
+	c.data = bus.Read(c.addr)
+}
+
+// the NMOS writes the unmodified value back while it computes the result
+func modifyData(c *Cpu, bus Bus) {
+	if c.Variant.IsCmos() {
+		bus.Read(c.addr)
+	} else {
+		bus.Write(c.addr, c.data)
+	}
+	c.data = c.operation(c, c.data)
+}
+
+func writeData(c *Cpu, bus Bus) {
+	bus.Write(c.addr, c.data)
+}
+
+// unstableFix stores the value anded with the high byte of the base address
+// plus one. When indexing crosses a page the stored value also replaces
+// the high byte of the target address, the value and the address fight
+// over the bus on the real chip.
+func unstableFix(c *Cpu, bus Bus) {
+	bus.Read(c.addr)
+	c.data = c.operation(c, 0) & (uint8(c.addr>>8) + 1)
+	if c.pageCrossed {
+		c.addr = uint16(c.data)<<8 | c.addr&0x00FF
+	}
+}
+
+// Branches, the operation reports whether the branch is taken
+
+func branchFetch(c *Cpu, bus Bus) {
+	offset := c.fetch(bus)
+	if c.operation(c, c.data) == 0 {
+		c.endInstruction()
+		return
+	}
+	c.data = offset
+}
+
+func branchTaken(c *Cpu, bus Bus) {
+	bus.Read(uint16(c.ProgramCounter))
+	target := uint16(c.ProgramCounter) + uint16(int8(c.data))
+	if target&0xFF00 == uint16(c.ProgramCounter)&0xFF00 {
+		c.ProgramCounter = Register16(target)
+		c.endInstruction()
+		return
+	}
+	c.addr = target
+	c.ProgramCounter = Register16(uint16(c.ProgramCounter)&0xFF00 | target&0x00FF)
+}
+
+func branchFix(c *Cpu, bus Bus) {
+	bus.Read(uint16(c.ProgramCounter))
+	c.ProgramCounter = Register16(c.addr)
+}
+
+// Stack
+
+func pushOperate(c *Cpu, bus Bus) {
+	c.push(bus, c.operation(c, 0))
+}
+
+func dummyReadStackInc(c *Cpu, bus Bus) {
+	bus.Read(0x0100 + uint16(c.StackPointer))
+	c.StackPointer++
+}
+
+func dummyReadStack(c *Cpu, bus Bus) {
+	bus.Read(0x0100 + uint16(c.StackPointer))
+}
+
+func pullOperate(c *Cpu, bus Bus) {
+	c.operation(c, bus.Read(0x0100+uint16(c.StackPointer)))
+}
+
+func pushPCHi(c *Cpu, bus Bus) {
+	c.push(bus, uint8(c.ProgramCounter>>8))
+}
+
+func pushPCLo(c *Cpu, bus Bus) {
+	c.push(bus, uint8(c.ProgramCounter))
+}
+
+func pullPCLoInc(c *Cpu, bus Bus) {
+	c.base = uint16(bus.Read(0x0100 + uint16(c.StackPointer)))
+	c.StackPointer++
+}
+
+func pullPCHi(c *Cpu, bus Bus) {
+	hi := uint16(bus.Read(0x0100 + uint16(c.StackPointer)))
+	c.ProgramCounter = Register16(hi<<8 | c.base)
+}
+
+func pullStatusInc(c *Cpu, bus Bus) {
+	c.Status = Register8(bus.Read(0x0100 + uint16(c.StackPointer)))
+	c.Status.Remove(Break)
+	c.Status.Add(Break2)
+	c.StackPointer++
+}
+
+func fetchPCInc(c *Cpu, bus Bus) {
+	c.fetch(bus)
+}
+
+// Jumps
+
+func jumpAddrHi(c *Cpu, bus Bus) {
+	fetchAddrHi(c, bus)
+	c.ProgramCounter = Register16(c.addr)
+}
+
+func readJumpLo(c *Cpu, bus Bus) {
+	c.base = uint16(bus.Read(c.addr))
+}
+
+// the NMOS never carries into the high byte of the pointer
+func readJumpHiWrapped(c *Cpu, bus Bus) {
+	hi := uint16(bus.Read(c.addr&0xFF00 | uint16(uint8(c.addr)+1)))
+	c.ProgramCounter = Register16(hi<<8 | c.base)
+}
+
+func readJumpHi(c *Cpu, bus Bus) {
+	hi := uint16(bus.Read(c.addr + 1))
+	c.ProgramCounter = Register16(hi<<8 | c.base)
+}
+
+func dummyReadOperandIndexX(c *Cpu, bus Bus) {
+	bus.Read(uint16(c.ProgramCounter - 1))
+	c.addr += uint16(c.XIndex)
+}
+
+func fetchJsrLo(c *Cpu, bus Bus) {
+	c.base = uint16(c.fetch(bus))
+}
+
+// the return address pushed by JSR is the last byte of the instruction
+func fetchJsrHi(c *Cpu, bus Bus) {
+	hi := uint16(bus.Read(uint16(c.ProgramCounter)))
+	c.ProgramCounter = Register16(hi<<8 | c.base)
+}
+
+// System
+
+func jamStep(c *Cpu, bus Bus) {
+	bus.Read(uint16(c.ProgramCounter))
+	c.ProgramCounter--
+	if c.HaltOnJam {
+		c.halted = true
+		return
+	}
+	c.fault = ErrJammed
+}
+
+func waitStep(c *Cpu, bus Bus) {
+	bus.Read(uint16(c.ProgramCounter))
+	c.waiting = true
+}
+
+func stopStep(c *Cpu, bus Bus) {
+	bus.Read(uint16(c.ProgramCounter))
+	c.halted = true
+}
+
+func unsupportedStep(c *Cpu, bus Bus) {
+	bus.Read(uint16(c.ProgramCounter))
+	c.fault = ErrUnsupportedMode
+}
+
+var (
+	jsrProgram        = []microStep{fetchJsrLo, dummyReadStack, pushPCHi, pushPCLo, fetchJsrHi}
+	rtsProgram        = []microStep{dummyReadPC, dummyReadStackInc, pullPCLoInc, pullPCHi, fetchPCInc}
+	rtiProgram        = []microStep{dummyReadPC, dummyReadStackInc, pullStatusInc, pullPCLoInc, pullPCHi}
+	jmpAbsProgram     = []microStep{fetchAddrLo, jumpAddrHi}
+	jmpIndProgram     = []microStep{fetchAddrLo, fetchAddrHi, readJumpLo, readJumpHiWrapped}
+	jmpIndCmosProgram = []microStep{fetchAddrLo, fetchAddrHi, dummyReadPC, readJumpLo, readJumpHi}
+	jmpIaxProgram     = []microStep{fetchAddrLo, fetchAddrHi, dummyReadOperandIndexX, readJumpLo, readJumpHi}
+	jamProgram        = []microStep{jamStep}
+	waiProgram        = []microStep{dummyReadPC, waitStep}
+	stpProgram        = []microStep{dummyReadPC, stopStep}
+	unsupported       = []microStep{unsupportedStep}
+	extraCycleProgram = []microStep{dummyReadAddr}
+)
+
+// buildProgram assembles the microcode of the instructions following the
+// regular addressing patterns.
+func buildProgram(mode int, access int) []microStep {
+	switch mode {
+	case Implied:
+		switch access {
+		case NoAccess:
+			return []microStep{implied}
+		case PushAccess:
+			return []microStep{dummyReadPC, pushOperate}
+		case PullAccess:
+			return []microStep{dummyReadPC, dummyReadStackInc, pullOperate}
+		}
+	case Accumulator:
+		return []microStep{accumulator}
+	case Immediate:
+		return []microStep{immediate}
+	case Relative:
+		return []microStep{branchFetch, branchTaken, branchFix}
+	case ZeroPageRelative:
+		return []microStep{fetchZeroPage, readData, dummyReadAddr, branchFetch, branchTaken, branchFix}
+	}
+
+	var addressing []microStep
+	switch mode {
+	case ZeroPage:
+		addressing = []microStep{fetchZeroPage}
+	case ZeroPageX:
+		addressing = []microStep{fetchZeroPage, zeroPageIndexX}
+	case ZeroPageY:
+		addressing = []microStep{fetchZeroPage, zeroPageIndexY}
+	case Absolute:
+		addressing = []microStep{fetchAddrLo, fetchAddrHi}
+	case AbsoluteX:
+		addressing = []microStep{fetchAddrLo, fetchAddrHiIndexX, fixAddr}
+	case AbsoluteX1:
+		addressing = []microStep{fetchAddrLo, fetchAddrHiIndexXSkip, fixAddr}
+	case AbsoluteY:
+		addressing = []microStep{fetchAddrLo, fetchAddrHiIndexY, fixAddr}
+	case AbsoluteY1:
+		addressing = []microStep{fetchAddrLo, fetchAddrHiIndexYSkip, fixAddr}
+	case IndirectX:
+		addressing = []microStep{fetchZeroPage, zeroPageIndexX, readPointerLo, readPointerHi}
+	case IndirectY:
+		addressing = []microStep{fetchZeroPage, readPointerLo, readPointerHiIndexY, fixAddr}
+	case IndirectY1:
+		addressing = []microStep{fetchZeroPage, readPointerLo, readPointerHiIndexYSkip, fixAddr}
+	case IndirectZeroPage:
+		addressing = []microStep{fetchZeroPage, readPointerLo, readPointerHi}
+	default:
+		return unsupported
+	}
+
+	switch access {
+	case ReadAccess:
+		return append(addressing, readOperate)
+	case WriteAccess:
+		return append(addressing, writeOperate)
+	case ModifyAccess:
+		return append(addressing, readData, modifyData, writeData)
+	case UnstableAccess:
+		// the fix up cycle computes the stored value
+		return append(addressing[:len(addressing)-1], unstableFix, writeData)
+	}
+	return unsupported
+}
@@ -0,0 +1,258 @@
+package go6502
+
+import (
+	"testing"
+
+	"github.com/zehlt/go6502/asrt"
+)
+
+type access struct {
+	addr  uint16
+	data  uint8
+	write bool
+}
+
+func read(addr uint16, data uint8) access {
+	return access{addr: addr, data: data}
+}
+
+func write(addr uint16, data uint8) access {
+	return access{addr: addr, data: data, write: true}
+}
+
+// recordingBus logs every access in the order the cpu issues them
+type recordingBus struct {
+	*Mem
+	log []access
+}
+
+func (b *recordingBus) Read(addr uint16) uint8 {
+	data := b.Mem.Read(addr)
+	b.log = append(b.log, read(addr, data))
+	return data
+}
+
+func (b *recordingBus) Write(addr uint16, data uint8) {
+	b.log = append(b.log, write(addr, data))
+	b.Mem.Write(addr, data)
+}
+
+func assertAccesses(t *testing.T, got []access, expected ...access) {
+	t.Helper()
+	asrt.Equal(t, len(got), len(expected))
+	for i := range expected {
+		asrt.Equal(t, got[i], expected[i])
+	}
+}
+
+func TestLoadAbsoluteXDummyReadOnPageCross(t *testing.T) {
+	bus := recordingBus{Mem: &Mem{}}
+	bus.Mem.Write(0x0000, LDA_ABX)
+	bus.Mem.Write(0x0001, 0xF0)
+	bus.Mem.Write(0x0002, 0x12)
+	bus.Mem.Write(0x1210, 0x11)
+	bus.Mem.Write(0x1310, 0x22)
+
+	cpu := Cpu{}
+	cpu.XIndex = 0x20
+	cpu.Step(&bus)
+
+	assertAccesses(t, bus.log,
+		read(0x0000, LDA_ABX),
+		read(0x0001, 0xF0),
+		read(0x0002, 0x12),
+		read(0x1210, 0x11),
+		read(0x1310, 0x22),
+	)
+	asrt.Equal(t, cpu.Accumulator, Register8(0x22))
+	asrt.Equal(t, cpu.Cycle, len(bus.log))
+}
+
+func TestStoreAbsoluteXAlwaysDummyReads(t *testing.T) {
+	bus := recordingBus{Mem: &Mem{}}
+	bus.Mem.Write(0x0000, STA_ABX)
+	bus.Mem.Write(0x0001, 0x00)
+	bus.Mem.Write(0x0002, 0x12)
+
+	cpu := Cpu{}
+	cpu.Accumulator = 0x42
+	cpu.XIndex = 0x05
+	cpu.Step(&bus)
+
+	assertAccesses(t, bus.log,
+		read(0x0000, STA_ABX),
+		read(0x0001, 0x00),
+		read(0x0002, 0x12),
+		read(0x1205, 0x00),
+		write(0x1205, 0x42),
+	)
+	asrt.Equal(t, cpu.Cycle, Opcodes[STA_ABX].Cycles)
+}
+
+func TestReadModifyWriteWritesTwice(t *testing.T) {
+	bus := recordingBus{Mem: &Mem{}}
+	bus.Mem.Write(0x0000, INC_ZER)
+	bus.Mem.Write(0x0001, 0x80)
+	bus.Mem.Write(0x0080, 0x41)
+
+	cpu := Cpu{}
+	cpu.Step(&bus)
+
+	assertAccesses(t, bus.log,
+		read(0x0000, INC_ZER),
+		read(0x0001, 0x80),
+		read(0x0080, 0x41),
+		write(0x0080, 0x41),
+		write(0x0080, 0x42),
+	)
+}
+
+func TestReadModifyWriteReadsTwiceOnCmos(t *testing.T) {
+	bus := recordingBus{Mem: &Mem{}}
+	bus.Mem.Write(0x0000, INC_ZER)
+	bus.Mem.Write(0x0001, 0x80)
+	bus.Mem.Write(0x0080, 0x41)
+
+	cpu := Cpu{Variant: CMOS65C02}
+	cpu.Step(&bus)
+
+	assertAccesses(t, bus.log,
+		read(0x0000, INC_ZER),
+		read(0x0001, 0x80),
+		read(0x0080, 0x41),
+		read(0x0080, 0x41),
+		write(0x0080, 0x42),
+	)
+}
+
+func TestIndirectYDummyReadOnPageCross(t *testing.T) {
+	bus := recordingBus{Mem: &Mem{}}
+	bus.Mem.Write(0x0000, LDA_IDY)
+	bus.Mem.Write(0x0001, 0xFF)
+	bus.Mem.Write(0x00FF, 0x80)
+	bus.Mem.Write(0xB200, 0x33)
+
+	cpu := Cpu{}
+	cpu.YIndex = 0x80
+	cpu.Step(&bus)
+
+	// the pointer high byte wraps to $0000 which holds the opcode
+	assertAccesses(t, bus.log,
+		read(0x0000, LDA_IDY),
+		read(0x0001, 0xFF),
+		read(0x00FF, 0x80),
+		read(0x0000, LDA_IDY),
+		read(0xB100, 0x00),
+		read(0xB200, 0x33),
+	)
+}
+
+func TestJsrStackTraffic(t *testing.T) {
+	bus := recordingBus{Mem: &Mem{}}
+	bus.Mem.Write(0x0600, JSR_ABS)
+	bus.Mem.Write(0x0601, 0x34)
+	bus.Mem.Write(0x0602, 0x12)
+
+	cpu := Cpu{}
+	cpu.ProgramCounter = 0x0600
+	cpu.StackPointer = 0xFF
+	cpu.Step(&bus)
+
+	assertAccesses(t, bus.log,
+		read(0x0600, JSR_ABS),
+		read(0x0601, 0x34),
+		read(0x01FF, 0x00),
+		write(0x01FF, 0x06),
+		write(0x01FE, 0x02),
+		read(0x0602, 0x12),
+	)
+	asrt.Equal(t, cpu.ProgramCounter, Register16(0x1234))
+}
+
+func TestTickAdvancesOneCycle(t *testing.T) {
+	bus := recordingBus{Mem: &Mem{}}
+	bus.Mem.Write(0x0000, LDA_ABS)
+	bus.Mem.Write(0x0001, 0x00)
+	bus.Mem.Write(0x0002, 0x12)
+	bus.Mem.Write(0x1200, 0x55)
+	bus.Mem.Write(0x0003, NOP_IMP)
+
+	cpu := Cpu{}
+	for i := 1; i <= Opcodes[LDA_ABS].Cycles; i++ {
+		asrt.Equal(t, cpu.Tick(&bus), nil)
+		asrt.Equal(t, cpu.Cycle, i)
+		asrt.Equal(t, len(bus.log), i)
+	}
+	asrt.Equal(t, cpu.Accumulator, Register8(0x55))
+	asrt.Equal(t, cpu.ProgramCounter, Register16(0x0003))
+
+	// Step completes an instruction left half done by Tick
+	cpu.Tick(&bus)
+	cpu.Step(&bus)
+	asrt.Equal(t, cpu.Cycle, Opcodes[LDA_ABS].Cycles+Opcodes[NOP_IMP].Cycles)
+	asrt.Equal(t, cpu.ProgramCounter, Register16(0x0004))
+}
+
+func TestWaitingReadsTheNextOpcode(t *testing.T) {
+	bus := recordingBus{Mem: &Mem{}}
+	bus.Mem.Write(0x0000, WAI_IMP)
+	bus.Mem.Write(0x0001, INX_IMP)
+
+	cpu := Cpu{Variant: WDC65C02}
+	cpu.Step(&bus)
+	bus.log = nil
+	cycle := cpu.Cycle
+	cpu.Tick(&bus)
+	cpu.Tick(&bus)
+
+	assertAccesses(t, bus.log,
+		read(0x0001, INX_IMP),
+		read(0x0001, INX_IMP),
+	)
+	asrt.Equal(t, cpu.Cycle, cycle+2)
+	asrt.Equal(t, cpu.XIndex, Register8(0x00))
+}
+
+func TestInterruptSequenceTraffic(t *testing.T) {
+	bus := recordingBus{Mem: &Mem{}}
+	bus.Mem.Write(IrqVector, 0x00)
+	bus.Mem.Write(IrqVector+1, 0x80)
+
+	cpu := Cpu{}
+	cpu.ProgramCounter = 0x0600
+	cpu.StackPointer = 0xFF
+	cpu.SetIRQ(true)
+	cpu.Step(&bus)
+
+	assertAccesses(t, bus.log,
+		read(0x0600, 0x00),
+		read(0x0600, 0x00),
+		write(0x01FF, 0x06),
+		write(0x01FE, 0x00),
+		write(0x01FD, Break2),
+		read(IrqVector, 0x00),
+		read(IrqVector+1, 0x80),
+	)
+}
+
+func TestNmiHijacksBrk(t *testing.T) {
+	bus := Mem{}
+	bus[0x0600] = BRK_IMP
+	bus[IrqVector] = 0x00
+	bus[IrqVector+1] = 0x80
+	bus[NmiVector] = 0x00
+	bus[NmiVector+1] = 0x90
+
+	cpu := Cpu{}
+	cpu.ProgramCounter = 0x0600
+	cpu.StackPointer = 0xFF
+	for i := 0; i < 3; i++ {
+		cpu.Tick(&bus)
+	}
+	cpu.SetNMI(true)
+	cpu.Step(&bus)
+
+	asrt.Equal(t, cpu.ProgramCounter, Register16(0x9000))
+	asrt.True(t, bus[0x01FD]&Break != 0)
+	asrt.Equal(t, cpu.Cycle, Opcodes[BRK_IMP].Cycles)
+}
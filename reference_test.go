@@ -0,0 +1,401 @@
+package go6502
+
+// reference is a plain model of the official opcodes of the NMOS 6502,
+// written from the datasheet and the decimal mode appendix of 6502.org
+// rather than from the microcode, the other side of FuzzStep. It executes
+// whole instructions on a flat memory, without cycles nor interrupts.
+type reference struct {
+	a, x, y, s, p uint8
+	pc            uint16
+	mem           *Mem
+}
+
+const (
+	refC uint8 = 1 << iota
+	refZ
+	refI
+	refD
+	refB
+	refU
+	refV
+	refN
+)
+
+type refMode int
+
+const (
+	refImp refMode = iota
+	refAcc
+	refImm
+	refZp
+	refZpx
+	refZpy
+	refAbs
+	refAbx
+	refAby
+	refInd
+	refIzx
+	refIzy
+	refRel
+)
+
+type refOpcode struct {
+	name string
+	mode refMode
+}
+
+// referenceOpcodes are the 151 official opcodes
+var referenceOpcodes = map[uint8]refOpcode{
+	0x69: {"ADC", refImm}, 0x65: {"ADC", refZp}, 0x75: {"ADC", refZpx}, 0x6D: {"ADC", refAbs},
+	0x7D: {"ADC", refAbx}, 0x79: {"ADC", refAby}, 0x61: {"ADC", refIzx}, 0x71: {"ADC", refIzy},
+	0x29: {"AND", refImm}, 0x25: {"AND", refZp}, 0x35: {"AND", refZpx}, 0x2D: {"AND", refAbs},
+	0x3D: {"AND", refAbx}, 0x39: {"AND", refAby}, 0x21: {"AND", refIzx}, 0x31: {"AND", refIzy},
+	0x0A: {"ASL", refAcc}, 0x06: {"ASL", refZp}, 0x16: {"ASL", refZpx}, 0x0E: {"ASL", refAbs}, 0x1E: {"ASL", refAbx},
+	0x90: {"BCC", refRel}, 0xB0: {"BCS", refRel}, 0xF0: {"BEQ", refRel}, 0x30: {"BMI", refRel},
+	0xD0: {"BNE", refRel}, 0x10: {"BPL", refRel}, 0x50: {"BVC", refRel}, 0x70: {"BVS", refRel},
+	0x24: {"BIT", refZp}, 0x2C: {"BIT", refAbs},
+	0x00: {"BRK", refImp},
+	0x18: {"CLC", refImp}, 0xD8: {"CLD", refImp}, 0x58: {"CLI", refImp}, 0xB8: {"CLV", refImp},
+	0xC9: {"CMP", refImm}, 0xC5: {"CMP", refZp}, 0xD5: {"CMP", refZpx}, 0xCD: {"CMP", refAbs},
+	0xDD: {"CMP", refAbx}, 0xD9: {"CMP", refAby}, 0xC1: {"CMP", refIzx}, 0xD1: {"CMP", refIzy},
+	0xE0: {"CPX", refImm}, 0xE4: {"CPX", refZp}, 0xEC: {"CPX", refAbs},
+	0xC0: {"CPY", refImm}, 0xC4: {"CPY", refZp}, 0xCC: {"CPY", refAbs},
+	0xC6: {"DEC", refZp}, 0xD6: {"DEC", refZpx}, 0xCE: {"DEC", refAbs}, 0xDE: {"DEC", refAbx},
+	0xCA: {"DEX", refImp}, 0x88: {"DEY", refImp},
+	0x49: {"EOR", refImm}, 0x45: {"EOR", refZp}, 0x55: {"EOR", refZpx}, 0x4D: {"EOR", refAbs},
+	0x5D: {"EOR", refAbx}, 0x59: {"EOR", refAby}, 0x41: {"EOR", refIzx}, 0x51: {"EOR", refIzy},
+	0xE6: {"INC", refZp}, 0xF6: {"INC", refZpx}, 0xEE: {"INC", refAbs}, 0xFE: {"INC", refAbx},
+	0xE8: {"INX", refImp}, 0xC8: {"INY", refImp},
+	0x4C: {"JMP", refAbs}, 0x6C: {"JMP", refInd},
+	0x20: {"JSR", refImp},
+	0xA9: {"LDA", refImm}, 0xA5: {"LDA", refZp}, 0xB5: {"LDA", refZpx}, 0xAD: {"LDA", refAbs},
+	0xBD: {"LDA", refAbx}, 0xB9: {"LDA", refAby}, 0xA1: {"LDA", refIzx}, 0xB1: {"LDA", refIzy},
+	0xA2: {"LDX", refImm}, 0xA6: {"LDX", refZp}, 0xB6: {"LDX", refZpy}, 0xAE: {"LDX", refAbs}, 0xBE: {"LDX", refAby},
+	0xA0: {"LDY", refImm}, 0xA4: {"LDY", refZp}, 0xB4: {"LDY", refZpx}, 0xAC: {"LDY", refAbs}, 0xBC: {"LDY", refAbx},
+	0x4A: {"LSR", refAcc}, 0x46: {"LSR", refZp}, 0x56: {"LSR", refZpx}, 0x4E: {"LSR", refAbs}, 0x5E: {"LSR", refAbx},
+	0xEA: {"NOP", refImp},
+	0x09: {"ORA", refImm}, 0x05: {"ORA", refZp}, 0x15: {"ORA", refZpx}, 0x0D: {"ORA", refAbs},
+	0x1D: {"ORA", refAbx}, 0x19: {"ORA", refAby}, 0x01: {"ORA", refIzx}, 0x11: {"ORA", refIzy},
+	0x48: {"PHA", refImp}, 0x08: {"PHP", refImp}, 0x68: {"PLA", refImp}, 0x28: {"PLP", refImp},
+	0x2A: {"ROL", refAcc}, 0x26: {"ROL", refZp}, 0x36: {"ROL", refZpx}, 0x2E: {"ROL", refAbs}, 0x3E: {"ROL", refAbx},
+	0x6A: {"ROR", refAcc}, 0x66: {"ROR", refZp}, 0x76: {"ROR", refZpx}, 0x6E: {"ROR", refAbs}, 0x7E: {"ROR", refAbx},
+	0x40: {"RTI", refImp}, 0x60: {"RTS", refImp},
+	0xE9: {"SBC", refImm}, 0xE5: {"SBC", refZp}, 0xF5: {"SBC", refZpx}, 0xED: {"SBC", refAbs},
+	0xFD: {"SBC", refAbx}, 0xF9: {"SBC", refAby}, 0xE1: {"SBC", refIzx}, 0xF1: {"SBC", refIzy},
+	0x38: {"SEC", refImp}, 0xF8: {"SED", refImp}, 0x78: {"SEI", refImp},
+	0x85: {"STA", refZp}, 0x95: {"STA", refZpx}, 0x8D: {"STA", refAbs}, 0x9D: {"STA", refAbx},
+	0x99: {"STA", refAby}, 0x81: {"STA", refIzx}, 0x91: {"STA", refIzy},
+	0x86: {"STX", refZp}, 0x96: {"STX", refZpy}, 0x8E: {"STX", refAbs},
+	0x84: {"STY", refZp}, 0x94: {"STY", refZpx}, 0x8C: {"STY", refAbs},
+	0xAA: {"TAX", refImp}, 0xA8: {"TAY", refImp}, 0xBA: {"TSX", refImp},
+	0x8A: {"TXA", refImp}, 0x9A: {"TXS", refImp}, 0x98: {"TYA", refImp},
+}
+
+func (r *reference) fetch() uint8 {
+	v := r.mem[r.pc]
+	r.pc++
+	return v
+}
+
+func (r *reference) push(v uint8) {
+	r.mem[0x0100|uint16(r.s)] = v
+	r.s--
+}
+
+func (r *reference) pull() uint8 {
+	r.s++
+	return r.mem[0x0100|uint16(r.s)]
+}
+
+// word reads a pointer, the high byte comes from next
+func (r *reference) word(addr, next uint16) uint16 {
+	return uint16(r.mem[addr]) | uint16(r.mem[next])<<8
+}
+
+func (r *reference) flag(f uint8, on bool) {
+	if on {
+		r.p |= f
+	} else {
+		r.p &^= f
+	}
+}
+
+func (r *reference) nz(v uint8) uint8 {
+	r.flag(refZ, v == 0)
+	r.flag(refN, v&0x80 != 0)
+	return v
+}
+
+func (r *reference) carry() int {
+	return int(r.p & refC)
+}
+
+// address fetches the operand and returns the address it designates, the
+// branch target for refRel
+func (r *reference) address(mode refMode) uint16 {
+	switch mode {
+	case refImm:
+		r.pc++
+		return r.pc - 1
+	case refZp:
+		return uint16(r.fetch())
+	case refZpx:
+		return uint16(r.fetch() + r.x)
+	case refZpy:
+		return uint16(r.fetch() + r.y)
+	case refAbs:
+		lo := r.fetch()
+		return uint16(lo) | uint16(r.fetch())<<8
+	case refAbx:
+		return r.address(refAbs) + uint16(r.x)
+	case refAby:
+		return r.address(refAbs) + uint16(r.y)
+	case refInd:
+		// the high byte does not cross the page
+		ptr := r.address(refAbs)
+		return r.word(ptr, ptr&0xFF00|uint16(uint8(ptr)+1))
+	case refIzx:
+		zp := r.fetch() + r.x
+		return r.word(uint16(zp), uint16(zp+1))
+	case refIzy:
+		zp := r.fetch()
+		return r.word(uint16(zp), uint16(zp+1)) + uint16(r.y)
+	case refRel:
+		offset := int8(r.fetch())
+		return r.pc + uint16(offset)
+	}
+	return 0
+}
+
+func (r *reference) adc(v uint8) {
+	if r.p&refD == 0 {
+		sum := int(r.a) + int(v) + r.carry()
+		r.flag(refV, ^(r.a^v)&(r.a^uint8(sum))&0x80 != 0)
+		r.flag(refC, sum > 0xFF)
+		r.a = r.nz(uint8(sum))
+		return
+	}
+
+	// N and V come from the sum once the low digit is adjusted, Z from
+	// the binary sum
+	r.flag(refZ, uint8(int(r.a)+int(v)+r.carry()) == 0)
+	lo := int(r.a&0x0F) + int(v&0x0F) + r.carry()
+	if lo > 9 {
+		lo = (lo+6)&0x0F + 0x10
+	}
+	signed := int(int8(r.a&0xF0)) + int(int8(v&0xF0)) + lo
+	r.flag(refN, signed&0x80 != 0)
+	r.flag(refV, signed < -128 || signed > 127)
+	sum := int(r.a&0xF0) + int(v&0xF0) + lo
+	if sum > 0x9F {
+		sum += 0x60
+	}
+	r.flag(refC, sum > 0xFF)
+	r.a = uint8(sum)
+}
+
+func (r *reference) sbc(v uint8) {
+	borrow := 1 - r.carry()
+	diff := int(r.a) - int(v) - borrow
+	a := r.a
+	r.flag(refV, (r.a^v)&(r.a^uint8(diff))&0x80 != 0)
+	r.flag(refC, diff >= 0)
+	r.a = r.nz(uint8(diff))
+	if r.p&refD == 0 {
+		return
+	}
+
+	// the flags stay the binary ones
+	lo := int(a&0x0F) - int(v&0x0F) - borrow
+	if lo < 0 {
+		lo = (lo-6)&0x0F - 0x10
+	}
+	dec := int(a&0xF0) - int(v&0xF0) + lo
+	if dec < 0 {
+		dec -= 0x60
+	}
+	r.a = uint8(dec)
+}
+
+func (r *reference) compare(reg, v uint8) {
+	r.flag(refC, reg >= v)
+	r.nz(reg - v)
+}
+
+// modify applies f to the accumulator or to the memory at addr
+func (r *reference) modify(mode refMode, addr uint16, f func(uint8) uint8) {
+	if mode == refAcc {
+		r.a = r.nz(f(r.a))
+		return
+	}
+	r.mem[addr] = r.nz(f(r.mem[addr]))
+}
+
+func (r *reference) branch(taken bool, target uint16) {
+	if taken {
+		r.pc = target
+	}
+}
+
+// step executes one instruction, it reports false for an opcode that is not
+// official
+func (r *reference) step() bool {
+	op, ok := referenceOpcodes[r.fetch()]
+	if !ok {
+		return false
+	}
+	addr := r.address(op.mode)
+	value := func() uint8 { return r.mem[addr] }
+
+	switch op.name {
+	case "ADC":
+		r.adc(value())
+	case "SBC":
+		r.sbc(value())
+	case "AND":
+		r.a = r.nz(r.a & value())
+	case "EOR":
+		r.a = r.nz(r.a ^ value())
+	case "ORA":
+		r.a = r.nz(r.a | value())
+	case "CMP":
+		r.compare(r.a, value())
+	case "CPX":
+		r.compare(r.x, value())
+	case "CPY":
+		r.compare(r.y, value())
+	case "BIT":
+		v := value()
+		r.flag(refZ, r.a&v == 0)
+		r.flag(refN, v&0x80 != 0)
+		r.flag(refV, v&0x40 != 0)
+
+	case "ASL":
+		r.modify(op.mode, addr, func(v uint8) uint8 {
+			r.flag(refC, v&0x80 != 0)
+			return v << 1
+		})
+	case "LSR":
+		r.modify(op.mode, addr, func(v uint8) uint8 {
+			r.flag(refC, v&0x01 != 0)
+			return v >> 1
+		})
+	case "ROL":
+		r.modify(op.mode, addr, func(v uint8) uint8 {
+			in := r.p & refC
+			r.flag(refC, v&0x80 != 0)
+			return v<<1 | in
+		})
+	case "ROR":
+		r.modify(op.mode, addr, func(v uint8) uint8 {
+			in := (r.p & refC) << 7
+			r.flag(refC, v&0x01 != 0)
+			return v>>1 | in
+		})
+	case "INC":
+		r.modify(op.mode, addr, func(v uint8) uint8 { return v + 1 })
+	case "DEC":
+		r.modify(op.mode, addr, func(v uint8) uint8 { return v - 1 })
+
+	case "BCC":
+		r.branch(r.p&refC == 0, addr)
+	case "BCS":
+		r.branch(r.p&refC != 0, addr)
+	case "BNE":
+		r.branch(r.p&refZ == 0, addr)
+	case "BEQ":
+		r.branch(r.p&refZ != 0, addr)
+	case "BPL":
+		r.branch(r.p&refN == 0, addr)
+	case "BMI":
+		r.branch(r.p&refN != 0, addr)
+	case "BVC":
+		r.branch(r.p&refV == 0, addr)
+	case "BVS":
+		r.branch(r.p&refV != 0, addr)
+
+	case "JMP":
+		r.pc = addr
+	case "JSR":
+		// the high byte of the target is read after the return address
+		// is pushed
+		lo := r.fetch()
+		r.push(uint8(r.pc >> 8))
+		r.push(uint8(r.pc))
+		r.pc = uint16(lo) | uint16(r.mem[r.pc])<<8
+	case "RTS":
+		lo := r.pull()
+		r.pc = (uint16(lo) | uint16(r.pull())<<8) + 1
+	case "BRK":
+		r.pc++
+		r.push(uint8(r.pc >> 8))
+		r.push(uint8(r.pc))
+		r.push(r.p | refB | refU)
+		r.p |= refI
+		r.pc = r.word(0xFFFE, 0xFFFF)
+	case "RTI":
+		r.p = r.pull()
+		lo := r.pull()
+		r.pc = uint16(lo) | uint16(r.pull())<<8
+
+	case "PHA":
+		r.push(r.a)
+	case "PHP":
+		r.push(r.p | refB | refU)
+	case "PLA":
+		r.a = r.nz(r.pull())
+	case "PLP":
+		r.p = r.pull()
+
+	case "LDA":
+		r.a = r.nz(value())
+	case "LDX":
+		r.x = r.nz(value())
+	case "LDY":
+		r.y = r.nz(value())
+	case "STA":
+		r.mem[addr] = r.a
+	case "STX":
+		r.mem[addr] = r.x
+	case "STY":
+		r.mem[addr] = r.y
+
+	case "TAX":
+		r.x = r.nz(r.a)
+	case "TAY":
+		r.y = r.nz(r.a)
+	case "TXA":
+		r.a = r.nz(r.x)
+	case "TYA":
+		r.a = r.nz(r.y)
+	case "TSX":
+		r.x = r.nz(r.s)
+	case "TXS":
+		r.s = r.x
+	case "INX":
+		r.x = r.nz(r.x + 1)
+	case "INY":
+		r.y = r.nz(r.y + 1)
+	case "DEX":
+		r.x = r.nz(r.x - 1)
+	case "DEY":
+		r.y = r.nz(r.y - 1)
+
+	case "CLC":
+		r.p &^= refC
+	case "SEC":
+		r.p |= refC
+	case "CLI":
+		r.p &^= refI
+	case "SEI":
+		r.p |= refI
+	case "CLD":
+		r.p &^= refD
+	case "SED":
+		r.p |= refD
+	case "CLV":
+		r.p &^= refV
+	case "NOP":
+	}
+	return true
+}
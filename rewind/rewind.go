@@ -0,0 +1,252 @@
+// Package rewind records the execution of a cpu on a memory so that it can
+// run backwards. The history is made of checkpoints, a copy of the memory
+// taken every Interval instructions, each followed by the log of the
+// instructions executed after it: the cpu state before the instruction and
+// the bytes it wrote, with their previous value.
+//
+//	history := rewind.New(&cpu, &memory)
+//	for cpu.Cycle < 100000 {
+//		history.Step(&memory)
+//	}
+//	cycle, _ := history.LastWrite(0x0042)
+//	history.RunBackTo(cycle)
+//
+// Only the cpu and the memory are rewound, the state of other devices on
+// the bus is not. The call stack of a cpu with TrackCalls is rewound with
+// the cpu, its diagnostics are not.
+package rewind
+
+import (
+	"errors"
+	"sort"
+
+	"github.com/zehlt/go6502"
+)
+
+const (
+	DefaultInterval = 1024
+	DefaultDepth    = 64
+)
+
+var ErrOutOfHistory = errors.New("rewind: cycle before the recorded history")
+
+// write is a byte written to the memory by an instruction
+type write struct {
+	addr     uint16
+	old, new uint8
+}
+
+// instruction is the state before an instruction and what it wrote, calls
+// is the backtrace of a cpu tracking the calls
+type instruction struct {
+	cycle  int
+	cpu    []byte
+	calls  []go6502.Frame
+	writes []write
+}
+
+// checkpoint is the memory before its first instruction
+type checkpoint struct {
+	mem          go6502.Mem
+	instructions []instruction
+}
+
+// Buffer is the history of Cpu on Mem. It keeps Depth checkpoints, the
+// oldest one is dropped as a new one is taken, so the history covers about
+// Interval*Depth instructions and the memory used grows with both. A zero
+// Interval is DefaultInterval, a zero Depth keeps every checkpoint.
+type Buffer struct {
+	Cpu      *go6502.Cpu
+	Mem      *go6502.Mem
+	Interval int
+	Depth    int
+
+	checkpoints []*checkpoint
+}
+
+// New returns an empty history for cpu on mem with the default interval and
+// depth.
+func New(cpu *go6502.Cpu, mem *go6502.Mem) *Buffer {
+	return &Buffer{Cpu: cpu, Mem: mem, Interval: DefaultInterval, Depth: DefaultDepth}
+}
+
+// Step executes one instruction on bus and records it. The writes of the
+// cpu must reach Mem through bus, the bytes are recorded as they are in Mem
+// after each write.
+func (b *Buffer) Step(bus go6502.Bus) error {
+	interval := b.Interval
+	if interval <= 0 {
+		interval = DefaultInterval
+	}
+	last := b.last()
+	if last == nil || len(last.instructions) >= interval {
+		last = b.checkpoint()
+	}
+
+	state, err := b.Cpu.MarshalBinary()
+	if err != nil {
+		return err
+	}
+	in := instruction{cycle: b.Cpu.Cycle, cpu: state}
+	if b.Cpu.TrackCalls {
+		in.calls = b.Cpu.Backtrace()
+	}
+	last.instructions = append(last.instructions, in)
+	return b.Cpu.Step(recorder{b, &last.instructions[len(last.instructions)-1], bus})
+}
+
+// StepBack restores the state before the last recorded instruction, it
+// reports false when the history is empty.
+func (b *Buffer) StepBack() bool {
+	for len(b.checkpoints) > 0 {
+		last := b.last()
+		if n := len(last.instructions); n > 0 {
+			b.undo(last.instructions[n-1])
+			last.instructions = last.instructions[:n-1]
+			return true
+		}
+		// the current state is the checkpoint itself
+		b.checkpoints = b.checkpoints[:len(b.checkpoints)-1]
+	}
+	return false
+}
+
+// RunBackTo restores the state before the instruction that was running at
+// cycle, the instructions after it are dropped from the history. A cycle
+// that is not in the past leaves the state as it is.
+func (b *Buffer) RunBackTo(cycle int) error {
+	if cycle >= b.Cpu.Cycle {
+		return nil
+	}
+	for i := len(b.checkpoints) - 1; i >= 0; i-- {
+		c := b.checkpoints[i]
+		// the last instruction that started at or before cycle
+		n := sort.Search(len(c.instructions), func(j int) bool {
+			return c.instructions[j].cycle > cycle
+		}) - 1
+		if n < 0 {
+			continue
+		}
+
+		if i == len(b.checkpoints)-1 {
+			for len(c.instructions) > n {
+				b.StepBack()
+			}
+			return nil
+		}
+
+		// older checkpoints are replayed forward rather than undone
+		*b.Mem = c.mem
+		for _, in := range c.instructions[:n] {
+			for _, w := range in.writes {
+				b.Mem[w.addr] = w.new
+			}
+		}
+		if err := b.restore(c.instructions[n]); err != nil {
+			return err
+		}
+		c.instructions = c.instructions[:n]
+		b.checkpoints = b.checkpoints[:i+1]
+		return nil
+	}
+	return ErrOutOfHistory
+}
+
+// LastWrite returns the cycle of the last recorded instruction that wrote
+// to addr, RunBackTo that cycle returns to before the write.
+func (b *Buffer) LastWrite(addr uint16) (int, bool) {
+	for i := len(b.checkpoints) - 1; i >= 0; i-- {
+		instructions := b.checkpoints[i].instructions
+		for j := len(instructions) - 1; j >= 0; j-- {
+			for _, w := range instructions[j].writes {
+				if w.addr == addr {
+					return instructions[j].cycle, true
+				}
+			}
+		}
+	}
+	return 0, false
+}
+
+// Oldest returns the cycle of the oldest state in the history, the current
+// cycle when it is empty.
+func (b *Buffer) Oldest() int {
+	for _, c := range b.checkpoints {
+		if len(c.instructions) > 0 {
+			return c.instructions[0].cycle
+		}
+	}
+	return b.Cpu.Cycle
+}
+
+// Clear forgets the history. It must be called after the cpu or the memory
+// are changed other than through Step, rewinding would mix both states.
+func (b *Buffer) Clear() {
+	b.checkpoints = nil
+}
+
+func (b *Buffer) last() *checkpoint {
+	if len(b.checkpoints) == 0 {
+		return nil
+	}
+	return b.checkpoints[len(b.checkpoints)-1]
+}
+
+func (b *Buffer) checkpoint() *checkpoint {
+	if b.Depth > 0 && len(b.checkpoints) >= b.Depth {
+		b.checkpoints = append(b.checkpoints[:0], b.checkpoints[1:]...)
+	}
+	c := &checkpoint{mem: *b.Mem}
+	b.checkpoints = append(b.checkpoints, c)
+	return c
+}
+
+func (b *Buffer) undo(in instruction) {
+	for i := len(in.writes) - 1; i >= 0; i-- {
+		b.Mem[in.writes[i].addr] = in.writes[i].old
+	}
+	b.restore(in)
+}
+
+// restore puts the cpu back in its state before in
+func (b *Buffer) restore(in instruction) error {
+	if err := b.Cpu.UnmarshalBinary(in.cpu); err != nil {
+		return err
+	}
+	b.Cpu.SetBacktrace(in.calls)
+	return nil
+}
+
+// recorder logs the writes of the instruction in progress
+type recorder struct {
+	b   *Buffer
+	in  *instruction
+	bus go6502.Bus
+}
+
+func (r recorder) Read(addr uint16) uint8 {
+	return r.bus.Read(addr)
+}
+
+func (r recorder) Write(addr uint16, data uint8) {
+	old := r.b.Mem[addr]
+	r.bus.Write(addr, data)
+	r.in.writes = append(r.in.writes, write{addr: addr, old: old, new: r.b.Mem[addr]})
+}
+
+func (r recorder) Peek(addr uint16) uint8 {
+	return go6502.Peek(r.bus, addr)
+}
+
+func (r recorder) Poke(addr uint16, data uint8) {
+	go6502.Poke(r.bus, addr, data)
+}
+
+func (r recorder) ReadWord(addr uint16) uint16 {
+	return uint16(r.Read(addr)) | uint16(r.Read(addr+1))<<8
+}
+
+func (r recorder) WriteWord(addr uint16, data uint16) {
+	r.Write(addr, uint8(data))
+	r.Write(addr+1, uint8(data>>8))
+}
@@ -0,0 +1,162 @@
+package rewind
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/zehlt/go6502"
+	"github.com/zehlt/go6502/asrt"
+)
+
+type state struct {
+	cpu []byte
+	mem go6502.Mem
+}
+
+// fills $0200-$02FF with a counter, pushing and pulling through the stack
+var program = []uint8{
+	go6502.LDX_IMM, 0x00,
+	go6502.TXA_IMP,
+	go6502.PHA_IMP,
+	go6502.PLA_IMP,
+	go6502.STA_ABX, 0x00, 0x02,
+	go6502.INC_ZER, 0x10,
+	go6502.INX_IMP,
+	go6502.BNE_REL, 0xF5,
+	go6502.JMP_ABS, 0x00, 0x06,
+}
+
+func setup() (*go6502.Cpu, *go6502.Mem) {
+	memory := &go6502.Mem{}
+	memory.WriteBytes(0x0600, program)
+	cpu := &go6502.Cpu{}
+	cpu.ProgramCounter = 0x0600
+	cpu.StackPointer = 0xFF
+	return cpu, memory
+}
+
+func save(t *testing.T, cpu *go6502.Cpu, memory *go6502.Mem) state {
+	t.Helper()
+	data, err := cpu.MarshalBinary()
+	asrt.Equal(t, err, nil)
+	return state{cpu: data, mem: *memory}
+}
+
+func assertState(t *testing.T, cpu *go6502.Cpu, memory *go6502.Mem, expected state) {
+	t.Helper()
+	got := save(t, cpu, memory)
+	asrt.True(t, bytes.Equal(got.cpu, expected.cpu))
+	asrt.True(t, got.mem == expected.mem)
+}
+
+// record steps n instructions and returns the state before each of them
+func record(t *testing.T, history *Buffer, n int) []state {
+	var states []state
+	for i := 0; i < n; i++ {
+		states = append(states, save(t, history.Cpu, history.Mem))
+		asrt.Equal(t, history.Step(history.Mem), nil)
+	}
+	return states
+}
+
+func TestStepBack(t *testing.T) {
+	cpu, memory := setup()
+	history := New(cpu, memory)
+	history.Interval = 16
+	states := record(t, history, 100)
+
+	for i := len(states) - 1; i >= 0; i-- {
+		asrt.True(t, history.StepBack())
+		assertState(t, cpu, memory, states[i])
+	}
+	asrt.False(t, history.StepBack())
+
+	// the history records again from there
+	again := record(t, history, 10)
+	asrt.True(t, again[5].mem == states[5].mem)
+	asrt.True(t, history.StepBack())
+	assertState(t, cpu, memory, states[9])
+}
+
+func TestRunBackTo(t *testing.T) {
+	cpu, memory := setup()
+	history := New(cpu, memory)
+	history.Interval = 16
+	states := record(t, history, 200)
+	cycles := make([]int, len(states))
+	for i := range states {
+		var c go6502.Cpu
+		asrt.Equal(t, c.UnmarshalBinary(states[i].cpu), nil)
+		cycles[i] = c.Cycle
+	}
+
+	// within the last checkpoint, then replayed from older ones
+	for _, i := range []int{195, 150, 64, 63, 3} {
+		asrt.Equal(t, history.RunBackTo(cycles[i]), nil)
+		assertState(t, cpu, memory, states[i])
+	}
+	// a cycle in the middle of an instruction goes before it
+	asrt.Equal(t, history.RunBackTo(cycles[1]+1), nil)
+	assertState(t, cpu, memory, states[1])
+	asrt.Equal(t, history.Oldest(), cycles[0])
+}
+
+func TestCallsAreRewound(t *testing.T) {
+	memory := &go6502.Mem{}
+	memory.WriteBytes(0x0600, []uint8{go6502.JSR_ABS, 0x10, 0x06})
+	memory.WriteBytes(0x0610, []uint8{go6502.JSR_ABS, 0x20, 0x06, go6502.RTS_IMP})
+	memory.WriteBytes(0x0620, []uint8{go6502.NOP_IMP, go6502.RTS_IMP})
+	cpu := &go6502.Cpu{TrackCalls: true}
+	cpu.ProgramCounter = 0x0600
+	cpu.StackPointer = 0xFF
+	history := New(cpu, memory)
+	history.Interval = 2
+	for i := 0; i < 5; i++ {
+		asrt.Equal(t, history.Step(memory), nil)
+	}
+	asrt.Equal(t, len(cpu.Backtrace()), 0)
+
+	asrt.True(t, history.StepBack())
+	asrt.Equal(t, len(cpu.Backtrace()), 1)
+	// replayed from an older checkpoint
+	asrt.Equal(t, history.RunBackTo(history.Oldest()+6), nil)
+	frames := cpu.Backtrace()
+	asrt.Equal(t, len(frames), 1)
+	asrt.Equal(t, frames[0].Target, uint16(0x0610))
+	asrt.Equal(t, history.Step(memory), nil)
+	asrt.Equal(t, len(cpu.Backtrace()), 2)
+	asrt.Equal(t, len(cpu.Diagnostics()), 0)
+}
+
+func TestDepthLimitsTheHistory(t *testing.T) {
+	cpu, memory := setup()
+	history := New(cpu, memory)
+	history.Interval = 10
+	history.Depth = 3
+	states := record(t, history, 45)
+
+	asrt.True(t, errors.Is(history.RunBackTo(0), ErrOutOfHistory))
+	asrt.Equal(t, history.RunBackTo(history.Oldest()), nil)
+	assertState(t, cpu, memory, states[20])
+}
+
+func TestLastWrite(t *testing.T) {
+	cpu, memory := setup()
+	history := New(cpu, memory)
+	record(t, history, 40)
+	asrt.Equal(t, memory[0x0204], uint8(0x04))
+
+	cycle, ok := history.LastWrite(0x0204)
+	asrt.True(t, ok)
+	asrt.Equal(t, history.RunBackTo(cycle), nil)
+	asrt.Equal(t, memory[0x0204], uint8(0x00))
+	asrt.Equal(t, uint16(cpu.ProgramCounter), uint16(0x0605))
+	asrt.Equal(t, history.Step(memory), nil)
+	asrt.Equal(t, memory[0x0204], uint8(0x04))
+
+	_, ok = history.LastWrite(0x0300)
+	asrt.False(t, ok)
+	history.Clear()
+	asrt.False(t, history.StepBack())
+}
@@ -0,0 +1,118 @@
+package go6502
+
+import "context"
+
+// StopReason tells why a bounded run returned.
+type StopReason int
+
+const (
+	// ConditionMet means StopWhen or the condition of RunUntil held
+	ConditionMet StopReason = iota
+	CycleBudget
+	InstructionBudget
+	CpuHalted
+	Canceled
+	Faulted
+)
+
+func (r StopReason) String() string {
+	switch r {
+	case ConditionMet:
+		return "condition met"
+	case CycleBudget:
+		return "cycle budget spent"
+	case InstructionBudget:
+		return "instruction budget spent"
+	case CpuHalted:
+		return "cpu halted"
+	case Canceled:
+		return "canceled"
+	case Faulted:
+		return "faulted"
+	default:
+		return "unknown"
+	}
+}
+
+// contextInterval is the number of instructions between two checks of the
+// context, so that a deadline costs close to nothing
+const contextInterval = 1024
+
+// stopped reports whether a run must stop before the next cycle, StopWhen
+// is only checked between instructions
+func (c *Cpu) stopped(bus Bus) (StopReason, bool) {
+	if c.halted {
+		return CpuHalted, true
+	}
+	if c.program == nil && c.StopWhen != nil && c.StopWhen(c, bus) {
+		return ConditionMet, true
+	}
+	return 0, false
+}
+
+// RunFor runs for cycles clock cycles. It stops on the exact cycle, maybe
+// in the middle of an instruction that the next Step or Tick completes.
+// Like all the bounded runs, it also stops when StopWhen holds, when the
+// cpu halts or with Faulted and the error of an instruction that fails.
+func (c *Cpu) RunFor(bus Bus, cycles int) (StopReason, error) {
+	end := c.Cycle + cycles
+	for c.Cycle < end {
+		if reason, ok := c.stopped(bus); ok {
+			return reason, nil
+		}
+		if err := c.Tick(bus); err != nil {
+			return Faulted, err
+		}
+	}
+	return CycleBudget, nil
+}
+
+// RunInstructions executes n instructions, the interrupt sequences count
+// as instructions.
+func (c *Cpu) RunInstructions(bus Bus, n int) (StopReason, error) {
+	for i := 0; i < n; i++ {
+		if reason, ok := c.stopped(bus); ok {
+			return reason, nil
+		}
+		if err := c.Step(bus); err != nil {
+			return Faulted, err
+		}
+	}
+	return InstructionBudget, nil
+}
+
+// RunUntil executes instructions until until reports true, it is checked
+// before each instruction.
+func (c *Cpu) RunUntil(bus Bus, until func(*Cpu) bool) (StopReason, error) {
+	for !until(c) {
+		if reason, ok := c.stopped(bus); ok {
+			return reason, nil
+		}
+		if err := c.Step(bus); err != nil {
+			return Faulted, err
+		}
+	}
+	return ConditionMet, nil
+}
+
+// RunContext executes instructions until ctx is done, it returns Canceled
+// with the error of ctx then. The context is checked every contextInterval
+// instructions.
+func (c *Cpu) RunContext(ctx context.Context, bus Bus) (StopReason, error) {
+	done := ctx.Done()
+	for i := 0; ; i++ {
+		if done != nil && i%contextInterval == 0 {
+			select {
+			case <-done:
+				return Canceled, ctx.Err()
+			default:
+			}
+		}
+		if reason, ok := c.stopped(bus); ok {
+			return reason, nil
+		}
+		if err := c.Step(bus); err != nil {
+			return Faulted, err
+		}
+	}
+}
@@ -0,0 +1,96 @@
+package go6502
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/zehlt/go6502/asrt"
+)
+
+// a guest stuck in a loop of 3 cycles
+func loopForever() (*Cpu, *Mem) {
+	memory := &Mem{}
+	memory.WriteBytes(0x0600, []uint8{JMP_ABS, 0x00, 0x06})
+	cpu := &Cpu{}
+	cpu.ProgramCounter = 0x0600
+	return cpu, memory
+}
+
+func TestRunForStopsOnTheExactCycle(t *testing.T) {
+	cpu, memory := loopForever()
+	reason, err := cpu.RunFor(memory, 1000)
+	asrt.Equal(t, err, nil)
+	asrt.Equal(t, reason, CycleBudget)
+	asrt.Equal(t, cpu.Cycle, 1000)
+
+	// 1000 is not a multiple of 3, the jump is completed by Step
+	asrt.Equal(t, cpu.Step(memory), nil)
+	asrt.Equal(t, cpu.Cycle, 1002)
+}
+
+func TestRunInstructions(t *testing.T) {
+	cpu, memory := loopForever()
+	reason, err := cpu.RunInstructions(memory, 10)
+	asrt.Equal(t, err, nil)
+	asrt.Equal(t, reason, InstructionBudget)
+	asrt.Equal(t, cpu.Cycle, 30)
+}
+
+func TestRunUntil(t *testing.T) {
+	memory := &Mem{}
+	memory.WriteBytes(0x0600, []uint8{INX_IMP, JMP_ABS, 0x00, 0x06})
+	cpu := &Cpu{}
+	cpu.ProgramCounter = 0x0600
+
+	reason, err := cpu.RunUntil(memory, func(c *Cpu) bool { return c.XIndex == 0x20 })
+	asrt.Equal(t, err, nil)
+	asrt.Equal(t, reason, ConditionMet)
+	asrt.Equal(t, cpu.XIndex, Register8(0x20))
+	asrt.Equal(t, cpu.ProgramCounter, Register16(0x0601))
+}
+
+func TestBoundedRunsHonourStopWhenHaltsAndFaults(t *testing.T) {
+	cpu, memory := loopForever()
+	cpu.StopWhen = func(c *Cpu, bus Bus) bool { return c.Cycle >= 30 }
+	reason, _ := cpu.RunFor(memory, 1000)
+	asrt.Equal(t, reason, ConditionMet)
+	asrt.Equal(t, cpu.Cycle, 30)
+
+	memory.WriteBytes(0x0600, []uint8{NOP_IMP, STP_IMP})
+	cpu = &Cpu{Variant: WDC65C02}
+	cpu.ProgramCounter = 0x0600
+	reason, err := cpu.RunUntil(memory, func(*Cpu) bool { return false })
+	asrt.Equal(t, err, nil)
+	asrt.Equal(t, reason, CpuHalted)
+	asrt.Equal(t, cpu.Run(memory), nil)
+
+	memory[0x0601] = JAM_IMP_12
+	cpu = &Cpu{}
+	cpu.ProgramCounter = 0x0600
+	reason, err = cpu.RunInstructions(memory, 10)
+	asrt.Equal(t, reason, Faulted)
+	asrt.True(t, errors.Is(err, ErrJammed))
+}
+
+func TestRunContext(t *testing.T) {
+	cpu, memory := loopForever()
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	reason, err := cpu.RunContext(ctx, memory)
+	asrt.Equal(t, reason, Canceled)
+	asrt.True(t, errors.Is(err, context.DeadlineExceeded))
+	asrt.True(t, cpu.Cycle > 0)
+
+	cpu.StopWhen = StopAtAddress(0x0600)
+	reason, err = cpu.RunContext(context.Background(), memory)
+	asrt.Equal(t, err, nil)
+	asrt.Equal(t, reason, ConditionMet)
+}
+
+func TestStopReasonString(t *testing.T) {
+	asrt.Equal(t, CycleBudget.String(), "cycle budget spent")
+	asrt.Equal(t, StopReason(42).String(), "unknown")
+}
@@ -0,0 +1,186 @@
+package go6502
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// Single step vectors in the ProcessorTests format,
+// https://github.com/TomHarte/ProcessorTests
+// Each vector gives the state before and after one instruction and the
+// bus activity of every cycle. singlestep.json holds hand written vectors,
+// the compares, PHP, PLP and decimal ADC and SBC among them, and always
+// runs. The full set is run when its 6502/v1 directory is copied into
+// testdata/ProcessorTests.
+
+const processorTestsDir = "testdata/ProcessorTests/6502/v1"
+
+// the break and unused bits only exist on the stack, they are not compared
+const statusMask = ^uint8(Break | Break2)
+
+// the JAM vectors expect the bus to be hammered with $FFFF reads
+var singleStepSkipped = map[uint8]bool{
+	0x02: true, 0x12: true, 0x22: true, 0x32: true, 0x42: true, 0x52: true,
+	0x62: true, 0x72: true, 0x92: true, 0xB2: true, 0xD2: true, 0xF2: true,
+}
+
+type singleStepState struct {
+	PC  uint16     `json:"pc"`
+	S   uint8      `json:"s"`
+	A   uint8      `json:"a"`
+	X   uint8      `json:"x"`
+	Y   uint8      `json:"y"`
+	P   uint8      `json:"p"`
+	RAM [][2]int32 `json:"ram"`
+}
+
+type singleStepVector struct {
+	Name    string           `json:"name"`
+	Initial singleStepState  `json:"initial"`
+	Final   singleStepState  `json:"final"`
+	Cycles  [][3]interface{} `json:"cycles"`
+}
+
+func loadVectors(path string) ([]singleStepVector, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var vectors []singleStepVector
+	if err := json.Unmarshal(data, &vectors); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return vectors, nil
+}
+
+func (v *singleStepVector) accesses() []access {
+	accesses := make([]access, 0, len(v.Cycles))
+	for _, cycle := range v.Cycles {
+		addr, _ := cycle[0].(float64)
+		data, _ := cycle[1].(float64)
+		kind, _ := cycle[2].(string)
+		accesses = append(accesses, access{addr: uint16(addr), data: uint8(data), write: kind == "write"})
+	}
+	return accesses
+}
+
+// runVector executes the vector and returns the differences with the
+// expected final state, an empty slice means the vector passed
+func runVector(v *singleStepVector) []string {
+	bus := recordingBus{Mem: &Mem{}}
+	for _, cell := range v.Initial.RAM {
+		bus.Mem.Write(uint16(cell[0]), uint8(cell[1]))
+	}
+
+	cpu := Cpu{}
+	cpu.ProgramCounter = Register16(v.Initial.PC)
+	cpu.StackPointer = Register8(v.Initial.S)
+	cpu.Accumulator = Register8(v.Initial.A)
+	cpu.XIndex = Register8(v.Initial.X)
+	cpu.YIndex = Register8(v.Initial.Y)
+	cpu.Status = Register8(v.Initial.P)
+
+	var diffs []string
+	if err := cpu.Step(&bus); err != nil {
+		diffs = append(diffs, err.Error())
+	}
+
+	registers := []struct {
+		name          string
+		got, expected uint16
+	}{
+		{"pc", uint16(cpu.ProgramCounter), v.Final.PC},
+		{"s", uint16(cpu.StackPointer), uint16(v.Final.S)},
+		{"a", uint16(cpu.Accumulator), uint16(v.Final.A)},
+		{"x", uint16(cpu.XIndex), uint16(v.Final.X)},
+		{"y", uint16(cpu.YIndex), uint16(v.Final.Y)},
+		{"p", uint16(uint8(cpu.Status) & statusMask), uint16(v.Final.P & statusMask)},
+	}
+	for _, r := range registers {
+		if r.got != r.expected {
+			diffs = append(diffs, fmt.Sprintf("%s: got $%02X, expected $%02X", r.name, r.got, r.expected))
+		}
+	}
+
+	for _, cell := range v.Final.RAM {
+		got := bus.Mem.Read(uint16(cell[0]))
+		if got != uint8(cell[1]) {
+			diffs = append(diffs, fmt.Sprintf("ram $%04X: got $%02X, expected $%02X", cell[0], got, cell[1]))
+		}
+	}
+
+	expected := v.accesses()
+	for i := 0; i < len(expected) || i < len(bus.log); i++ {
+		switch {
+		case i >= len(bus.log):
+			diffs = append(diffs, fmt.Sprintf("cycle %d: missing %v", i+1, expected[i]))
+		case i >= len(expected):
+			diffs = append(diffs, fmt.Sprintf("cycle %d: extra %v", i+1, bus.log[i]))
+		case bus.log[i] != expected[i]:
+			diffs = append(diffs, fmt.Sprintf("cycle %d: got %v, expected %v", i+1, bus.log[i], expected[i]))
+		}
+	}
+	return diffs
+}
+
+func (a access) String() string {
+	kind := "read"
+	if a.write {
+		kind = "write"
+	}
+	return fmt.Sprintf("%s $%04X $%02X", kind, a.addr, a.data)
+}
+
+// runVectors reports the failing vectors, up to maxFailures of them
+func runVectors(t *testing.T, vectors []singleStepVector, maxFailures int) {
+	t.Helper()
+	failures := 0
+	for i := range vectors {
+		diffs := runVector(&vectors[i])
+		if len(diffs) == 0 {
+			continue
+		}
+		t.Errorf("%s: %v", vectors[i].Name, diffs)
+		failures++
+		if failures == maxFailures {
+			t.Errorf("giving up after %d failures", failures)
+			return
+		}
+	}
+}
+
+func TestSingleStepSamples(t *testing.T) {
+	vectors, err := loadVectors("testdata/singlestep.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	runVectors(t, vectors, len(vectors))
+}
+
+func TestSingleStepProcessorTests(t *testing.T) {
+	if _, err := os.Stat(processorTestsDir); errors.Is(err, fs.ErrNotExist) {
+		t.Skipf("%s not found", processorTestsDir)
+	}
+
+	for code := 0; code < 0x100; code++ {
+		if singleStepSkipped[uint8(code)] {
+			continue
+		}
+		name := fmt.Sprintf("%02x.json", code)
+		t.Run(name, func(t *testing.T) {
+			vectors, err := loadVectors(filepath.Join(processorTestsDir, name))
+			if errors.Is(err, fs.ErrNotExist) {
+				t.Skipf("%s not found", name)
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			runVectors(t, vectors, 10)
+		})
+	}
+}
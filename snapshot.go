@@ -0,0 +1,387 @@
+package go6502
+
+import (
+	"bytes"
+	"encoding"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+)
+
+// Snapshots save the whole machine: the cpu, instruction in progress and
+// interrupt lines included, the memory and the named devices. Restoring a
+// snapshot gives back a machine that runs cycle for cycle like the saved
+// one.
+//
+// The format is little endian. It starts with the magic "G6502SNP" and the
+// uint16 version, followed by chunks made of a 4 bytes tag, a uint32 size
+// and the data:
+//
+//	"CPU " the state of MarshalBinary of Cpu
+//	"MEM " the bytes of the memory
+//	"DEV " a uint16 name size, the name, the state of the device
+//
+// A chunk holds at most maxChunkSize bytes.
+const (
+	snapshotMagic   = "G6502SNP"
+	SnapshotVersion = 1
+
+	// maxChunkSize bounds what a corrupt size makes ReadSnapshot allocate,
+	// the memory and the state of any device fit
+	maxChunkSize = 1 << 20
+)
+
+var ErrBadSnapshot = errors.New("go6502: bad snapshot")
+
+// Device is a bus device with a state of its own, saved in snapshots.
+type Device interface {
+	encoding.BinaryMarshaler
+	encoding.BinaryUnmarshaler
+}
+
+// the microcode program in progress, saved as a reference since the
+// programs are tables of functions
+const (
+	noProgram = iota
+	opcodeProgram
+	interruptSequence
+	extraCycleSequence
+)
+
+// the faults are saved as their index here, 0 without a fault
+var snapshotFaults = []error{nil, ErrJammed, ErrUnsupportedMode}
+
+// cpuState is the saved state of a cpu, fixed size for encoding/binary
+type cpuState struct {
+	Cycle     int64
+	PC        uint16
+	SP, A, X  uint8
+	Y, P      uint8
+	Variant   uint8
+	HaltOnJam bool
+
+	IrqLine, NmiLine, NmiPending bool
+	Waiting, Halted              bool
+	Fault                        uint8
+
+	Program     uint8
+	Step        uint8
+	Opcode      uint8
+	OpcodePC    uint16
+	Addr, Base  uint16
+	Data        uint8
+	PageCrossed bool
+	ExtraCycle  bool
+	Vector      uint16
+	IBefore     bool
+	IDelayed    bool
+}
+
+func sameProgram(a, b []microStep) bool {
+	return len(a) > 0 && len(a) == len(b) && &a[0] == &b[0]
+}
+
+// MarshalBinary saves the state of the cpu, registers, cycle count,
+// interrupt lines and the instruction left in progress by Tick. The hooks,
+// StopWhen and Trace, and the call stack of TrackCalls are not saved.
+func (c *Cpu) MarshalBinary() ([]byte, error) {
+	s := cpuState{
+		Cycle:     int64(c.Cycle),
+		PC:        uint16(c.ProgramCounter),
+		SP:        uint8(c.StackPointer),
+		A:         uint8(c.Accumulator),
+		X:         uint8(c.XIndex),
+		Y:         uint8(c.YIndex),
+		P:         uint8(c.Status),
+		Variant:   uint8(c.Variant),
+		HaltOnJam: c.HaltOnJam,
+
+		IrqLine:    c.irqLine,
+		NmiLine:    c.nmiLine,
+		NmiPending: c.nmiPending,
+		Waiting:    c.waiting,
+		Halted:     c.halted,
+
+		Step:        uint8(c.step),
+		Opcode:      c.opcode,
+		OpcodePC:    c.opcodePC,
+		Addr:        c.addr,
+		Base:        c.base,
+		Data:        c.data,
+		PageCrossed: c.pageCrossed,
+		ExtraCycle:  c.extraCycle,
+		Vector:      c.vector,
+		IBefore:     c.iBefore,
+		IDelayed:    c.iDelayed,
+	}
+
+	for i, fault := range snapshotFaults {
+		if c.fault == fault {
+			s.Fault = uint8(i)
+		}
+	}
+
+	switch {
+	case c.program == nil:
+		s.Program = noProgram
+	case sameProgram(c.program, interruptProgram):
+		s.Program = interruptSequence
+	case sameProgram(c.program, extraCycleProgram):
+		s.Program = extraCycleSequence
+	default:
+		s.Program = opcodeProgram
+	}
+
+	var buf bytes.Buffer
+	if err := binary.Write(&buf, binary.LittleEndian, &s); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// UnmarshalBinary restores a state saved by MarshalBinary, the hooks are
+// kept. The call stack of TrackCalls starts over empty, SetBacktrace puts
+// one back.
+func (c *Cpu) UnmarshalBinary(data []byte) error {
+	var s cpuState
+	if len(data) != binary.Size(&s) {
+		return fmt.Errorf("%w: cpu state of %d bytes", ErrBadSnapshot, len(data))
+	}
+	if err := binary.Read(bytes.NewReader(data), binary.LittleEndian, &s); err != nil {
+		return err
+	}
+	if int(s.Variant) >= len(dispatchTables) {
+		return fmt.Errorf("%w: unknown variant %d", ErrBadSnapshot, s.Variant)
+	}
+	if int(s.Fault) >= len(snapshotFaults) {
+		return fmt.Errorf("%w: unknown fault %d", ErrBadSnapshot, s.Fault)
+	}
+
+	variant := Variant(s.Variant)
+	opc := variant.dispatch()[s.Opcode]
+	var program []microStep
+	switch s.Program {
+	case noProgram:
+	case opcodeProgram:
+		if !opc.known {
+			return fmt.Errorf("%w: opcode $%02X in progress", ErrBadSnapshot, s.Opcode)
+		}
+		program = opc.program
+	case interruptSequence:
+		program = interruptProgram
+	case extraCycleSequence:
+		program = extraCycleProgram
+	default:
+		return fmt.Errorf("%w: unknown program %d", ErrBadSnapshot, s.Program)
+	}
+	// a program ends as its last step runs, the next step is always in it
+	if program != nil && int(s.Step) >= len(program) {
+		return fmt.Errorf("%w: step %d out of the program", ErrBadSnapshot, s.Step)
+	}
+
+	c.Cycle = int(s.Cycle)
+	c.ProgramCounter = Register16(s.PC)
+	c.StackPointer = Register8(s.SP)
+	c.Accumulator = Register8(s.A)
+	c.XIndex = Register8(s.X)
+	c.YIndex = Register8(s.Y)
+	c.Status = Register8(s.P)
+	c.Variant = variant
+	c.HaltOnJam = s.HaltOnJam
+
+	c.irqLine = s.IrqLine
+	c.nmiLine = s.NmiLine
+	c.nmiPending = s.NmiPending
+	c.waiting = s.Waiting
+	c.halted = s.Halted
+	c.fault = snapshotFaults[s.Fault]
+
+	c.program = program
+	c.step = int(s.Step)
+	c.operation = opc.operation
+	c.opcode = s.Opcode
+	c.opcodePC = s.OpcodePC
+	c.addr = s.Addr
+	c.base = s.Base
+	c.data = s.Data
+	c.pageCrossed = s.PageCrossed
+	c.extraCycle = s.ExtraCycle
+	c.vector = s.Vector
+	c.iBefore = s.IBefore
+	c.iDelayed = s.IDelayed
+	c.spBefore = c.StackPointer
+	c.calls = c.calls[:0]
+	return nil
+}
+
+// MarshalBinary returns a copy of the memory.
+func (m *Mem) MarshalBinary() ([]byte, error) {
+	data := make([]byte, len(m))
+	copy(data, m[:])
+	return data, nil
+}
+
+// UnmarshalBinary restores the memory, data must cover all of it.
+func (m *Mem) UnmarshalBinary(data []byte) error {
+	if len(data) != len(m) {
+		return fmt.Errorf("%w: memory of %d bytes", ErrBadSnapshot, len(data))
+	}
+	copy(m[:], data)
+	return nil
+}
+
+func writeChunk(w io.Writer, tag string, data []byte) error {
+	header := make([]byte, 8)
+	copy(header, tag)
+	binary.LittleEndian.PutUint32(header[4:], uint32(len(data)))
+	if _, err := w.Write(header); err != nil {
+		return err
+	}
+	_, err := w.Write(data)
+	return err
+}
+
+// WriteSnapshot saves the cpu, the memory and the devices to w. The
+// devices are saved under their name in the map.
+func WriteSnapshot(w io.Writer, cpu *Cpu, mem *Mem, devices map[string]Device) error {
+	header := make([]byte, len(snapshotMagic)+2)
+	copy(header, snapshotMagic)
+	binary.LittleEndian.PutUint16(header[len(snapshotMagic):], SnapshotVersion)
+	if _, err := w.Write(header); err != nil {
+		return err
+	}
+
+	state, err := cpu.MarshalBinary()
+	if err != nil {
+		return err
+	}
+	if err := writeChunk(w, "CPU ", state); err != nil {
+		return err
+	}
+	state, _ = mem.MarshalBinary()
+	if err := writeChunk(w, "MEM ", state); err != nil {
+		return err
+	}
+
+	// sorted so that the same machine always gives the same bytes
+	names := make([]string, 0, len(devices))
+	for name := range devices {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		state, err := devices[name].MarshalBinary()
+		if err != nil {
+			return fmt.Errorf("go6502: device %s: %w", name, err)
+		}
+		data := make([]byte, 2, 2+len(name)+len(state))
+		binary.LittleEndian.PutUint16(data, uint16(len(name)))
+		data = append(append(data, name...), state...)
+		if err := writeChunk(w, "DEV ", data); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ReadSnapshot restores the cpu, the memory and the devices saved by
+// WriteSnapshot. Every device of the snapshot must be in devices and the
+// other way around. On errors the machine is left as it was, the devices
+// already restored get their previous state back.
+func ReadSnapshot(r io.Reader, cpu *Cpu, mem *Mem, devices map[string]Device) error {
+	header := make([]byte, len(snapshotMagic)+2)
+	if _, err := io.ReadFull(r, header); err != nil {
+		return fmt.Errorf("%w: %v", ErrBadSnapshot, err)
+	}
+	if string(header[:len(snapshotMagic)]) != snapshotMagic {
+		return fmt.Errorf("%w: not a snapshot", ErrBadSnapshot)
+	}
+	version := binary.LittleEndian.Uint16(header[len(snapshotMagic):])
+	if version != SnapshotVersion {
+		return fmt.Errorf("%w: version %d, expected %d", ErrBadSnapshot, version, SnapshotVersion)
+	}
+
+	// the state is decoded aside, the machine is left untouched on errors
+	var (
+		chunks     = map[string][]byte{}
+		deviceData = map[string][]byte{}
+	)
+	for {
+		chunk := make([]byte, 8)
+		if _, err := io.ReadFull(r, chunk); err == io.EOF {
+			break
+		} else if err != nil {
+			return fmt.Errorf("%w: %v", ErrBadSnapshot, err)
+		}
+		tag := string(chunk[:4])
+		size := binary.LittleEndian.Uint32(chunk[4:])
+		if size > maxChunkSize {
+			return fmt.Errorf("%w: chunk %q of %d bytes", ErrBadSnapshot, tag, size)
+		}
+		data := make([]byte, size)
+		if _, err := io.ReadFull(r, data); err != nil {
+			return fmt.Errorf("%w: chunk %q: %v", ErrBadSnapshot, tag, err)
+		}
+
+		if tag != "DEV " {
+			chunks[tag] = data
+			continue
+		}
+		if len(data) < 2 || len(data) < 2+int(binary.LittleEndian.Uint16(data)) {
+			return fmt.Errorf("%w: truncated device", ErrBadSnapshot)
+		}
+		end := 2 + int(binary.LittleEndian.Uint16(data))
+		name := string(data[2:end])
+		if _, ok := devices[name]; !ok {
+			return fmt.Errorf("%w: unexpected device %s", ErrBadSnapshot, name)
+		}
+		deviceData[name] = data[end:]
+	}
+
+	for _, tag := range []string{"CPU ", "MEM "} {
+		if _, ok := chunks[tag]; !ok {
+			return fmt.Errorf("%w: missing chunk %q", ErrBadSnapshot, tag)
+		}
+	}
+	for name := range devices {
+		if _, ok := deviceData[name]; !ok {
+			return fmt.Errorf("%w: missing device %s", ErrBadSnapshot, name)
+		}
+	}
+
+	restored := *cpu
+	if err := restored.UnmarshalBinary(chunks["CPU "]); err != nil {
+		return err
+	}
+	memory := chunks["MEM "]
+	if len(memory) != len(mem) {
+		return fmt.Errorf("%w: memory of %d bytes", ErrBadSnapshot, len(memory))
+	}
+
+	// the devices may still fail, they go first and are put back then
+	names := make([]string, 0, len(devices))
+	previous := map[string][]byte{}
+	for name, device := range devices {
+		state, err := device.MarshalBinary()
+		if err != nil {
+			return fmt.Errorf("go6502: device %s: %w", name, err)
+		}
+		names = append(names, name)
+		previous[name] = state
+	}
+	sort.Strings(names)
+	for i, name := range names {
+		if err := devices[name].UnmarshalBinary(deviceData[name]); err != nil {
+			for _, restored := range names[:i+1] {
+				devices[restored].UnmarshalBinary(previous[restored])
+			}
+			return fmt.Errorf("go6502: device %s: %w", name, err)
+		}
+	}
+
+	*cpu = restored
+	mem.UnmarshalBinary(memory)
+	return nil
+}
@@ -0,0 +1,225 @@
+package go6502
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"testing"
+
+	"github.com/zehlt/go6502/asrt"
+)
+
+// counterDevice counts its accesses, as a stand in for a device with state
+type counterDevice struct {
+	count uint16
+}
+
+func (d *counterDevice) MarshalBinary() ([]byte, error) {
+	return []byte{uint8(d.count), uint8(d.count >> 8)}, nil
+}
+
+func (d *counterDevice) UnmarshalBinary(data []byte) error {
+	if len(data) != 2 {
+		return errors.New("counter: bad state")
+	}
+	d.count = uint16(data[0]) | uint16(data[1])<<8
+	return nil
+}
+
+// rawDevice saves any state, to feed other devices with bad ones
+type rawDevice struct {
+	state []byte
+}
+
+func (d *rawDevice) MarshalBinary() ([]byte, error) {
+	return append([]byte(nil), d.state...), nil
+}
+
+func (d *rawDevice) UnmarshalBinary(data []byte) error {
+	d.state = append([]byte(nil), data...)
+	return nil
+}
+
+func snapshotProgram() *Mem {
+	memory := &Mem{}
+	memory.WriteBytes(0x0600, []uint8{
+		LDX_IMM, 0x00,
+		INX_IMP,
+		TXA_IMP,
+		STA_ABX, 0x00, 0x02,
+		CPX_IMM, 0x40,
+		BNE_REL, 0xF7,
+		JMP_ABS, 0x00, 0x06,
+	})
+	memory.WriteBytes(0x8000, []uint8{PHA_IMP, PLA_IMP, RTI_IMP})
+	memory.WriteWord(NmiVector, 0x8000)
+	return memory
+}
+
+func TestSnapshotResumesMidInstruction(t *testing.T) {
+	memory := snapshotProgram()
+	cpu := Cpu{Variant: CMOS65C02}
+	cpu.ProgramCounter = 0x0600
+	cpu.StackPointer = 0xFF
+	for i := 0; i < 101; i++ {
+		asrt.Equal(t, cpu.Tick(memory), nil)
+	}
+	cpu.SetNMI(true)
+	for cpu.program == nil {
+		asrt.Equal(t, cpu.Tick(memory), nil)
+	}
+
+	var snapshot bytes.Buffer
+	asrt.Equal(t, WriteSnapshot(&snapshot, &cpu, memory, nil), nil)
+
+	restoredMemory := &Mem{}
+	restored := Cpu{}
+	asrt.Equal(t, ReadSnapshot(bytes.NewReader(snapshot.Bytes()), &restored, restoredMemory, nil), nil)
+	asrt.Equal(t, restored.Registers, cpu.Registers)
+	asrt.Equal(t, restored.Cycle, cpu.Cycle)
+	asrt.Equal(t, *restoredMemory, *memory)
+
+	bus := recordingBus{Mem: memory}
+	restoredBus := recordingBus{Mem: restoredMemory}
+	for i := 0; i < 500; i++ {
+		asrt.Equal(t, cpu.Tick(&bus), nil)
+		asrt.Equal(t, restored.Tick(&restoredBus), nil)
+	}
+	assertAccesses(t, restoredBus.log, bus.log...)
+	asrt.Equal(t, restored.Registers, cpu.Registers)
+	asrt.Equal(t, restored.Cycle, cpu.Cycle)
+	asrt.Equal(t, *restoredMemory, *memory)
+
+	// the pending nmi was taken after the restore
+	handler := false
+	for _, a := range restoredBus.log {
+		handler = handler || a == read(0x8000, PHA_IMP)
+	}
+	asrt.True(t, handler)
+
+	// a snapshot of identical machines is identical
+	var again, restoredAgain bytes.Buffer
+	WriteSnapshot(&again, &cpu, memory, nil)
+	WriteSnapshot(&restoredAgain, &restored, restoredMemory, nil)
+	asrt.True(t, bytes.Equal(again.Bytes(), restoredAgain.Bytes()))
+}
+
+func TestSnapshotKeepsFaultsAndHalts(t *testing.T) {
+	memory := &Mem{}
+	memory[0x0600] = JAM_IMP_12
+	cpu := Cpu{}
+	cpu.ProgramCounter = 0x0600
+	asrt.Equal(t, cpu.Tick(memory), nil)
+
+	// the jam is reported on the last cycle, after the restore
+	var snapshot bytes.Buffer
+	asrt.Equal(t, WriteSnapshot(&snapshot, &cpu, memory, nil), nil)
+	restored := Cpu{}
+	asrt.Equal(t, ReadSnapshot(&snapshot, &restored, &Mem{}, nil), nil)
+	asrt.True(t, errors.Is(restored.Step(memory), ErrJammed))
+
+	cpu = Cpu{HaltOnJam: true}
+	cpu.ProgramCounter = 0x0600
+	asrt.Equal(t, cpu.Step(memory), nil)
+	snapshot.Reset()
+	asrt.Equal(t, WriteSnapshot(&snapshot, &cpu, memory, nil), nil)
+	restored = Cpu{}
+	asrt.Equal(t, ReadSnapshot(&snapshot, &restored, &Mem{}, nil), nil)
+	asrt.True(t, restored.Halted())
+	asrt.Equal(t, restored.Step(memory), nil)
+	asrt.Equal(t, restored.Cycle, cpu.Cycle)
+}
+
+func TestSnapshotDevices(t *testing.T) {
+	memory := snapshotProgram()
+	cpu := Cpu{}
+	counter := &counterDevice{count: 0x1234}
+
+	var snapshot bytes.Buffer
+	asrt.Equal(t, WriteSnapshot(&snapshot, &cpu, memory, map[string]Device{"counter": counter}), nil)
+	data := snapshot.Bytes()
+
+	restored := &counterDevice{}
+	asrt.Equal(t, ReadSnapshot(bytes.NewReader(data), &Cpu{}, &Mem{}, map[string]Device{"counter": restored}), nil)
+	asrt.Equal(t, restored.count, uint16(0x1234))
+
+	err := ReadSnapshot(bytes.NewReader(data), &Cpu{}, &Mem{}, nil)
+	asrt.True(t, errors.Is(err, ErrBadSnapshot))
+	err = ReadSnapshot(bytes.NewReader(data), &Cpu{}, &Mem{}, map[string]Device{"counter": restored, "timer": &counterDevice{}})
+	asrt.True(t, errors.Is(err, ErrBadSnapshot))
+}
+
+func TestSnapshotFailingDeviceLeavesTheMachine(t *testing.T) {
+	memory := snapshotProgram()
+	cpu := Cpu{}
+	cpu.Cycle = 1000
+	var snapshot bytes.Buffer
+	saved := map[string]Device{"a": &counterDevice{count: 1}, "b": &rawDevice{[]byte{1, 2, 3}}}
+	asrt.Equal(t, WriteSnapshot(&snapshot, &cpu, memory, saved), nil)
+
+	// b does not accept a state of 3 bytes, a is restored before it
+	a, b := &counterDevice{count: 7}, &counterDevice{count: 9}
+	target, targetMemory := Cpu{}, &Mem{}
+	err := ReadSnapshot(&snapshot, &target, targetMemory, map[string]Device{"a": a, "b": b})
+	asrt.True(t, err != nil)
+	asrt.Equal(t, a.count, uint16(7))
+	asrt.Equal(t, b.count, uint16(9))
+	asrt.Equal(t, target.Cycle, 0)
+	asrt.Equal(t, targetMemory[0x0600], uint8(0))
+}
+
+func TestSnapshotRejectsBadInput(t *testing.T) {
+	memory := snapshotProgram()
+	cpu := Cpu{}
+	var snapshot bytes.Buffer
+	WriteSnapshot(&snapshot, &cpu, memory, nil)
+	data := snapshot.Bytes()
+
+	// a memory chunk of 16 bytes in place of the full one
+	shortMemory := append([]byte{}, data[:len(data)-len(memory)-8]...)
+	shortMemory = append(shortMemory, 'M', 'E', 'M', ' ', 16, 0, 0, 0)
+	shortMemory = append(shortMemory, make([]byte, 16)...)
+
+	target := &Mem{}
+	target[0] = 0x42
+	for name, bad := range map[string][]byte{
+		"empty":     nil,
+		"magic":     append([]byte("NOTASNAP"), data[8:]...),
+		"version":   append(append([]byte(snapshotMagic), 0x63, 0x00), data[10:]...),
+		"truncated": data[:len(data)-10],
+		"header":    data[:10],
+		"memory":    shortMemory,
+		"size":      append(append([]byte{}, data[:10]...), 'C', 'P', 'U', ' ', 0xFF, 0xFF, 0xFF, 0xFF),
+	} {
+		err := ReadSnapshot(bytes.NewReader(bad), &Cpu{}, target, nil)
+		if !errors.Is(err, ErrBadSnapshot) {
+			t.Fatalf("%s: expected ErrBadSnapshot, got %v", name, err)
+		}
+	}
+	// the machine is left untouched
+	asrt.Equal(t, target[0], uint8(0x42))
+}
+
+func TestSnapshotRejectsUnknownVariants(t *testing.T) {
+	var state bytes.Buffer
+	binary.Write(&state, binary.LittleEndian, &cpuState{Variant: uint8(len(dispatchTables))})
+	cpu := Cpu{}
+	err := cpu.UnmarshalBinary(state.Bytes())
+	asrt.True(t, errors.Is(err, ErrBadSnapshot))
+}
+
+func TestSnapshotRejectsStepPastTheProgram(t *testing.T) {
+	memory := snapshotProgram()
+	cpu := Cpu{}
+	cpu.ProgramCounter = 0x0600
+	asrt.Equal(t, cpu.Tick(memory), nil)
+	cpu.step = len(cpu.program)
+	state, err := cpu.MarshalBinary()
+	asrt.Equal(t, err, nil)
+
+	restored := Cpu{}
+	err = restored.UnmarshalBinary(state)
+	asrt.True(t, errors.Is(err, ErrBadSnapshot))
+	asrt.True(t, restored.program == nil)
+	asrt.Equal(t, restored.Tick(memory), nil)
+}
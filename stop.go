@@ -0,0 +1,16 @@
+package go6502
+
+// StopAtAddress stops before the instruction at addr is executed.
+func StopAtAddress(addr uint16) StopCondition {
+	return func(c *Cpu, bus Bus) bool {
+		return uint16(c.ProgramCounter) == addr
+	}
+}
+
+// StopOnOpcode stops before the next instruction when its opcode is op,
+// the opcode itself is not executed.
+func StopOnOpcode(op uint8) StopCondition {
+	return func(c *Cpu, bus Bus) bool {
+		return Peek(bus, uint16(c.ProgramCounter)) == op
+	}
+}
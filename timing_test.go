@@ -0,0 +1,173 @@
+package go6502
+
+import (
+	"testing"
+
+	"github.com/zehlt/go6502/asrt"
+)
+
+// Reference timings of the official NMOS opcodes, taken from the MOS
+// programming manual. pagePenalty marks the reads paying one more cycle
+// when indexing crosses a page, stores and read-modify-writes always pay
+// it and have it in their base count.
+var referenceTimings = []struct {
+	code        uint8
+	cycles      int
+	pagePenalty bool
+}{
+	{ADC_IMM, 2, false}, {ADC_ZER, 3, false}, {ADC_ZRX, 4, false}, {ADC_ABS, 4, false},
+	{ADC_ABX, 4, true}, {ADC_ABY, 4, true}, {ADC_IDX, 6, false}, {ADC_IDY, 5, true},
+
+	{AND_IMM, 2, false}, {AND_ZER, 3, false}, {AND_ZRX, 4, false}, {AND_ABS, 4, false},
+	{AND_ABX, 4, true}, {AND_ABY, 4, true}, {AND_IDX, 6, false}, {AND_IDY, 5, true},
+
+	{ASL_ACC, 2, false}, {ASL_ZER, 5, false}, {ASL_ZRX, 6, false}, {ASL_ABS, 6, false}, {ASL_ABX, 7, false},
+
+	{BIT_ZER, 3, false}, {BIT_ABS, 4, false},
+
+	{BRK_IMP, 7, false},
+
+	{CLC_IMP, 2, false}, {CLD_IMP, 2, false}, {CLI_IMP, 2, false}, {CLV_IMP, 2, false},
+
+	{CMP_IMM, 2, false}, {CMP_ZER, 3, false}, {CMP_ZRX, 4, false}, {CMP_ABS, 4, false},
+	{CMP_ABX, 4, true}, {CMP_ABY, 4, true}, {CMP_IDX, 6, false}, {CMP_IDY, 5, true},
+
+	{CPX_IMM, 2, false}, {CPX_ZER, 3, false}, {CPX_ABS, 4, false},
+	{CPY_IMM, 2, false}, {CPY_ZER, 3, false}, {CPY_ABS, 4, false},
+
+	{DEC_ZER, 5, false}, {DEC_ZRX, 6, false}, {DEC_ABS, 6, false}, {DEC_ABX, 7, false},
+	{DEX_IMP, 2, false}, {DEY_IMP, 2, false},
+
+	{EOR_IMM, 2, false}, {EOR_ZER, 3, false}, {EOR_ZRX, 4, false}, {EOR_ABS, 4, false},
+	{EOR_ABX, 4, true}, {EOR_ABY, 4, true}, {EOR_IDX, 6, false}, {EOR_IDY, 5, true},
+
+	{INC_ZER, 5, false}, {INC_ZRX, 6, false}, {INC_ABS, 6, false}, {INC_ABX, 7, false},
+	{INX_IMP, 2, false}, {INY_IMP, 2, false},
+
+	{JMP_ABS, 3, false}, {JMP_IND, 5, false}, {JSR_ABS, 6, false},
+
+	{LDA_IMM, 2, false}, {LDA_ZER, 3, false}, {LDA_ZRX, 4, false}, {LDA_ABS, 4, false},
+	{LDA_ABX, 4, true}, {LDA_ABY, 4, true}, {LDA_IDX, 6, false}, {LDA_IDY, 5, true},
+
+	{LDX_IMM, 2, false}, {LDX_ZER, 3, false}, {LDX_ZRY, 4, false}, {LDX_ABS, 4, false}, {LDX_ABY, 4, true},
+	{LDY_IMM, 2, false}, {LDY_ZER, 3, false}, {LDY_ZRX, 4, false}, {LDY_ABS, 4, false}, {LDY_ABX, 4, true},
+
+	{LSR_ACC, 2, false}, {LSR_ZER, 5, false}, {LSR_ZRX, 6, false}, {LSR_ABS, 6, false}, {LSR_ABX, 7, false},
+
+	{NOP_IMP, 2, false},
+
+	{ORA_IMM, 2, false}, {ORA_ZER, 3, false}, {ORA_ZRX, 4, false}, {ORA_ABS, 4, false},
+	{ORA_ABX, 4, true}, {ORA_ABY, 4, true}, {ORA_IDX, 6, false}, {ORA_IDY, 5, true},
+
+	{PHA_IMP, 3, false}, {PHP_IMP, 3, false}, {PLA_IMP, 4, false}, {PLP_IMP, 4, false},
+
+	{ROL_ACC, 2, false}, {ROL_ZER, 5, false}, {ROL_ZRX, 6, false}, {ROL_ABS, 6, false}, {ROL_ABX, 7, false},
+	{ROR_ACC, 2, false}, {ROR_ZER, 5, false}, {ROR_ZRX, 6, false}, {ROR_ABS, 6, false}, {ROR_ABX, 7, false},
+
+	{RTI_IMP, 6, false}, {RTS_IMP, 6, false},
+
+	{SBC_IMM, 2, false}, {SBC_ZER, 3, false}, {SBC_ZRX, 4, false}, {SBC_ABS, 4, false},
+	{SBC_ABX, 4, true}, {SBC_ABY, 4, true}, {SBC_IDX, 6, false}, {SBC_IDY, 5, true},
+
+	{SEC_IMP, 2, false}, {SED_IMP, 2, false}, {SEI_IMP, 2, false},
+
+	{STA_ZER, 3, false}, {STA_ZRX, 4, false}, {STA_ABS, 4, false}, {STA_ABX, 5, false},
+	{STA_ABY, 5, false}, {STA_IDX, 6, false}, {STA_IDY, 6, false},
+
+	{STX_ZER, 3, false}, {STX_ZRY, 4, false}, {STX_ABS, 4, false},
+	{STY_ZER, 3, false}, {STY_ZRX, 4, false}, {STY_ABS, 4, false},
+
+	{TAX_IMP, 2, false}, {TAY_IMP, 2, false}, {TSX_IMP, 2, false},
+	{TXA_IMP, 2, false}, {TXS_IMP, 2, false}, {TYA_IMP, 2, false},
+}
+
+// branch opcodes with the flag they test and the state taking them
+var referenceBranches = []struct {
+	code uint8
+	flag uint8
+	set  bool
+}{
+	{BCC_REL, Carry, false}, {BCS_REL, Carry, true}, {BNE_REL, Zero, false}, {BEQ_REL, Zero, true},
+	{BPL_REL, Negative, false}, {BMI_REL, Negative, true}, {BVC_REL, Verflow, false}, {BVS_REL, Verflow, true},
+}
+
+// runTimed executes the opcode at $0200 with its operand pointing at $10F0,
+// the index makes the effective address cross a page or not
+func runTimed(t *testing.T, code uint8, index Register8) int {
+	t.Helper()
+	bus := Mem{}
+	bus[0x0200] = code
+	bus[0x0201] = 0xF0
+	bus[0x0202] = 0x10
+	bus[0x00F0] = 0xF0
+	bus[0x00F1] = 0x10
+
+	cpu := Cpu{}
+	cpu.ProgramCounter = 0x0200
+	cpu.StackPointer = 0xFD
+	cpu.XIndex = index
+	cpu.YIndex = index
+	asrt.Equal(t, cpu.Step(&bus), nil)
+	return cpu.Cycle
+}
+
+func TestReferenceTimingsCoverOfficialOpcodes(t *testing.T) {
+	asrt.Equal(t, len(referenceTimings)+len(referenceBranches), len(officialOpcodes))
+	for _, ref := range referenceTimings {
+		asrt.Equal(t, officialOpcodes[ref.code].Cycles, ref.cycles)
+	}
+}
+
+func TestCyclesMatchReferenceTimings(t *testing.T) {
+	for _, ref := range referenceTimings {
+		// $10F0 + $05 stays in the page
+		asrt.Equal(t, runTimed(t, ref.code, 0x05), ref.cycles)
+
+		// $10F0 + $20 crosses it, (zp,X) and zp,X wrap in the zero page
+		crossed := ref.cycles
+		if ref.pagePenalty {
+			crossed++
+		}
+		asrt.Equal(t, runTimed(t, ref.code, 0x20), crossed)
+	}
+}
+
+func TestBranchTimings(t *testing.T) {
+	for _, ref := range referenceBranches {
+		for _, tc := range []struct {
+			pc     Register16
+			offset uint8
+			taken  bool
+			cycles int
+		}{
+			{0x0200, 0x10, false, 2},
+			{0x0200, 0x10, true, 3}, // to $0212
+			{0x02F0, 0x20, true, 4}, // across the page to $0312
+		} {
+			bus := Mem{}
+			bus[tc.pc] = ref.code
+			bus[tc.pc+1] = tc.offset
+
+			cpu := Cpu{}
+			cpu.ProgramCounter = tc.pc
+			if tc.taken == ref.set {
+				cpu.Status.Add(ref.flag)
+			}
+			cpu.Step(&bus)
+
+			asrt.Equal(t, cpu.Cycle, tc.cycles)
+			if tc.taken {
+				asrt.Equal(t, cpu.ProgramCounter, tc.pc+2+Register16(tc.offset))
+			}
+		}
+	}
+}
+
+func TestInterruptTiming(t *testing.T) {
+	bus := Mem{}
+	cpu := Cpu{}
+	cpu.SetNMI(true)
+	cpu.Step(&bus)
+
+	asrt.Equal(t, cpu.Cycle, 7)
+}
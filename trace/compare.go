@@ -0,0 +1,97 @@
+package trace
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+)
+
+// Divergence is the first line that differs between two logs. Field names
+// the first column that differs: PC, bytes, instruction, a register like
+// A or SP, CYC, or end when one log is shorter.
+type Divergence struct {
+	Line     int
+	Field    string
+	Got      string
+	Expected string
+}
+
+func (d *Divergence) String() string {
+	return fmt.Sprintf("line %d differs on %s\n     got: %s\nexpected: %s", d.Line, d.Field, d.Got, d.Expected)
+}
+
+// the PPU column of nestest.log has no counterpart here
+var ppuColumn = regexp.MustCompile(`PPU:\s*\d+,\s*\d+\s*`)
+
+// the fixed columns of a line, the registers follow as NAME:VALUE pairs
+var columns = []struct {
+	name       string
+	start, end int
+}{
+	{"PC", 0, 4},
+	{"bytes", 6, 14},
+	{"instruction", 15, 48},
+}
+
+// Compare reads both logs line by line and returns the first divergence,
+// nil when they match. Trailing spaces and the PPU column are ignored.
+func Compare(got, expected io.Reader) (*Divergence, error) {
+	gotLines := bufio.NewScanner(got)
+	expectedLines := bufio.NewScanner(expected)
+
+	for line := 1; ; line++ {
+		hasGot := gotLines.Scan()
+		hasExpected := expectedLines.Scan()
+		if err := gotLines.Err(); err != nil {
+			return nil, err
+		}
+		if err := expectedLines.Err(); err != nil {
+			return nil, err
+		}
+		if !hasGot && !hasExpected {
+			return nil, nil
+		}
+
+		g, e := normalize(gotLines.Text()), normalize(expectedLines.Text())
+		if !hasGot || !hasExpected {
+			return &Divergence{Line: line, Field: "end", Got: g, Expected: e}, nil
+		}
+		if g != e {
+			return &Divergence{Line: line, Field: differingField(g, e), Got: g, Expected: e}, nil
+		}
+	}
+}
+
+func normalize(line string) string {
+	return strings.TrimRight(ppuColumn.ReplaceAllString(line, ""), " \t\r")
+}
+
+func differingField(got, expected string) string {
+	for _, column := range columns {
+		if field(got, column.start, column.end) != field(expected, column.start, column.end) {
+			return column.name
+		}
+	}
+
+	gotRegisters := strings.Fields(field(got, 48, len(got)))
+	expectedRegisters := strings.Fields(field(expected, 48, len(expected)))
+	for i := 0; i < len(gotRegisters) && i < len(expectedRegisters); i++ {
+		if gotRegisters[i] != expectedRegisters[i] {
+			name := strings.SplitN(expectedRegisters[i], ":", 2)[0]
+			return name
+		}
+	}
+	return "end"
+}
+
+func field(line string, start, end int) string {
+	if start >= len(line) {
+		return ""
+	}
+	if end > len(line) {
+		end = len(line)
+	}
+	return strings.TrimSpace(line[start:end])
+}
@@ -0,0 +1,145 @@
+// Package trace logs the executed instructions in the column layout of
+// nestest.log, so that runs can be diffed against other emulators:
+//
+//	C000  4C F5 C5  JMP $C5F5                       A:00 X:00 Y:00 P:24 SP:FD CYC:7
+//
+// Undocumented NMOS opcodes are marked with a star before the mnemonic and
+// memory operands are followed by the address and the value they resolve
+// to. Those values are read through the bus before the instruction runs,
+// devices with read side effects see the extra reads.
+package trace
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/zehlt/go6502"
+	"github.com/zehlt/go6502/disasm"
+)
+
+// nestest.log names ISC after its other common name
+var nestestMnemonics = map[string]string{
+	"ISC": "ISB",
+}
+
+// Writer writes a line per instruction, its Trace method is meant to be
+// installed as the Trace hook of a cpu:
+//
+//	cpu.Trace = trace.NewWriter(os.Stdout).Trace
+type Writer struct {
+	w   io.Writer
+	err error
+}
+
+// NewWriter returns a Writer logging to w.
+func NewWriter(w io.Writer) *Writer {
+	return &Writer{w: w}
+}
+
+// Trace writes the line of the instruction c is about to execute. Once a
+// write fails, the following lines are dropped and Err reports the error.
+func (t *Writer) Trace(c *go6502.Cpu, bus go6502.Bus) {
+	if t.err != nil {
+		return
+	}
+	_, t.err = io.WriteString(t.w, Line(c, bus)+"\n")
+}
+
+// Err returns the first write error.
+func (t *Writer) Err() error {
+	return t.err
+}
+
+// Line formats the state of c before it executes the instruction at its
+// program counter. The memory is read with go6502.Peek, free of side
+// effects on the buses that are an Inspector.
+func Line(c *go6502.Cpu, bus go6502.Bus) string {
+	opcodes := c.Variant.Opcodes()
+	d := disasm.Disassembler{Opcodes: opcodes}
+	line := d.Instruction(bus, uint16(c.ProgramCounter))
+
+	hex := make([]string, len(line.Bytes))
+	for i, b := range line.Bytes {
+		hex[i] = fmt.Sprintf("%02X", b)
+	}
+
+	mark := ' '
+	text := line.Text()
+	if opc, ok := opcodes[line.Bytes[0]]; ok {
+		if !c.Variant.IsCmos() && !go6502.Documented(opc.Code) {
+			mark = '*'
+		}
+		if name, ok := nestestMnemonics[line.Mnemonic]; ok {
+			line.Mnemonic = name
+		}
+		text = line.Text() + annotation(c, bus, opc, line.Bytes)
+	}
+
+	return fmt.Sprintf("%04X  %-8s %c%-31s A:%02X X:%02X Y:%02X P:%02X SP:%02X CYC:%d",
+		line.Addr, strings.Join(hex, " "), mark, text,
+		uint8(c.Accumulator), uint8(c.XIndex), uint8(c.YIndex), uint8(c.Status), uint8(c.StackPointer), c.Cycle)
+}
+
+// annotation resolves the memory operand of the instruction, like
+// " @ 0300 = 89" for LDA $0300,X
+func annotation(c *go6502.Cpu, bus go6502.Bus, opc go6502.Opcode, bytes []uint8) string {
+	var operand, word uint16
+	if len(bytes) > 1 {
+		operand = uint16(bytes[1])
+		word = operand
+	}
+	if len(bytes) > 2 {
+		word |= uint16(bytes[2]) << 8
+	}
+	x, y := uint16(c.XIndex), uint16(c.YIndex)
+
+	peek := func(addr uint16) uint8 {
+		return go6502.Peek(bus, addr)
+	}
+	// pointers are read from the zero page and wrap around it
+	pointer := func(addr uint16) uint16 {
+		return uint16(peek(addr&0xFF)) | uint16(peek((addr+1)&0xFF))<<8
+	}
+
+	switch opc.Mode {
+	case go6502.ZeroPage:
+		return fmt.Sprintf(" = %02X", peek(operand))
+	case go6502.ZeroPageX:
+		addr := (operand + x) & 0xFF
+		return fmt.Sprintf(" @ %02X = %02X", addr, peek(addr))
+	case go6502.ZeroPageY:
+		addr := (operand + y) & 0xFF
+		return fmt.Sprintf(" @ %02X = %02X", addr, peek(addr))
+	case go6502.Absolute:
+		if opc.Mnemonic == "JMP" || opc.Mnemonic == "JSR" {
+			return ""
+		}
+		return fmt.Sprintf(" = %02X", peek(word))
+	case go6502.AbsoluteX, go6502.AbsoluteX1:
+		addr := word + x
+		return fmt.Sprintf(" @ %04X = %02X", addr, peek(addr))
+	case go6502.AbsoluteY, go6502.AbsoluteY1:
+		addr := word + y
+		return fmt.Sprintf(" @ %04X = %02X", addr, peek(addr))
+	case go6502.IndirectX:
+		ptr := (operand + x) & 0xFF
+		addr := pointer(ptr)
+		return fmt.Sprintf(" @ %02X = %04X = %02X", ptr, addr, peek(addr))
+	case go6502.IndirectY, go6502.IndirectY1:
+		base := pointer(operand)
+		addr := base + y
+		return fmt.Sprintf(" = %04X @ %04X = %02X", base, addr, peek(addr))
+	case go6502.IndirectZeroPage:
+		addr := pointer(operand)
+		return fmt.Sprintf(" = %04X = %02X", addr, peek(addr))
+	case go6502.Indirect:
+		// the NMOS part does not carry into the high byte of the pointer
+		hi := word + 1
+		if !c.Variant.IsCmos() {
+			hi = word&0xFF00 | hi&0x00FF
+		}
+		return fmt.Sprintf(" = %04X", uint16(peek(word))|uint16(peek(hi))<<8)
+	}
+	return ""
+}
@@ -0,0 +1,152 @@
+package trace
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/zehlt/go6502"
+	"github.com/zehlt/go6502/asrt"
+)
+
+// the first lines of nestest.log, PPU column included
+const nestestStart = `C000  4C F5 C5  JMP $C5F5                       A:00 X:00 Y:00 P:24 SP:FD PPU:  0, 21 CYC:7
+C5F5  A2 00     LDX #$00                        A:00 X:00 Y:00 P:24 SP:FD PPU:  0, 30 CYC:10
+C5F7  86 00     STX $00 = 00                    A:00 X:00 Y:00 P:26 SP:FD PPU:  0, 36 CYC:12
+C5F9  86 10     STX $10 = 00                    A:00 X:00 Y:00 P:26 SP:FD PPU:  0, 45 CYC:15
+C5FB  86 11     STX $11 = 00                    A:00 X:00 Y:00 P:26 SP:FD PPU:  0, 54 CYC:18
+C5FD  20 2D C7  JSR $C72D                       A:00 X:00 Y:00 P:26 SP:FD PPU:  0, 63 CYC:21
+C72D  EA        NOP                             A:00 X:00 Y:00 P:26 SP:FB PPU:  0, 72 CYC:27
+`
+
+func nestestCpu(memory *go6502.Mem) go6502.Cpu {
+	copy(memory[0xC000:], []uint8{go6502.JMP_ABS, 0xF5, 0xC5})
+	copy(memory[0xC5F5:], []uint8{
+		go6502.LDX_IMM, 0x00,
+		go6502.STX_ZER, 0x00,
+		go6502.STX_ZER, 0x10,
+		go6502.STX_ZER, 0x11,
+		go6502.JSR_ABS, 0x2D, 0xC7,
+	})
+	memory[0xC72D] = go6502.NOP_IMP
+
+	cpu := go6502.Cpu{Variant: go6502.Ricoh2A03, Cycle: 7}
+	cpu.ProgramCounter = 0xC000
+	cpu.StackPointer = 0xFD
+	cpu.Status = 0x24
+	return cpu
+}
+
+func TestNestestLayout(t *testing.T) {
+	memory := go6502.Mem{}
+	cpu := nestestCpu(&memory)
+
+	var log bytes.Buffer
+	writer := NewWriter(&log)
+	cpu.Trace = writer.Trace
+	for i := 0; i < 7; i++ {
+		if err := cpu.Step(&memory); err != nil {
+			t.Fatal(err)
+		}
+	}
+	asrt.Equal(t, writer.Err(), nil)
+
+	divergence, err := Compare(&log, strings.NewReader(nestestStart))
+	asrt.Equal(t, err, nil)
+	if divergence != nil {
+		t.Error(divergence)
+	}
+}
+
+func TestAnnotations(t *testing.T) {
+	memory := go6502.Mem{}
+	memory[0x0010] = 0x34
+	memory[0x0011] = 0x12
+	memory[0x1236] = 0x5A
+	memory[0x02FF] = 0x00
+	memory[0x0200] = 0x03
+
+	cpu := go6502.Cpu{}
+	cpu.XIndex = 0x02
+	cpu.YIndex = 0x02
+
+	lines := []struct {
+		code     []uint8
+		expected string
+	}{
+		{[]uint8{go6502.LDA_ZRX, 0x0E}, "LDA $0E,X @ 10 = 34"},
+		{[]uint8{go6502.LDA_ABY, 0x34, 0x12}, "LDA $1234,Y @ 1236 = 5A"},
+		{[]uint8{go6502.LDA_IDX, 0x0E}, "LDA ($0E,X) @ 10 = 1234 = 00"},
+		{[]uint8{go6502.LDA_IDY, 0x10}, "LDA ($10),Y = 1234 @ 1236 = 5A"},
+		{[]uint8{go6502.JMP_IND, 0xFF, 0x02}, "JMP ($02FF) = 0300"},
+		{[]uint8{go6502.DCP_ZER, 0x10}, "*DCP $10 = 34"},
+		{[]uint8{go6502.ISC_ZER, 0x10}, "*ISB $10 = 34"},
+	}
+
+	for _, l := range lines {
+		copy(memory[0x0600:], l.code)
+		cpu.ProgramCounter = 0x0600
+		got := Line(&cpu, &memory)
+		asrt.Equal(t, strings.TrimSpace(got[15:48]), l.expected)
+	}
+}
+
+// acknowledge is a register cleared by its read
+type acknowledge struct {
+	status uint8
+}
+
+func (a *acknowledge) Read(offset uint16) uint8 {
+	status := a.status
+	a.status = 0
+	return status
+}
+
+func (a *acknowledge) Write(offset uint16, data uint8) {
+	a.status = data
+}
+
+func (a *acknowledge) Peek(offset uint16) uint8 {
+	return a.status
+}
+
+func (a *acknowledge) Poke(offset uint16, data uint8) {
+	a.status = data
+}
+
+func TestLinesHaveNoSideEffects(t *testing.T) {
+	bus := &go6502.MappedBus{}
+	register := &acknowledge{status: 0x80}
+	bus.Map(go6502.Mapping{Start: 0x0000, End: 0xFFFF, Chip: make(go6502.RAM, 0x10000)})
+	bus.Map(go6502.Mapping{Start: 0x4000, End: 0x4000, Chip: register})
+	bus.Write(0x0600, go6502.LDA_ABS)
+	bus.Write(0x0601, 0x00)
+	bus.Write(0x0602, 0x40)
+
+	cpu := go6502.Cpu{}
+	cpu.ProgramCounter = 0x0600
+	asrt.True(t, strings.Contains(Line(&cpu, bus), "LDA $4000 = 80"))
+	asrt.Equal(t, register.status, uint8(0x80))
+	cpu.Step(bus)
+	asrt.Equal(t, cpu.Accumulator, go6502.Register8(0x80))
+	asrt.Equal(t, register.status, uint8(0x00))
+}
+
+func TestCompareReportsFirstDivergence(t *testing.T) {
+	got := strings.Replace(nestestStart, "P:26 SP:FD PPU:  0, 45", "P:A6 SP:FD PPU:  0, 45", 1)
+	got = strings.Replace(got, "CYC:27", "CYC:28", 1)
+
+	divergence, err := Compare(strings.NewReader(got), strings.NewReader(nestestStart))
+	asrt.Equal(t, err, nil)
+	asrt.Equal(t, divergence.Line, 4)
+	asrt.Equal(t, divergence.Field, "P")
+
+	lines := strings.SplitAfter(nestestStart, "\n")
+	short := strings.Join(lines[:3], "")
+	divergence, _ = Compare(strings.NewReader(short), strings.NewReader(nestestStart))
+	asrt.Equal(t, divergence.Line, 4)
+	asrt.Equal(t, divergence.Field, "end")
+
+	divergence, _ = Compare(strings.NewReader(nestestStart), strings.NewReader(nestestStart))
+	asrt.True(t, divergence == nil)
+}
@@ -0,0 +1,118 @@
+package go6502
+
+type Variant int
+
+const (
+	// NMOS6502 is the original MOS part, undocumented opcodes included
+	NMOS6502 Variant = iota
+	// Ricoh2A03 is the NES cpu, an NMOS core without decimal mode
+	Ricoh2A03
+	// CMOS65C02 is the base 65C02, undefined opcodes are NOPs
+	CMOS65C02
+	// Rockwell65C02 adds the BBR/BBS/RMB/SMB bit instructions
+	Rockwell65C02
+	// WDC65C02 adds WAI and STP on top of the Rockwell set
+	WDC65C02
+)
+
+var Opcodes = mergeOpcodes(officialOpcodes, undocumentedOpcodes)
+
+var Opcodes65C02 = mergeOpcodes(cmosNops, officialOpcodes, cmosOpcodes)
+
+var OpcodesRockwell65C02 = mergeOpcodes(Opcodes65C02, rockwellOpcodes)
+
+var OpcodesWDC65C02 = mergeOpcodes(OpcodesRockwell65C02, wdcOpcodes)
+
+// later tables override the entries of the previous ones, the opcodes
+// without a program of their own get the microcode of their mode
+func mergeOpcodes(tables ...map[uint8]Opcode) map[uint8]Opcode {
+	merged := map[uint8]Opcode{}
+	for _, table := range tables {
+		for code, opc := range table {
+			if opc.program == nil {
+				opc.program = buildProgram(opc.Mode, opc.Access)
+			}
+			merged[code] = opc
+		}
+	}
+	return merged
+}
+
+func (v Variant) String() string {
+	switch v {
+	case NMOS6502:
+		return "6502"
+	case Ricoh2A03:
+		return "2A03"
+	case CMOS65C02:
+		return "65C02"
+	case Rockwell65C02:
+		return "R65C02"
+	case WDC65C02:
+		return "W65C02"
+	default:
+		return "unknown"
+	}
+}
+
+// dispatch is what the cpu needs of an opcode to execute it
+type dispatch struct {
+	known     bool
+	operation func(c *Cpu, value uint8) uint8
+	program   []microStep
+}
+
+// dispatchTables are the opcode tables of the variants indexed by opcode,
+// the cpu looks the opcodes up there rather than in the maps
+var dispatchTables = [...]*[256]dispatch{
+	NMOS6502:      dispatchTable(Opcodes),
+	Ricoh2A03:     dispatchTable(Opcodes),
+	CMOS65C02:     dispatchTable(Opcodes65C02),
+	Rockwell65C02: dispatchTable(OpcodesRockwell65C02),
+	WDC65C02:      dispatchTable(OpcodesWDC65C02),
+}
+
+func dispatchTable(opcodes map[uint8]Opcode) *[256]dispatch {
+	var table [256]dispatch
+	for code, opc := range opcodes {
+		table[code] = dispatch{known: true, operation: opc.Operation, program: opc.program}
+	}
+	return &table
+}
+
+func (v Variant) dispatch() *[256]dispatch {
+	if v < 0 || int(v) >= len(dispatchTables) {
+		return dispatchTables[NMOS6502]
+	}
+	return dispatchTables[v]
+}
+
+// Opcodes returns the opcode table decoded by the variant. The cpu reads
+// the tables once at start up, changing them has no effect on execution.
+func (v Variant) Opcodes() map[uint8]Opcode {
+	switch v {
+	case CMOS65C02:
+		return Opcodes65C02
+	case Rockwell65C02:
+		return OpcodesRockwell65C02
+	case WDC65C02:
+		return OpcodesWDC65C02
+	default:
+		return Opcodes
+	}
+}
+
+// Documented tells whether code is one of the 151 opcodes of the original
+// datasheet.
+func Documented(code uint8) bool {
+	_, ok := officialOpcodes[code]
+	return ok
+}
+
+func (v Variant) IsCmos() bool {
+	return v == CMOS65C02 || v == Rockwell65C02 || v == WDC65C02
+}
+
+func (v Variant) HasDecimal() bool {
+	return v != Ricoh2A03
+}
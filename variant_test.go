@@ -0,0 +1,218 @@
+package go6502
+
+import (
+	"testing"
+
+	"github.com/zehlt/go6502/asrt"
+)
+
+func TestVariantOpcodesCoverEveryByte(t *testing.T) {
+	for _, variant := range []Variant{NMOS6502, Ricoh2A03, CMOS65C02, Rockwell65C02, WDC65C02} {
+		table := variant.Opcodes()
+		for code := 0; code < 0x100; code++ {
+			opc, ok := table[uint8(code)]
+			asrt.True(t, ok)
+			asrt.Equal(t, opc.Code, uint8(code))
+		}
+	}
+}
+
+func TestJmpIndirectPageWrapBug(t *testing.T) {
+	memory := Mem{
+		JMP_IND, 0xFF, 0x02,
+	}
+	memory[0x02FF] = 0x34
+	memory[0x0300] = 0x12
+	memory[0x0200] = 0x56
+
+	cpu := Cpu{}
+	cpu.Step(BusEx{&memory})
+
+	asrt.Equal(t, cpu.ProgramCounter, Register16(0x5634))
+
+	cpu = Cpu{Variant: CMOS65C02}
+	cpu.Step(BusEx{&memory})
+
+	asrt.Equal(t, cpu.ProgramCounter, Register16(0x1234))
+	asrt.Equal(t, cpu.Cycle, Opcodes65C02[JMP_IND].Cycles)
+}
+
+func TestCmosStzAbsolute(t *testing.T) {
+	memory := Mem{
+		STZ_ABS, 0x00, 0x02, BRK_IMP,
+	}
+	memory[0x0200] = 0xFF
+
+	cpu := Cpu{StopWhen: StopOnOpcode(BRK_IMP), Variant: CMOS65C02}
+	cpu.Run(BusEx{&memory})
+
+	asrt.Equal(t, memory[0x0200], uint8(0x00))
+	asrt.Equal(t, cpu.Cycle, Opcodes65C02[STZ_ABS].Cycles)
+}
+
+func TestCmosBraRelative(t *testing.T) {
+	memory := Mem{
+		BRA_REL, 0x02, BRK_IMP, BRK_IMP, INX_IMP, BRK_IMP,
+	}
+
+	cpu := Cpu{StopWhen: StopOnOpcode(BRK_IMP), Variant: CMOS65C02}
+	cpu.Run(BusEx{&memory})
+
+	asrt.Equal(t, cpu.ProgramCounter, Register16(0x0005))
+	asrt.Equal(t, cpu.XIndex, Register8(0x01))
+}
+
+func TestCmosPushAndPullIndexes(t *testing.T) {
+	memory := Mem{
+		PHX_IMP, PLY_IMP, BRK_IMP,
+	}
+
+	cpu := Cpu{StopWhen: StopOnOpcode(BRK_IMP), Variant: CMOS65C02}
+	cpu.StackPointer = 0xFF
+	cpu.XIndex = 0x80
+	cpu.Run(BusEx{&memory})
+
+	asrt.Equal(t, cpu.YIndex, Register8(0x80))
+	asrt.Equal(t, cpu.StackPointer, Register8(0xFF))
+	asrt.True(t, cpu.Status.Has(Negative))
+}
+
+func TestCmosTsbAndTrbZeroPage(t *testing.T) {
+	memory := Mem{
+		TSB_ZER, 0x40, TRB_ZER, 0x41, BRK_IMP,
+	}
+	memory[0x40] = 0b0000_1111
+	memory[0x41] = 0b1111_0000
+
+	cpu := Cpu{StopWhen: StopOnOpcode(BRK_IMP), Variant: CMOS65C02}
+	cpu.Accumulator = 0b0011_0000
+	cpu.Run(BusEx{&memory})
+
+	asrt.Equal(t, memory[0x40], uint8(0b0011_1111))
+	asrt.Equal(t, memory[0x41], uint8(0b1100_0000))
+	asrt.False(t, cpu.Status.Has(Zero))
+}
+
+func TestCmosLdaIndirectZeroPage(t *testing.T) {
+	memory := Mem{
+		LDA_IZP, 0x40, BRK_IMP,
+	}
+	memory[0x40] = 0x00
+	memory[0x41] = 0x03
+	memory[0x0300] = 0x99
+
+	cpu := Cpu{StopWhen: StopOnOpcode(BRK_IMP), Variant: CMOS65C02}
+	cpu.Run(BusEx{&memory})
+
+	asrt.Equal(t, cpu.Accumulator, Register8(0x99))
+	asrt.Equal(t, cpu.Cycle, Opcodes65C02[LDA_IZP].Cycles)
+}
+
+func TestCmosIncAccumulator(t *testing.T) {
+	memory := Mem{
+		INC_ACC, BRK_IMP,
+	}
+
+	cpu := Cpu{StopWhen: StopOnOpcode(BRK_IMP), Variant: CMOS65C02}
+	cpu.Accumulator = 0xFF
+	cpu.Run(BusEx{&memory})
+
+	asrt.Equal(t, cpu.Accumulator, Register8(0x00))
+	asrt.True(t, cpu.Status.Has(Zero))
+}
+
+func TestCmosUndefinedOpcodesAreNops(t *testing.T) {
+	memory := Mem{
+		0x5C, 0x34, 0x12, 0x03, BRK_IMP,
+	}
+
+	cpu := Cpu{StopWhen: StopOnOpcode(BRK_IMP), Variant: CMOS65C02}
+	cpu.Run(BusEx{&memory})
+
+	asrt.Equal(t, cpu.ProgramCounter, Register16(0x0004))
+	asrt.Equal(t, cpu.Cycle, 8+1)
+}
+
+func TestCmosAdcDecimalFlags(t *testing.T) {
+	memory := Mem{
+		ADC_IMM, 0x01, BRK_IMP,
+	}
+
+	cpu := Cpu{StopWhen: StopOnOpcode(BRK_IMP), Variant: CMOS65C02}
+	cpu.Accumulator = 0x99
+	cpu.Status.Add(Decimal)
+	cpu.Run(BusEx{&memory})
+
+	asrt.Equal(t, cpu.Accumulator, Register8(0x00))
+	asrt.True(t, cpu.Status.Has(Carry))
+	asrt.True(t, cpu.Status.Has(Zero))
+	asrt.False(t, cpu.Status.Has(Negative))
+	asrt.Equal(t, cpu.Cycle, Opcodes65C02[ADC_IMM].Cycles+1)
+}
+
+func TestCmosInterruptClearsDecimal(t *testing.T) {
+	memory := Mem{}
+	memory[NmiVector+1] = 0x80
+
+	cpu := Cpu{Variant: CMOS65C02}
+	cpu.StackPointer = 0xFF
+	cpu.Status.Add(Decimal)
+	cpu.SetNMI(true)
+	cpu.Step(BusEx{&memory})
+
+	asrt.False(t, cpu.Status.Has(Decimal))
+	asrt.Equal(t, memory[0x01FD]&Decimal, uint8(Decimal))
+}
+
+func TestRockwellBitInstructions(t *testing.T) {
+	memory := Mem{
+		SMB3_ZER, 0x40, RMB0_ZER, 0x40, BBS3_ZRL, 0x40, 0x01, BRK_IMP, INX_IMP, BBR0_ZRL, 0x40, 0xFB,
+	}
+	memory[0x40] = 0b0000_0001
+
+	cpu := Cpu{StopWhen: StopOnOpcode(BRK_IMP), Variant: Rockwell65C02}
+	cpu.Run(BusEx{&memory})
+
+	// BBR loops back to the BRK right after BBS
+	asrt.Equal(t, memory[0x40], uint8(0b0000_1000))
+	asrt.Equal(t, cpu.XIndex, Register8(0x01))
+	asrt.Equal(t, cpu.ProgramCounter, Register16(0x0007))
+}
+
+func TestWdcWaitResumesOnInterrupt(t *testing.T) {
+	memory := Mem{
+		WAI_IMP, INX_IMP,
+	}
+
+	cpu := Cpu{Variant: WDC65C02}
+	cpu.Status.Add(Interrupt)
+	cpu.Step(BusEx{&memory})
+	cpu.Step(BusEx{&memory})
+	cpu.Step(BusEx{&memory})
+
+	asrt.Equal(t, cpu.ProgramCounter, Register16(0x0001))
+
+	cpu.SetIRQ(true)
+	cpu.Step(BusEx{&memory})
+
+	asrt.Equal(t, cpu.ProgramCounter, Register16(0x0002))
+	asrt.Equal(t, cpu.XIndex, Register8(0x01))
+}
+
+func TestWdcStopHaltsUntilReset(t *testing.T) {
+	memory := Mem{
+		STP_IMP, INX_IMP,
+	}
+
+	cpu := Cpu{Variant: WDC65C02}
+	cpu.Step(BusEx{&memory})
+	cpu.Step(BusEx{&memory})
+
+	asrt.Equal(t, cpu.ProgramCounter, Register16(0x0001))
+	asrt.Equal(t, cpu.XIndex, Register8(0x00))
+
+	cpu.Reset(BusEx{&memory})
+	cpu.Step(BusEx{&memory})
+
+	asrt.Equal(t, cpu.ProgramCounter, Register16(0x0001))
+}